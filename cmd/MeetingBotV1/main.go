@@ -6,6 +6,7 @@ import (
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
 	db "github.com/leegeev/KomaevBookingBot/internal/infrastructure"
 	repository "github.com/leegeev/KomaevBookingBot/internal/repository/postgres"
 	"github.com/leegeev/KomaevBookingBot/internal/usecase"
@@ -33,7 +34,7 @@ func main() {
 		logger.Error("Failed to load configuration", "error", err)
 		return
 	}
-	logger.Info("Configuration loaded successfully", "config", config)
+	logger.Info("Configuration loaded successfully", "config", config.Redacted())
 
 	// Подключение к БД
 	db, err := db.ConnectDBWithRetry(ctx, config.DB, logger)
@@ -43,14 +44,53 @@ func main() {
 	}
 	defer db.Close()
 
+	// meetingbot migrate status|up|down N — только миграции, без запуска бота
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		code := runMigrate(ctx, db, os.Args[2:], logger)
+		db.Close()
+		os.Exit(code)
+	}
+
+	// Схема БД
+	if err := migrateOnStart(ctx, db, config.DB, logger); err != nil {
+		logger.Error("Database schema is not ready", "error", err)
+		return
+	}
+
 	// Инициализация репозиториев
 	roomRepo := repository.NewRoomRepositoryPG(db, logger)
 	bookingRepo := repository.NewBookingRepositoryPG(db, logger)
 	logRepo := repository.NewLogRepositoryPG(db, logger)
+	clientRepo := repository.NewClientRepositoryPG(db, logger)
+	auditRepo := repository.NewAuditRepositoryPG(db, logger)
+	settingsRepo := repository.NewUserSettingsRepositoryPG(db, logger)
+	stateRepo := repository.NewBotStateRepositoryPG(db, logger)
+	sessionRepo := repository.NewSessionRepositoryPG(db, logger)
+	usernameRepo := repository.NewUsernameRepositoryPG(db, logger)
+
+	// Политика бронирования
+	policy, err := usecase.NewBookingPolicy(config.Booking)
+	if err != nil {
+		logger.Error("Failed to load booking policy", "error", err)
+		return
+	}
+
+	// Формат номеров записей журналов
+	numFmt, err := domain.NewLogNumberFormat(config.Telegram.LogNumberFormat, config.Telegram.LogNumberWidth)
+	if err != nil {
+		logger.Error("Invalid log number format", "error", err)
+		return
+	}
 
 	// Инициализация сервиса
-	service := usecase.NewBookingService(roomRepo, bookingRepo, logger, config.Telegram)
-	logService := usecase.NewLogService(logRepo, logger, config.Telegram)
+	service := usecase.NewBookingService(roomRepo, bookingRepo, auditRepo, settingsRepo, usernameRepo, logger, config.Telegram, policy)
+	logService := usecase.NewLogService(logRepo, clientRepo, logger, config.Telegram, numFmt)
+	if err := logService.BackfillNumbers(ctx); err != nil {
+		logger.Error("Failed to number existing log records", "error", err)
+		return
+	}
+	stateService := usecase.NewBotStateService(stateRepo, logger, config.Telegram)
+	sessionService := usecase.NewSessionService(sessionRepo, logger, config.Telegram)
 
 	// TG BOT
 	bot, err := tgbotapi.NewBotAPI(config.Telegram.Token)
@@ -58,12 +98,12 @@ func main() {
 		logger.Error("Failed to init Telegram bot", "error", err)
 		return
 	}
-	h := telegram.NewHandler(bot, config.Telegram, logger, service, logService)
+	h := telegram.NewHandler(bot, config.Telegram, logger, service, logService, stateService, sessionService)
 	g, ctx := errgroup.WithContext(ctx)
 
 	g.Go(func() error {
-		logger.Info("Telegram bot starting...")
-		if err := h.RunPolling(ctx); err != nil {
+		logger.Info("Telegram bot starting...", "mode", config.Telegram.Mode)
+		if err := runBot(ctx, bot, h, config.Telegram, logger); err != nil {
 			logger.Error("bot stopped", "error", err)
 		}
 		logger.Info("Telegram bot stopped")
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/jmoiron/sqlx"
+	db "github.com/leegeev/KomaevBookingBot/internal/infrastructure"
+	"github.com/leegeev/KomaevBookingBot/pkg/config"
+	"github.com/leegeev/KomaevBookingBot/pkg/logger"
+	"github.com/leegeev/KomaevBookingBot/scripts"
+)
+
+const migrateUsage = `usage: meetingbot migrate <command>
+  status      список миграций и отметка о применении
+  up          применить все неприменённые миграции
+  down <N>    откатить миграции новее версии N (0 — откатить всё)`
+
+// migrateOnStart приводит схему к версии бинаря перед запуском бота.
+// При database.auto_migrate: false миграции не применяются, а бот не
+// стартует, пока схема не совпадёт (см. команду migrate).
+func migrateOnStart(ctx context.Context, conn *sqlx.DB, cfg config.DB, logger logger.Logger) error {
+	m, err := db.NewMigrator(conn, scripts.Migrations, logger)
+	if err != nil {
+		return err
+	}
+	if !cfg.AutoMigrate {
+		return m.Check(ctx)
+	}
+
+	n, err := m.Up(ctx)
+	if err != nil {
+		return err
+	}
+	logger.Info("Database schema is up to date", "version", m.Latest(), "applied", n)
+	return nil
+}
+
+// runMigrate выполняет `meetingbot migrate ...` и возвращает код выхода.
+func runMigrate(ctx context.Context, conn *sqlx.DB, args []string, logger logger.Logger) int {
+	m, err := db.NewMigrator(conn, scripts.Migrations, logger)
+	if err != nil {
+		logger.Error("Failed to load migrations", "error", err)
+		return 1
+	}
+
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	switch args[0] {
+	case "status":
+		list, err := m.Status(ctx)
+		if err != nil {
+			logger.Error("Failed to get migration status", "error", err)
+			return 1
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range list {
+			name, at := s.Name, "—"
+			if s.Up == "" {
+				name = "(нет в этой версии бота)"
+			}
+			if s.Applied() {
+				at = s.AppliedAt.Format("2006-01-02 15:04:05 MST")
+			}
+			fmt.Fprintf(w, "%03d\t%s\t%s\n", s.Version, name, at)
+		}
+		w.Flush()
+
+	case "up":
+		n, err := m.Up(ctx)
+		if err != nil {
+			logger.Error("Migration up failed", "error", err)
+			return 1
+		}
+		fmt.Printf("applied %d migration(s), schema version %d\n", n, m.Latest())
+
+	case "down":
+		if len(args) < 2 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		target, err := strconv.Atoi(args[1])
+		if err != nil || target < 0 {
+			fmt.Fprintln(os.Stderr, migrateUsage)
+			return 2
+		}
+		n, err := m.DownTo(ctx, target)
+		if err != nil {
+			logger.Error("Migration down failed", "error", err)
+			return 1
+		}
+		fmt.Printf("reverted %d migration(s), schema version %d\n", n, target)
+
+	default:
+		fmt.Fprintln(os.Stderr, migrateUsage)
+		return 2
+	}
+	return 0
+}
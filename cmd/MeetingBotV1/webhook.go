@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram"
+	"github.com/leegeev/KomaevBookingBot/pkg/config"
+	"github.com/leegeev/KomaevBookingBot/pkg/logger"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	defaultWebhookListen = ":8080"
+	defaultWebhookPath   = "/telegram/webhook"
+	// Сколько ждать завершения HTTP-запросов при остановке сервера
+	webhookShutdownTimeout = 5 * time.Second
+)
+
+// Требование Telegram к secret_token
+var webhookSecretRe = regexp.MustCompile(`^[A-Za-z0-9_-]{1,256}$`)
+
+// runBot получает апдейты long polling'ом или через вебхук (telegram.mode).
+func runBot(ctx context.Context, bot *tgbotapi.BotAPI, h *telegram.Handler, cfg config.Telegram, logger logger.Logger) error {
+	if cfg.Mode == config.ModeWebhook {
+		return runWebhook(ctx, bot, h, cfg.Webhook, logger)
+	}
+	return h.RunPolling(ctx)
+}
+
+// runWebhook — режим webhook: HTTP-сервер принимает апдейты и отдаёт их в
+// тот же конвейер, что и long polling. При старте вебхук регистрируется в
+// Telegram (setWebhook), при остановке снимается (deleteWebhook), и
+// апдейты копятся у Telegram до следующего запуска.
+func runWebhook(ctx context.Context, bot *tgbotapi.BotAPI, h *telegram.Handler, cfg config.Webhook, logger logger.Logger) error {
+	path, err := webhookPath(cfg)
+	if err != nil {
+		return err
+	}
+	if !webhookSecretRe.MatchString(cfg.SecretToken) {
+		return errors.New("telegram.webhook.secret_token: нужно 1-256 символов A-Z, a-z, 0-9, _ и -")
+	}
+	if (cfg.CertFile == "") != (cfg.KeyFile == "") {
+		return errors.New("telegram.webhook: cert_file и key_file задаются вместе")
+	}
+	if cfg.UploadCert && cfg.CertFile == "" {
+		return errors.New("telegram.webhook.upload_cert: не задан cert_file")
+	}
+	listen := cfg.Listen
+	if listen == "" {
+		listen = defaultWebhookListen
+	}
+
+	wh := telegram.NewWebhook(cfg.SecretToken, logger)
+	mux := http.NewServeMux()
+	mux.Handle(path, wh)
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	// Слушаем порт до setWebhook, чтобы Telegram не ходил в пустоту
+	ln, err := net.Listen("tcp", listen)
+	if err != nil {
+		return fmt.Errorf("listen %s: %w", listen, err)
+	}
+	tls := cfg.CertFile != ""
+	logger.Info("Webhook server listening", "addr", ln.Addr().String(), "path", path, "tls", tls)
+
+	if cfg.URL == "" {
+		logger.Warn("telegram.webhook.url is empty: webhook is not registered in Telegram, only local POST requests will arrive")
+	} else {
+		if err := setWebhook(bot, cfg); err != nil {
+			ln.Close()
+			return err
+		}
+		logger.Info("Webhook registered", "url", cfg.URL)
+		defer deleteWebhook(bot, logger)
+	}
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.Go(func() error {
+		var err error
+		if tls {
+			err = srv.ServeTLS(ln, cfg.CertFile, cfg.KeyFile)
+		} else {
+			err = srv.Serve(ln)
+		}
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("webhook server: %w", err)
+	})
+	g.Go(func() error {
+		err := h.Serve(gctx, wh)
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), webhookShutdownTimeout)
+		defer cancel()
+		if e := srv.Shutdown(shutdownCtx); e != nil {
+			logger.Error("Failed to shut down webhook server", "error", e)
+		}
+		return err
+	})
+	return g.Wait()
+}
+
+// webhookPath — путь эндпоинта: явный path, иначе путь из url.
+func webhookPath(cfg config.Webhook) (string, error) {
+	if cfg.Path != "" {
+		return cfg.Path, nil
+	}
+	if cfg.URL == "" {
+		return defaultWebhookPath, nil
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return "", fmt.Errorf("telegram.webhook.url: нужен https-адрес, получено %q", cfg.URL)
+	}
+	if u.Path == "" || u.Path == "/" {
+		return "/", nil
+	}
+	return u.Path, nil
+}
+
+// setWebhook регистрирует вебхук. В tgbotapi v5 нет secret_token, поэтому
+// запрос собирается вручную.
+func setWebhook(bot *tgbotapi.BotAPI, cfg config.Webhook) error {
+	params := tgbotapi.Params{
+		"url":          cfg.URL,
+		"secret_token": cfg.SecretToken,
+	}
+	params.AddNonZero("max_connections", cfg.MaxConnections)
+	params.AddBool("drop_pending_updates", cfg.DropPending)
+	if err := params.AddInterface("allowed_updates", telegram.AllowedUpdates); err != nil {
+		return fmt.Errorf("setWebhook: %w", err)
+	}
+
+	var err error
+	if cfg.UploadCert {
+		_, err = bot.UploadFiles("setWebhook", params, []tgbotapi.RequestFile{{
+			Name: "certificate",
+			Data: tgbotapi.FilePath(cfg.CertFile),
+		}})
+	} else {
+		_, err = bot.MakeRequest("setWebhook", params)
+	}
+	if err != nil {
+		return fmt.Errorf("setWebhook: %w", err)
+	}
+	return nil
+}
+
+func deleteWebhook(bot *tgbotapi.BotAPI, logger logger.Logger) {
+	if _, err := bot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		logger.Error("Failed to delete webhook", "error", err)
+		return
+	}
+	logger.Info("Webhook deleted")
+}
@@ -0,0 +1,251 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/internal/usecase"
+)
+
+/* ---------- /cancel_booking — отмена чужой брони администратором ---------- */
+
+const maxAdminCancelBookings = 30
+
+func (h *Handler) handleAdminCancel(ctx context.Context, msg *tgbotapi.Message) {
+	if err := ctx.Err(); err != nil {
+		h.log.Warn("Context canceled in handleAdminCancel handler",
+			"user", msg.From.UserName,
+			"chat_id", msg.Chat.ID,
+			"err", ctx.Err())
+		return
+	}
+
+	if !h.actor(msg.From.ID).IsAdmin() {
+		h.reply(msg.Chat.ID, "Недостаточно прав.")
+		return
+	}
+
+	rooms, err := h.uc.ListRooms(ctx)
+	if errors.Is(err, domain.ErrNoRoomsAvailable) {
+		h.reply(msg.Chat.ID, string(tools.TextBookNoRoomsAvailable))
+		return
+	} else if err != nil {
+		h.log.Error("Failed to list rooms", "user_id", msg.From.ID, "error", err)
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при /cancel_booking:* `%s`", err.Error()))
+		h.reply(msg.Chat.ID, string(tools.TextBookNoRoomsErr))
+		return
+	}
+
+	m := tgbotapi.NewMessage(msg.Chat.ID, tools.TextAdminCancelIntro.String())
+	m.ParseMode = "MarkdownV2"
+	m.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tools.BuildRoomListKB(rooms, "admin_cancel")...)
+
+	if _, err := h.bot.Send(m); err != nil {
+		h.log.Error("Failed to send rooms list on /cancel_booking", "err", err)
+	}
+}
+
+// admin_cancel:list:<roomID> — ближайшие брони выбранной переговорки.
+func (h *Handler) handleAdminCancelList(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("handleAdminCancelList", "data", cq.Data, "user", cq.From.UserName)
+
+	if !h.actor(cq.From.ID).IsAdmin() {
+		h.answerWarning(tools.TextMyCancelForbidden.String(), cq)
+		return
+	}
+
+	parts := strings.Split(cq.Data, ":")
+	roomID, _ := strconv.ParseInt(parts[2], 10, 64)
+
+	room, err := h.uc.GetRoom(ctx, roomID)
+	if err != nil {
+		h.reply(cq.Message.Chat.ID, "Ошибка: не удалось получить переговорку.")
+		return
+	}
+
+	bks, err := h.uc.ListRoomUpcoming(ctx, roomID)
+	if err != nil {
+		h.log.Error("Failed to list room bookings", "room_id", roomID, "error", err)
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при /cancel_booking:* `%s`", err.Error()))
+		h.answerWarning(tools.TextAdminCancelErr.String(), cq)
+		return
+	}
+	if len(bks) > maxAdminCancelBookings {
+		bks = bks[:maxAdminCancelBookings]
+	}
+
+	text := tools.SafeText(fmt.Sprintf(string(tools.TextAdminCancelBookings), room.Name))
+	if len(bks) == 0 {
+		text = tools.SafeText(fmt.Sprintf(string(tools.TextAdminCancelNoBookings), room.Name))
+	}
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		text.String(),
+		tools.BuildAdminCancelListKB(bks),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on admin cancel list", "err", err)
+		}
+	}()
+}
+
+// admin_cancel:pick:<bookingID> — запоминаем бронь и ждём причину текстом.
+func (h *Handler) handleAdminCancelPick(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("handleAdminCancelPick", "data", cq.Data, "user", cq.From.UserName)
+
+	if !h.actor(cq.From.ID).IsAdmin() {
+		h.answerWarning(tools.TextMyCancelForbidden.String(), cq)
+		return
+	}
+
+	parts := strings.Split(cq.Data, ":")
+	id, _ := strconv.ParseInt(parts[2], 10, 64)
+
+	bk, err := h.uc.GetById(ctx, id)
+	if errors.Is(err, domain.ErrBookingNotFound) {
+		h.answerWarning(tools.TextAdminCancelNotFound.String(), cq)
+		return
+	} else if err != nil {
+		h.log.Error("Failed to get booking for admin cancel", "err", err, "user_id", cq.From.ID, "bk_id", id)
+		h.answerWarning(tools.TextAdminCancelErr.String(), cq)
+		return
+	}
+
+	h.goTo(ctx, cq.From.ID, stepAdminCancelReason)
+	h.sessions.Set(ctx, &tools.BookingSession{
+		ChatID:    cq.Message.Chat.ID,
+		UserID:    cq.From.ID,
+		UserName:  displayName(cq.From),
+		MessageID: cq.Message.MessageID,
+		RoomID:    bk.RoomID,
+		RoomName:  bk.RoomName,
+		CancelID:  bk.ID,
+	})
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.BuildAdminCancelAskReasonStr(bk).String(),
+		tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+			tools.BuildBackInlineKBButton(fmt.Sprintf("admin_cancel:reason_back:%d", bk.RoomID)),
+		)),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on admin cancel pick", "err", err)
+		}
+	}()
+}
+
+// Причина отмены введена текстом — отменяем бронь и уведомляем владельца.
+func (h *Handler) handleAdminCancelReason(ctx context.Context, msg *tgbotapi.Message) {
+	session := h.sessions.Get(ctx, msg.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, msg.Chat.ID, msg.From.ID, domain.SessionBooking)
+		return
+	}
+
+	reason := strings.TrimSpace(msg.Text)
+	if len([]rune(reason)) < 3 {
+		h.reply(msg.Chat.ID, string(tools.TextAdminCancelReasonShort))
+		return
+	}
+	if len([]rune(reason)) > 500 {
+		h.reply(msg.Chat.ID, string(tools.TextAdminCancelReasonLong))
+		return
+	}
+
+	// Убираем кнопку "Назад" у сообщения с запросом причины
+	h.sessions.Delete(ctx, msg.From.ID)
+	h.endFlow(ctx, msg.From.ID)
+	go func() {
+		edit := tgbotapi.NewEditMessageReplyMarkup(session.ChatID, session.MessageID, tools.BuildBlankInlineKB())
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Warn("Failed to remove keyboard on admin cancel reason", "err", err)
+		}
+	}()
+
+	bk, err := h.uc.AdminCancelBooking(ctx, usecase.AdminCancelCmd{
+		BookingID: session.CancelID,
+		Actor:     h.actor(msg.From.ID),
+		ActorName: displayName(msg.From),
+		Reason:    reason,
+	})
+	switch {
+	case errors.Is(err, domain.ErrForbiddenCancellation):
+		h.reply(msg.Chat.ID, "Недостаточно прав.")
+		return
+	case errors.Is(err, domain.ErrBookingNotFound):
+		h.reply(msg.Chat.ID, string(tools.TextAdminCancelNotFound))
+		return
+	case err != nil:
+		h.log.Error("Failed to cancel booking by admin", "user_id", msg.From.ID, "bk_id", session.CancelID, "error", err)
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при /cancel_booking:* `%s`", err.Error()))
+		h.reply(msg.Chat.ID, string(tools.TextAdminCancelErr))
+		return
+	}
+
+	go h.wake()
+	h.notifyAttendees(bk.Attendees, bk.UserID, tools.BuildAttendeeCancelledStr(bk, 0))
+
+	// Личное сообщение владельцу. Не дойдёт, если владелец не начинал диалог с ботом.
+	notice := tgbotapi.NewMessage(int64(bk.UserID), tools.BuildAdminCancelNoticeStr(bk, reason).String())
+	notice.ParseMode = "MarkdownV2"
+	go func() {
+		done := tools.TextAdminCancelDone
+		if _, err := h.bot.Send(notice); err != nil {
+			h.log.Warn("Failed to notify booking owner", "owner_id", bk.UserID, "err", err)
+			done = tools.TextAdminCancelDoneNoDM
+		}
+		h.reply(msg.Chat.ID, string(done))
+	}()
+}
+
+// admin_cancel:reason_back:<roomID> — передумали отменять, возвращаемся к списку броней.
+func (h *Handler) handleAdminCancelReasonBack(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.sessions.Delete(ctx, cq.From.ID)
+	h.endFlow(ctx, cq.From.ID)
+	h.handleAdminCancelList(ctx, cq)
+}
+
+// admin_cancel:pick_back — назад к списку переговорок.
+func (h *Handler) handleAdminCancelPickBack(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	rooms, err := h.uc.ListRooms(ctx)
+	if errors.Is(err, domain.ErrNoRoomsAvailable) {
+		h.reply(cq.Message.Chat.ID, string(tools.TextBookNoRoomsAvailable))
+		return
+	} else if err != nil {
+		h.log.Error("Failed to list rooms", "user_id", cq.From.ID, "error", err)
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при /cancel_booking:* `%s`", err.Error()))
+		h.reply(cq.Message.Chat.ID, string(tools.TextBookNoRoomsErr))
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.TextAdminCancelIntro.String(),
+		tgbotapi.NewInlineKeyboardMarkup(tools.BuildRoomListKB(rooms, "admin_cancel")...),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on admin cancel back", "err", err)
+		}
+	}()
+}
@@ -25,6 +25,9 @@ func (h *Handler) handleBook(ctx context.Context, msg *tgbotapi.Message) {
 		return
 	}
 
+	// Новая бронь: прерываем незавершённый диалог (например, брошенный перенос)
+	h.cancelFlow(ctx, msg.From.ID)
+
 	rooms, err := h.uc.ListRooms(ctx)
 	if errors.Is(err, domain.ErrNoRoomsAvailable) {
 		h.reply(msg.From.ID, tools.TextBookNoRoomsAvailable.String())
@@ -72,29 +75,25 @@ func (h *Handler) handleBookList(ctx context.Context, cq *tgbotapi.CallbackQuery
 		return
 	}
 
-	var userName string
-	switch {
-	case cq.From.UserName != "":
-		// Есть никнейм → используем его
-		userName = "@" + cq.From.UserName
-	case cq.From.LastName != "":
-		// Нет ника, но есть имя + фамилия
-		userName = cq.From.FirstName + " " + cq.From.LastName
-	default:
-		// Остался минимум: только имя
-		userName = cq.From.FirstName
+	userName := displayName(cq.From)
+
+	// При переносе брони сессия уже есть — сохраняем ID переносимой брони
+	var rescheduleID domain.BookingID
+	if prev := h.sessions.Get(ctx, cq.From.ID); prev != nil {
+		rescheduleID = prev.RescheduleID
 	}
 
 	// Создаем bookingSession и сохраняем в in-memory storage
-	h.sessions.Set(&tools.BookingSession{
-		BookState: tools.BookStateChoosingDate,
-		ChatID:    cq.Message.Chat.ID,
-		UserID:    cq.From.ID,
-		UserName:  userName,
-		MessageID: cq.Message.MessageID,
-		RoomID:    room.ID,
-		RoomName:  room.Name,
-		Date:      time.Now().In(h.cfg.OfficeTZ).Truncate(24 * time.Hour),
+	h.goTo(ctx, cq.From.ID, stepBookDate)
+	h.sessions.Set(ctx, &tools.BookingSession{
+		ChatID:       cq.Message.Chat.ID,
+		UserID:       cq.From.ID,
+		UserName:     userName,
+		MessageID:    cq.Message.MessageID,
+		RoomID:       room.ID,
+		RoomName:     room.Name,
+		Date:         time.Now().In(h.cfg.OfficeTZ).Truncate(24 * time.Hour),
+		RescheduleID: rescheduleID,
 	})
 
 	edit := tgbotapi.NewEditMessageTextAndMarkup(
@@ -153,23 +152,21 @@ func (h *Handler) handleBookCalendar(ctx context.Context, cq *tgbotapi.CallbackQ
 		return
 	}
 
-	session := h.sessions.Get(cq.From.ID)
+	session := h.sessions.Get(ctx, cq.From.ID)
 	if session == nil {
-		h.reply(cq.Message.Chat.ID, "Сессия не найдена")
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionBooking)
 		return
 	}
 
 	session.Date = date
-	session.BookState = tools.BookStateChoosingStartTime
+	h.goTo(ctx, session.UserID, stepBookStartTime)
 
+	text, kb := h.buildTimePick(ctx, session)
 	edit := tgbotapi.NewEditMessageTextAndMarkup(
 		cq.Message.Chat.ID,
 		cq.Message.MessageID,
-		tools.TextBookAskTimeInput.String(),
-		tgbotapi.NewInlineKeyboardMarkup(
-			[]tgbotapi.InlineKeyboardButton{
-				tools.BuildBackInlineKBButton("book:timepick_back"),
-			}),
+		text,
+		kb,
 	)
 
 	edit.ParseMode = "MarkdownV2"
@@ -180,6 +177,60 @@ func (h *Handler) handleBookCalendar(ctx context.Context, cq *tgbotapi.CallbackQ
 	}()
 }
 
+// Step 1.1
+// Текст и клавиатура выбора времени: свободные начала брони кнопками + ручной ввод.
+func (h *Handler) buildTimePick(ctx context.Context, session *tools.BookingSession) (string, tgbotapi.InlineKeyboardMarkup) {
+	step := h.uc.Policy().Step
+	free, err := h.uc.FreeSlots(ctx, session.RoomID, session.Date, step)
+	if err != nil {
+		// Без подсказок остаётся ручной ввод
+		h.log.Error("Failed to get free slots", "err", err, "room_id", session.RoomID, "date", session.Date)
+		return tools.TextBookAskTimeInput.String(), tools.BuildTimePickKB(nil)
+	}
+
+	starts := domain.SlotStarts(free, step, step)
+	if len(starts) == 0 {
+		return tools.TextBookNoFreeSlots.String(), tools.BuildTimePickKB(nil)
+	}
+	return tools.TextBookAskTimePick.String(), tools.BuildTimePickKB(starts)
+}
+
+// Step 2.
+// Обработчик выбора времени кнопкой.
+func (h *Handler) handleBookTimepickButton(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	parts := strings.Split(cq.Data, ":")
+	picked, err := time.Parse("1504", parts[2])
+	if err != nil {
+		h.reply(cq.Message.Chat.ID, "Ошибка: неправильное время")
+		return
+	}
+
+	session := h.sessions.Get(ctx, cq.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionBooking)
+		return
+	}
+	h.goTo(ctx, session.UserID, stepBookDuration)
+	session.StartTime = time.Date(session.Date.Year(), session.Date.Month(), session.Date.Day(),
+		picked.Hour(), picked.Minute(), 0, 0, h.cfg.OfficeTZ)
+	session.MessageID = cq.Message.MessageID
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.TextBookAskDuration.String(),
+		tools.BuildDurationKB(h.uc.Policy()),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on timepick button", "err", err)
+		}
+	}()
+}
+
 // Step 2.
 // Обработчик РУЧНОГО ввода времени.
 func (h *Handler) handleBookTimepick(ctx context.Context, msg *tgbotapi.Message) {
@@ -194,29 +245,27 @@ func (h *Handler) handleBookTimepick(ctx context.Context, msg *tgbotapi.Message)
 		return
 	}
 
-	session := h.sessions.Get(msg.From.ID)
+	session := h.sessions.Get(ctx, msg.From.ID)
 	if session == nil {
-		h.reply(msg.From.ID, "Сессия не найдена")
+		h.sessionLost(ctx, msg.From.ID, msg.From.ID, domain.SessionBooking)
 		return
 	}
-	session.BookState = tools.BookStateChoosingDuration
+	h.goTo(ctx, session.UserID, stepBookDuration)
 	session.StartTime = startTime
 
-	edit := tgbotapi.NewEditMessageTextAndMarkup(
+	edit := tgbotapi.NewEditMessageReplyMarkup(
 		msg.Chat.ID,
 		session.MessageID,
-		tools.TextBookAskTimeInput.String(),
 		tools.BuildBlankInlineKB(),
 	)
 
-	edit.ParseMode = "MarkdownV2"
 	if _, err := h.bot.Send(edit); err != nil {
 		h.log.Error("Failed to edit message on timepick", "err", err)
 	}
 
 	newMsg := tgbotapi.NewMessage(msg.Chat.ID, tools.TextBookAskDuration.String())
 	newMsg.ParseMode = "MarkdownV2"
-	newMsg.ReplyMarkup = tools.BuildDurationKB()
+	newMsg.ReplyMarkup = tools.BuildDurationKB(h.uc.Policy())
 	go func() {
 		sentMsg, err := h.bot.Send(newMsg)
 		if err != nil {
@@ -234,25 +283,178 @@ func (h *Handler) handleBookDuration(ctx context.Context, cq *tgbotapi.CallbackQ
 	h.answerCB(cq, "")
 
 	parts := strings.Split(cq.Data, ":")
-	durStr := parts[2]
-	// (0.5, 1.5, 2.5, 3.5)
-	// (1.0, 2.0, 3.0, 4.0)
+	// длительность в минутах
+	mins, err := strconv.Atoi(parts[2])
+	if err != nil || mins <= 0 {
+		h.reply(cq.Message.Chat.ID, "Ошибка: неправильная длительность")
+		return
+	}
+
+	session := h.sessions.Get(ctx, cq.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionBooking)
+		return
+	}
+
+	session.Duration = time.Duration(mins) * time.Minute
+	h.goTo(ctx, session.UserID, stepBookRecurrence)
+	session.MessageID = cq.Message.MessageID
+
+	// Перенос меняет одну бронь — шаг повтора пропускаем
+	if session.RescheduleID != 0 {
+		h.showBookConfirmation(ctx, cq, session)
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.TextBookAskRecurrence.String(),
+		tools.BuildRecurrenceKB(),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on duration", "err", err)
+		}
+	}()
+}
+
+// Step 3.
+// Выбор правила повторения. "none" — разовая бронь, сразу к подтверждению.
+func (h *Handler) handleBookRecurrence(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	parts := strings.Split(cq.Data, ":")
+	freq := domain.RecurrenceFreq(parts[2])
+
+	session := h.sessions.Get(ctx, cq.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionBooking)
+		return
+	}
+	session.MessageID = cq.Message.MessageID
 
-	durF, err := strconv.ParseFloat(durStr, 64)
+	if !freq.Valid() {
+		session.Recurrence = domain.RecurrenceRule{}
+		h.showBookPurpose(ctx, cq, session)
+		return
+	}
+
+	session.Recurrence = domain.RecurrenceRule{Freq: freq}
+	h.goTo(ctx, session.UserID, stepBookRecurrenceEnd)
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.TextBookAskRecurrenceEnd.String(),
+		tools.BuildRecurrenceEndKB(),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on recurrence", "err", err)
+		}
+	}()
+}
+
+// Step 3.1
+// Окончание серии через число повторений.
+func (h *Handler) handleBookRecurrenceCount(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	parts := strings.Split(cq.Data, ":")
+	count, err := strconv.Atoi(parts[2])
+	if err != nil || count <= 0 {
+		h.reply(cq.Message.Chat.ID, "Ошибка: неправильное число повторений")
+		return
+	}
+
+	session := h.sessions.Get(ctx, cq.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionBooking)
+		return
+	}
+
+	session.Recurrence.Count = count
+	session.Recurrence.Until = time.Time{}
+	session.MessageID = cq.Message.MessageID
+	h.showBookPurpose(ctx, cq, session)
+}
+
+// Step 3.1
+// Окончание серии по дате: показываем календарь.
+func (h *Handler) handleBookRecurrenceUntil(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.TextBookUntilCalendar.String(),
+		tools.BuildUntilCalendarKB(0),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on recurrence until", "err", err)
+		}
+	}()
+}
+
+// Step 3.2
+// Дата окончания серии выбрана.
+func (h *Handler) handleBookUntil(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	parts := strings.Split(cq.Data, ":")
+	until, err := time.ParseInLocation("2006-01-02", parts[2], h.cfg.OfficeTZ)
 	if err != nil {
-		h.reply(cq.Message.Chat.ID, "Ошибка: неправильная длительность")
+		h.answerCB(cq, "")
+		h.reply(cq.Message.Chat.ID, "Ошибка: неправильная дата")
 		return
 	}
 
-	session := h.sessions.Get(cq.From.ID)
+	session := h.sessions.Get(ctx, cq.From.ID)
 	if session == nil {
-		h.reply(cq.Message.Chat.ID, "Сессия не найдена")
+		h.answerCB(cq, "")
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionBooking)
 		return
 	}
 
-	session.Duration = time.Duration(durF * float64(time.Hour))
-	session.BookState = tools.BookStateConfirmingBooking
+	if until.Before(session.Date) {
+		h.answerCB(cq, tools.TextBookUntilTooEarly)
+		return
+	}
+	h.answerCB(cq, "")
+
+	session.Recurrence.Until = until
+	session.Recurrence.Count = 0
 	session.MessageID = cq.Message.MessageID
+	h.showBookPurpose(ctx, cq, session)
+}
+
+func (h *Handler) handleBookUntilNavigation(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	parts := strings.Split(cq.Data, ":")
+	shift, _ := strconv.ParseInt(parts[2], 10, 64)
+	if shift < 0 {
+		return
+	}
+
+	editMarkup := tgbotapi.NewEditMessageReplyMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.BuildUntilCalendarKB(shift),
+	)
+	go func() {
+		if _, err := h.bot.Request(editMarkup); err != nil {
+			h.log.Error("failed to edit until calendar inline keyboard", "err", err)
+		}
+	}()
+}
+
+// Редактирует текущее сообщение на подтверждение брони.
+func (h *Handler) showBookConfirmation(ctx context.Context, cq *tgbotapi.CallbackQuery, session *tools.BookingSession) {
+	h.goTo(ctx, session.UserID, stepBookConfirm)
 
 	edit := tgbotapi.NewEditMessageTextAndMarkup(
 		cq.Message.Chat.ID,
@@ -263,7 +465,7 @@ func (h *Handler) handleBookDuration(ctx context.Context, cq *tgbotapi.CallbackQ
 	edit.ParseMode = "MarkdownV2"
 	go func() {
 		if _, err := h.bot.Send(edit); err != nil {
-			h.log.Error("Failed to edit message on duration", "err", err)
+			h.log.Error("Failed to edit message on confirmation", "err", err)
 		}
 	}()
 }
@@ -274,7 +476,7 @@ func (h *Handler) handleBookConfirm(ctx context.Context, cq *tgbotapi.CallbackQu
 	parts := strings.Split(cq.Data, ":")
 	confirm, _ := strconv.ParseInt(parts[2], 10, 64) // 0 || 1
 
-	session := h.sessions.Get(cq.From.ID)
+	session := h.sessions.Get(ctx, cq.From.ID)
 	session.MessageID = cq.Message.MessageID
 	session.StartTime = time.Date(
 		session.Date.Year(),
@@ -294,6 +496,9 @@ func (h *Handler) handleBookConfirm(ctx context.Context, cq *tgbotapi.CallbackQu
 		UserName: session.UserName,
 		Start:    session.StartTime,
 		End:      session.EndTime,
+
+		Note:      session.Note,
+		Attendees: session.Attendees,
 	}
 
 	edit := tgbotapi.NewEditMessageReplyMarkup(
@@ -307,40 +512,83 @@ func (h *Handler) handleBookConfirm(ctx context.Context, cq *tgbotapi.CallbackQu
 	}
 
 	var replyText string
+	var err error
 
 	if confirm == 1 {
-		// Проверка на прошедшее время
-		if cmd.End.Before(time.Now()) {
-			h.answerWarning(tools.TextBookTooLateWaring.String(), cq)
-			return
+		var createdToday bool
+		switch {
+		case session.RescheduleID != 0:
+			err = h.uc.RescheduleBooking(ctx, usecase.RescheduleBookingCmd{
+				BookingID: session.RescheduleID,
+				Actor:     h.actor(session.UserID),
+				RoomID:    session.RoomID,
+				Start:     cmd.Start,
+				End:       cmd.End,
+			})
+			replyText = tools.TextBookRescheduled.String()
+			// старое время могло быть сегодня — обновляем всегда
+			createdToday = true
+		case session.Recurrence.IsZero():
+			err = h.uc.CreateBooking(ctx, cmd)
+			replyText = tools.TextBookYes.String()
+			createdToday = isToday(cmd.Start)
+			if err == nil {
+				h.notifyAttendees(cmd.Attendees, cmd.UserID, tools.BuildAttendeeInvitedStr(
+					cmd.UserName, cmd.RoomName, cmd.Start, cmd.End, cmd.Note, domain.RecurrenceRule{}))
+			}
+		default:
+			var res usecase.SeriesResult
+			res, err = h.uc.CreateRecurringBooking(ctx, usecase.CreateSeriesCmd{
+				CreateBookingCmd: cmd,
+				Rule:             session.Recurrence,
+			})
+			replyText = tools.BuildSeriesCreatedStr(len(res.Created), res.Conflicts, res.Rejected).String()
+			createdToday = len(res.Created) > 0 && isToday(res.Created[0].Start)
+			if err == nil {
+				first := res.Created[0]
+				h.notifyAttendees(cmd.Attendees, cmd.UserID, tools.BuildAttendeeInvitedStr(
+					cmd.UserName, cmd.RoomName, first.Start, first.End, cmd.Note, session.Recurrence))
+			}
 		}
 
-		if err := h.uc.CreateBooking(ctx, cmd); err != nil {
+		if err != nil {
+			switch {
+			// Пересечение при переносе: старая бронь осталась
+			case errors.Is(err, domain.ErrOverlapsExisting) && session.RescheduleID != 0:
+				h.answerWarning(tools.TextBookRescheduleOverlap.String(), cq)
 			// Пересечение бронирований
-			if errors.Is(err, domain.ErrOverlapsExisting) {
+			case errors.Is(err, domain.ErrOverlapsExisting):
 				h.answerWarning(tools.TextBookOverlapWarning.String(), cq)
-				return
-			}
+			case errors.Is(err, domain.ErrForbiddenCancellation):
+				h.answerWarning(tools.TextBookRescheduleForbidden.String(), cq)
+			case errors.Is(err, domain.ErrSeriesTooLong):
+				h.answerWarning(tools.SafeText(fmt.Sprintf(string(tools.TextBookSeriesTooLong), domain.MaxSeriesOccurrences)).String(), cq)
+			case errors.Is(err, domain.ErrInvalidRecurrence):
+				h.answerWarning(tools.TextBookInvalidSeries.String(), cq)
+			// Нарушение политики бронирования
+			case tools.IsPolicyViolation(err):
+				h.answerWarning(tools.BuildPolicyViolationStr(err, h.uc.Policy()).String(), cq)
 			// Неизвестная ошибка
-			h.log.Error("failed to create booking", "err", err)
-			h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при создании брони:* `%s`", err.Error()))
-			h.answerWarning(tools.TextBookServerError.String(), cq)
+			default:
+				h.log.Error("failed to create booking", "err", err)
+				h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при создании брони:* `%s`", err.Error()))
+				h.answerWarning(tools.TextBookServerError.String(), cq)
+			}
 			return
 		}
-		// Ошибок нет - бронь создана
-		replyText = tools.TextBookYes.String()
 
-		now := time.Now().In(cmd.Start.Location())
-		sy, sm, sd := cmd.Start.Date()
-		ny, nm, nd := now.Date()
-		if sy == ny && sm == nm && sd == nd {
+		// Ошибок нет - бронь создана
+		if createdToday {
 			go h.wake()
 		}
 
+	} else if session.RescheduleID != 0 {
+		replyText = tools.TextBookRescheduleNo.String()
 	} else {
 		replyText = tools.TextBookNo.String()
 	}
-	h.sessions.Delete(cq.From.ID)
+	h.sessions.Delete(ctx, cq.From.ID)
+	h.endFlow(ctx, cq.From.ID)
 
 	role, err := h.getRole(cq.From.ID)
 	if err != nil {
@@ -357,3 +605,11 @@ func (h *Handler) handleBookConfirm(ctx context.Context, cq *tgbotapi.CallbackQu
 		}
 	}()
 }
+
+// isToday — попадает ли момент t на сегодняшний день (в его TZ).
+func isToday(t time.Time) bool {
+	now := time.Now().In(t.Location())
+	sy, sm, sd := t.Date()
+	ny, nm, nd := now.Date()
+	return sy == ny && sm == nm && sd == nd
+}
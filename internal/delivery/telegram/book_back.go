@@ -15,6 +15,8 @@ import (
 func (h *Handler) handleBookListBack(ctx context.Context, cq *tgbotapi.CallbackQuery) {
 	h.answerCB(cq, "")
 	h.log.Info("User clicked 'Назад' на списке комнат", "user_id", cq.From.ID)
+	h.sessions.Delete(ctx, cq.From.ID)
+	h.endFlow(ctx, cq.From.ID)
 
 	edit := tgbotapi.NewEditMessageTextAndMarkup(
 		cq.Message.Chat.ID,
@@ -97,18 +99,21 @@ func (h *Handler) handleBookDurationBack(ctx context.Context, cq *tgbotapi.Callb
 	h.answerCB(cq, "")
 	h.log.Info("User clicked 'Назад' on duration", "user_id", cq.From.ID)
 
+	session := h.sessions.Get(ctx, cq.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionBooking)
+		return
+	}
+	h.goTo(ctx, session.UserID, stepBookStartTime)
+
+	text, kb := h.buildTimePick(ctx, session)
 	edit := tgbotapi.NewEditMessageTextAndMarkup(
 		cq.Message.Chat.ID,
 		cq.Message.MessageID,
-		tools.TextBookAskTimeInput.String(),
-		tgbotapi.NewInlineKeyboardMarkup(
-			[]tgbotapi.InlineKeyboardButton{
-				tools.BuildBackInlineKBButton("book:timepick_back"),
-			}),
+		text,
+		kb,
 	)
 
-	h.sessions.Get(cq.From.ID).BookState = tools.BookStateChoosingStartTime
-
 	edit.ParseMode = "MarkdownV2"
 	go func() {
 		if _, err := h.bot.Send(edit); err != nil {
@@ -117,15 +122,93 @@ func (h *Handler) handleBookDurationBack(ctx context.Context, cq *tgbotapi.Callb
 	}()
 }
 
+func (h *Handler) handleBookRecurrenceBack(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("User clicked 'Назад' on recurrence", "user_id", cq.From.ID)
+
+	if session := h.sessions.Get(ctx, cq.From.ID); session != nil {
+		h.goTo(ctx, session.UserID, stepBookDuration)
+		session.Recurrence = domain.RecurrenceRule{}
+	}
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.TextBookAskDuration.String(),
+		tools.BuildDurationKB(h.uc.Policy()),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on BookRecurrenceBack", "err", err)
+		}
+	}()
+}
+
+func (h *Handler) handleBookRecurrenceEndBack(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("User clicked 'Назад' on recurrence end", "user_id", cq.From.ID)
+
+	if session := h.sessions.Get(ctx, cq.From.ID); session != nil {
+		h.goTo(ctx, session.UserID, stepBookRecurrence)
+	}
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.TextBookAskRecurrence.String(),
+		tools.BuildRecurrenceKB(),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on BookRecurrenceEndBack", "err", err)
+		}
+	}()
+}
+
+func (h *Handler) handleBookUntilBack(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("User clicked 'Назад' on until calendar", "user_id", cq.From.ID)
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.TextBookAskRecurrenceEnd.String(),
+		tools.BuildRecurrenceEndKB(),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on BookUntilBack", "err", err)
+		}
+	}()
+}
+
 func (h *Handler) handleBookConfirmBack(ctx context.Context, cq *tgbotapi.CallbackQuery) {
 	h.answerCB(cq, "")
 	h.log.Info("User clicked 'Назад' on confirmation", "user_id", cq.From.ID)
 
+	session := h.sessions.Get(ctx, cq.From.ID)
+	if session != nil && session.RescheduleID != 0 {
+		// При переносе шага повтора нет — возвращаем к длительности
+		h.goTo(ctx, session.UserID, stepBookDuration)
+		h.handleBookConfirmBackToDuration(cq)
+		return
+	}
+	if session == nil {
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionBooking)
+		return
+	}
+	h.showBookAttendees(ctx, cq, session)
+}
+
+func (h *Handler) handleBookConfirmBackToDuration(cq *tgbotapi.CallbackQuery) {
 	edit := tgbotapi.NewEditMessageTextAndMarkup(
 		cq.Message.Chat.ID,
 		cq.Message.MessageID,
 		tools.TextBookAskDuration.String(),
-		tools.BuildDurationKB(),
+		tools.BuildDurationKB(h.uc.Policy()),
 	)
 	edit.ParseMode = "MarkdownV2"
 	go func() {
@@ -0,0 +1,272 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+/* ---------- /book: цель встречи и участники (необязательные шаги) ---------- */
+
+// Step 4.
+// Цель встречи. Вызывается после выбора повтора.
+func (h *Handler) showBookPurpose(ctx context.Context, cq *tgbotapi.CallbackQuery, session *tools.BookingSession) {
+	h.goTo(ctx, session.UserID, stepBookPurpose)
+	session.MessageID = cq.Message.MessageID
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.TextBookAskPurpose.String(),
+		tools.BuildSkipKB("book:purpose_skip", "book:purpose_back"),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on purpose", "err", err)
+		}
+	}()
+}
+
+// Цель введена текстом.
+func (h *Handler) handleBookPurpose(ctx context.Context, msg *tgbotapi.Message) {
+	session := h.sessions.Get(ctx, msg.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, msg.From.ID, msg.From.ID, domain.SessionBooking)
+		return
+	}
+
+	note := strings.TrimSpace(msg.Text)
+	if len([]rune(note)) > domain.MaxNoteLen {
+		h.reply(msg.Chat.ID, fmt.Sprintf(string(tools.TextBookPurposeTooLong), domain.MaxNoteLen))
+		return
+	}
+	session.Note = note
+	h.goTo(ctx, session.UserID, stepBookAttendees)
+
+	h.sendBookStep(msg.Chat.ID, session,
+		tools.TextBookAskAttendees.String(),
+		tools.BuildSkipKB("book:attendees_skip", "book:attendees_back"),
+	)
+}
+
+func (h *Handler) handleBookPurposeSkip(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	session := h.sessions.Get(ctx, cq.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionBooking)
+		return
+	}
+	session.Note = ""
+	h.showBookAttendees(ctx, cq, session)
+}
+
+// Step 5.
+// Участники встречи.
+func (h *Handler) showBookAttendees(ctx context.Context, cq *tgbotapi.CallbackQuery, session *tools.BookingSession) {
+	h.goTo(ctx, session.UserID, stepBookAttendees)
+	session.MessageID = cq.Message.MessageID
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.TextBookAskAttendees.String(),
+		tools.BuildSkipKB("book:attendees_skip", "book:attendees_back"),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on attendees", "err", err)
+		}
+	}()
+}
+
+// Участники введены текстом.
+func (h *Handler) handleBookAttendees(ctx context.Context, msg *tgbotapi.Message) {
+	session := h.sessions.Get(ctx, msg.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, msg.From.ID, msg.From.ID, domain.SessionBooking)
+		return
+	}
+
+	attendees, unknown := h.parseAttendees(ctx, msg)
+	if len(unknown) > 0 {
+		h.sendSafe(msg.Chat.ID, tools.SafeText(fmt.Sprintf(string(tools.TextBookAttendeesUnknown),
+			tools.StripMarkup(strings.Join(unknown, ", ")))))
+		return
+	}
+	check := domain.Booking{Attendees: attendees}
+	if err := check.ValidateDetails(); err != nil {
+		h.reply(msg.Chat.ID, fmt.Sprintf(string(tools.TextBookTooManyAttendees), domain.MaxAttendees, domain.MaxAttendeeNameLen))
+		return
+	}
+	session.Attendees = attendees
+	h.goTo(ctx, session.UserID, stepBookConfirm)
+
+	h.sendBookStep(msg.Chat.ID, session,
+		tools.BuildConfirmationStr(session).String(),
+		tools.BuildConfirmationKB("book"),
+	)
+}
+
+func (h *Handler) handleBookAttendeesSkip(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	session := h.sessions.Get(ctx, cq.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionBooking)
+		return
+	}
+	session.Attendees = nil
+	h.showBookConfirmation(ctx, cq, session)
+}
+
+// book:purpose_back — назад к выбору повтора.
+func (h *Handler) handleBookPurposeBack(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("User clicked 'Назад' on purpose", "user_id", cq.From.ID)
+
+	if session := h.sessions.Get(ctx, cq.From.ID); session != nil {
+		h.goTo(ctx, session.UserID, stepBookRecurrence)
+		session.MessageID = cq.Message.MessageID
+	}
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.TextBookAskRecurrence.String(),
+		tools.BuildRecurrenceKB(),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on purpose back", "err", err)
+		}
+	}()
+}
+
+// book:attendees_back — назад к цели встречи.
+func (h *Handler) handleBookAttendeesBack(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("User clicked 'Назад' on attendees", "user_id", cq.From.ID)
+
+	session := h.sessions.Get(ctx, cq.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionBooking)
+		return
+	}
+	h.showBookPurpose(ctx, cq, session)
+}
+
+// sendBookStep убирает кнопки у предыдущего сообщения шага и отправляет
+// следующий шаг новым сообщением (после текстового ввода редактировать нечего).
+func (h *Handler) sendBookStep(chatID int64, session *tools.BookingSession, text string, kb tgbotapi.InlineKeyboardMarkup) {
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, session.MessageID, tools.BuildBlankInlineKB())
+	if _, err := h.bot.Send(edit); err != nil {
+		h.log.Warn("Failed to remove keyboard of previous step", "err", err)
+	}
+
+	newMsg := tgbotapi.NewMessage(chatID, text)
+	newMsg.ParseMode = "MarkdownV2"
+	newMsg.ReplyMarkup = kb
+	go func() {
+		sentMsg, err := h.bot.Send(newMsg)
+		if err != nil {
+			h.log.Error("Failed to send next booking step", "err", err)
+			return
+		}
+		// чтобы при НАЗАД редактировалось текущее сообщение
+		session.MessageID = sentMsg.MessageID
+	}()
+}
+
+// parseAttendees разбирает список участников из сообщения.
+// Упоминания пользователей без ника (text_mention) дают UserID сразу,
+// @ник ищется среди ников, которые бот видел у пользователей, остальное —
+// просто имена без уведомления. Ники, владельца которых бот не знает,
+// возвращаются в unknown: угадывать участника по имени нельзя.
+func (h *Handler) parseAttendees(ctx context.Context, msg *tgbotapi.Message) (out []domain.Attendee, unknown []string) {
+	seen := make(map[string]bool)
+	add := func(a domain.Attendee) {
+		key := strings.ToLower(a.Name)
+		if a.UserID != 0 {
+			key = fmt.Sprint(a.UserID)
+		}
+		if a.Name == "" || seen[key] || int64(a.UserID) == msg.From.ID {
+			return
+		}
+		seen[key] = true
+		out = append(out, a)
+	}
+	addUsername := func(name string) {
+		id, err := h.uc.LookupUserID(ctx, name)
+		if err != nil {
+			if !errors.Is(err, domain.ErrUserNotFound) {
+				h.log.Warn("Failed to resolve attendee", "name", name, "err", err)
+			}
+			unknown = append(unknown, name)
+			return
+		}
+		add(domain.Attendee{UserID: id, Name: name})
+	}
+
+	// Смещения сущностей Telegram — в UTF-16
+	text := utf16.Encode([]rune(msg.Text))
+	for _, e := range msg.Entities {
+		if e.Offset < 0 || e.Offset+e.Length > len(text) {
+			continue
+		}
+		switch {
+		case e.Type == "text_mention" && e.User != nil:
+			add(domain.Attendee{UserID: domain.UserID(e.User.ID), Name: displayName(e.User)})
+		case e.Type == "mention":
+			addUsername(string(utf16.Decode(text[e.Offset : e.Offset+e.Length])))
+		default:
+			continue
+		}
+		for i := e.Offset; i < e.Offset+e.Length; i++ {
+			text[i] = ','
+		}
+	}
+
+	for _, part := range strings.FieldsFunc(string(utf16.Decode(text)), func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n'
+	}) {
+		name := strings.TrimSpace(part)
+		if name == "" {
+			continue
+		}
+		// @ник, который Telegram не распознал как упоминание
+		if strings.HasPrefix(name, "@") {
+			addUsername(name)
+			continue
+		}
+		add(domain.Attendee{Name: name})
+	}
+	return out, unknown
+}
+
+// notifyAttendees отправляет участникам-пользователям Telegram личное сообщение.
+// Организатора и того, кто выполняет действие, не уведомляем.
+func (h *Handler) notifyAttendees(attendees []domain.Attendee, skip domain.UserID, text tools.SafeText) {
+	for _, a := range attendees {
+		if a.UserID == 0 || a.UserID == skip {
+			continue
+		}
+		m := tgbotapi.NewMessage(int64(a.UserID), text.String())
+		m.ParseMode = "MarkdownV2"
+		go func(a domain.Attendee) {
+			if _, err := h.bot.Send(m); err != nil {
+				// участник мог ни разу не писать боту
+				h.log.Warn("Failed to notify attendee", "user_id", a.UserID, "name", a.Name, "err", err)
+			}
+		}(a)
+	}
+}
@@ -0,0 +1,141 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+/* ---------- /checkin и освобождение неиспользованных броней ---------- */
+
+// releaseNoShows вызывается по расписанию (telegram.checkin_tick).
+// Освобождённое время сразу появляется свободным в сообщении с расписанием.
+func (h *Handler) releaseNoShows(ctx context.Context) {
+	released, err := h.uc.ReleaseNoShows(ctx)
+	if err != nil {
+		h.log.Error("Failed to release no-show bookings", "err", err)
+		return
+	}
+	if len(released) == 0 {
+		return
+	}
+
+	go h.wake()
+
+	grace := h.uc.CheckinGrace()
+	for _, rb := range released {
+		m := tgbotapi.NewMessage(int64(rb.UserID), tools.BuildNoShowReleasedStr(rb, grace).String())
+		m.ParseMode = "MarkdownV2"
+		go func(rb domain.ReleasedBooking) {
+			if _, err := h.bot.Send(m); err != nil {
+				h.log.Warn("Failed to notify owner about released booking", "owner_id", rb.UserID, "bk_id", rb.ID, "err", err)
+			}
+		}(rb)
+	}
+}
+
+func (h *Handler) handleCheckin(ctx context.Context, msg *tgbotapi.Message) {
+	if err := ctx.Err(); err != nil {
+		h.log.Warn("Context canceled in handleCheckin handler",
+			"user", msg.From.UserName,
+			"chat_id", msg.Chat.ID,
+			"err", ctx.Err())
+		return
+	}
+
+	if h.uc.CheckinGrace() <= 0 {
+		h.reply(msg.Chat.ID, string(tools.TextCheckinDisabled))
+		return
+	}
+
+	bks, err := h.uc.PendingCheckIns(ctx, domain.UserID(msg.From.ID))
+	if err != nil {
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при /checkin:* `%s`", err.Error()))
+		h.reply(msg.Chat.ID, string(tools.TextCheckinErr))
+		return
+	}
+
+	switch len(bks) {
+	case 0:
+		h.reply(msg.Chat.ID, string(tools.TextCheckinNone))
+	case 1:
+		bk, err := h.uc.CheckIn(ctx, int64(bks[0].ID), h.actor(msg.From.ID))
+		if err != nil {
+			h.log.Error("Failed to check in", "user_id", msg.From.ID, "bk_id", bks[0].ID, "error", err)
+			h.reply(msg.Chat.ID, string(checkinErrText(err)))
+			return
+		}
+		m := tgbotapi.NewMessage(msg.Chat.ID, tools.BuildCheckinDoneStr(bk).String())
+		m.ParseMode = "MarkdownV2"
+		go func() {
+			if _, err := h.bot.Send(m); err != nil {
+				h.log.Error("Failed to send checkin result", "err", err)
+			}
+		}()
+	default:
+		m := tgbotapi.NewMessage(msg.Chat.ID, tools.TextCheckinPick.String())
+		m.ParseMode = "MarkdownV2"
+		m.ReplyMarkup = tools.BuildCheckinKB(bks)
+		go func() {
+			if _, err := h.bot.Send(m); err != nil {
+				h.log.Error("Failed to send checkin list", "err", err)
+			}
+		}()
+	}
+}
+
+// checkin:do:<bookingID>
+func (h *Handler) handleCheckinDo(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("handleCheckinDo", "data", cq.Data, "user", cq.From.UserName)
+
+	parts := strings.Split(cq.Data, ":")
+	id, _ := strconv.ParseInt(parts[2], 10, 64)
+
+	bk, err := h.uc.CheckIn(ctx, id, h.actor(cq.From.ID))
+	if err != nil {
+		h.answerCheckinErr(cq, id, err)
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.BuildCheckinDoneStr(bk).String(),
+		tools.BuildBlankInlineKB(),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on checkin", "err", err)
+		}
+	}()
+}
+
+// answerCheckinErr сообщает об ошибке check-in из callback (кнопки /checkin и напоминания).
+func (h *Handler) answerCheckinErr(cq *tgbotapi.CallbackQuery, id int64, err error) {
+	if !errors.Is(err, domain.ErrBookingNotFound) && !errors.Is(err, domain.ErrBookingEnded) && !errors.Is(err, domain.ErrForbiddenCancellation) {
+		h.log.Error("Failed to check in", "user_id", cq.From.ID, "bk_id", id, "error", err)
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при /checkin:* `%s`", err.Error()))
+	}
+	h.answerWarning(checkinErrText(err).String(), cq)
+}
+
+func checkinErrText(err error) tools.SafeText {
+	switch {
+	case errors.Is(err, domain.ErrBookingNotFound):
+		return tools.TextCheckinNotFound
+	case errors.Is(err, domain.ErrBookingEnded):
+		return tools.TextCheckinEnded
+	case errors.Is(err, domain.ErrForbiddenCancellation):
+		return tools.TextCheckinForbidden
+	default:
+		return tools.TextCheckinErr
+	}
+}
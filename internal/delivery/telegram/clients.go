@@ -0,0 +1,78 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+/* ---------- /merge_clients — объединение дублей доверителей ---------- */
+
+// /merge_clients Ромашка — похожие клиенты с номерами;
+// /merge_clients 12 7 — записи клиента №12 переходят к №7, №12 удаляется.
+func (h *Handler) handleMergeClients(ctx context.Context, msg *tgbotapi.Message) {
+	if err := ctx.Err(); err != nil {
+		h.log.Warn("Context canceled in handleMergeClients handler",
+			"user", msg.From.UserName,
+			"chat_id", msg.Chat.ID,
+			"err", ctx.Err())
+		return
+	}
+
+	actor := h.actor(msg.From.ID)
+	if !actor.IsAdmin() {
+		h.sendSafe(msg.Chat.ID, tools.TextClientsNotAllowed)
+		return
+	}
+
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) == 0 {
+		h.sendSafe(msg.Chat.ID, tools.TextClientsMergeUsage)
+		return
+	}
+
+	if len(args) == 2 {
+		from, errFrom := strconv.ParseInt(args[0], 10, 64)
+		to, errTo := strconv.ParseInt(args[1], 10, 64)
+		if errFrom == nil && errTo == nil {
+			h.mergeClients(ctx, msg.Chat.ID, domain.ClientID(from), domain.ClientID(to), actor)
+			return
+		}
+	}
+
+	clients, err := h.logsUC.SuggestClients(ctx, strings.Join(args, " "))
+	if err != nil {
+		h.sendSafe(msg.Chat.ID, tools.TextClientsMergeErr)
+		return
+	}
+	h.sendSafe(msg.Chat.ID, tools.BuildClientListStr(clients))
+}
+
+func (h *Handler) mergeClients(ctx context.Context, chatID int64, from, to domain.ClientID, actor domain.Actor) {
+	client, err := h.logsUC.MergeClients(ctx, from, to, actor)
+	switch {
+	case errors.Is(err, domain.ErrInvalidInputData):
+		h.sendSafe(chatID, tools.TextClientsMergeUsage)
+		return
+	case errors.Is(err, domain.ErrRecordNotFound):
+		h.sendSafe(chatID, tools.TextClientsMissing)
+		return
+	case errors.Is(err, domain.ErrUnauthorized):
+		h.sendSafe(chatID, tools.TextClientsNotAllowed)
+		return
+	case err != nil:
+		h.log.Error("MergeClients error", "err", err, "from", from, "to", to)
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при /merge_clients:* `%s`", err.Error()))
+		h.sendSafe(chatID, tools.TextClientsMergeErr)
+		return
+	}
+
+	text := fmt.Sprintf(tools.TextClientsMerged, from, tools.StripMarkup(client.Name), client.ID, client.Records)
+	h.sendSafe(chatID, tools.SafeText(text))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	// "time"
 
@@ -12,6 +13,7 @@ import (
 
 	"github.com/leegeev/KomaevBookingBot/internal/delivery/notifier"
 	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
 	"github.com/leegeev/KomaevBookingBot/internal/usecase"
 	"github.com/leegeev/KomaevBookingBot/pkg/config"
 	"github.com/leegeev/KomaevBookingBot/pkg/logger"
@@ -26,30 +28,66 @@ type Handler struct {
 	logsUC     *usecase.LogService
 	sessions   *tools.SessionsStore // userID -> сессия бронирования
 	logSession *tools.LogsStore     // userID -> сессия журналов
+	flows      *tools.FlowStore     // userID -> текущий диалог и его шаг
+	searches   *tools.SearchStore   // userID -> последний поиск по журналам
+	exports    *tools.ExportStore   // userID -> настройка выгрузки журналов
 	roleCache  *RoleCache           // userID -> роль (user/admin)
 
-	messageID int64
-	msgMu     sync.Mutex
+	state      *usecase.BotStateService
+	sessionsUC *usecase.SessionService
+
+	// группа для проверки ролей; меняется /register, читается из воркеров
+	groupChatID atomic.Int64
+
+	// сообщения с расписанием на день scheduleDay: chatID -> messageID
+	groups      []int64
+	messages    map[int64]int
+	scheduleDay string
+	msgMu       sync.Mutex
 
 	commandHandlers  map[string]func(ctx context.Context, msg *tgbotapi.Message)
 	callbackHandlers map[string]func(ctx context.Context, cq *tgbotapi.CallbackQuery)
+
+	// Диалоги: объявленные флоу и флоу, которому принадлежит шаг
+	flowDefs map[tools.FlowID]*flow
+	stepFlow map[tools.Step]tools.FlowID
 }
 
-func NewHandler(bot *tgbotapi.BotAPI, cfg config.Telegram, log logger.Logger, uc *usecase.BookingService, logsUC *usecase.LogService) *Handler {
-	return &Handler{
+func NewHandler(bot *tgbotapi.BotAPI, cfg config.Telegram, log logger.Logger, uc *usecase.BookingService, logsUC *usecase.LogService, state *usecase.BotStateService, sessions *usecase.SessionService) *Handler {
+	h := &Handler{
 		bot:              bot,
 		cfg:              cfg,
 		log:              log,
 		uc:               uc,
 		logsUC:           logsUC,
-		sessions:         tools.NewSessionStore(),
-		logSession:       tools.NewLogsStore(),
+		sessions:         tools.NewSessionStore(sessions),
+		logSession:       tools.NewLogsStore(sessions),
+		flows:            tools.NewFlowStore(sessions),
+		searches:         tools.NewSearchStore(),
+		exports:          tools.NewExportStore(),
 		roleCache:        NewRoleCache(cfg.RoleCacheTTL),
-		messageID:        0,
+		state:            state,
+		sessionsUC:       sessions,
+		messages:         make(map[int64]int),
 		msgMu:            sync.Mutex{},
 		commandHandlers:  make(map[string]func(ctx context.Context, msg *tgbotapi.Message)),
 		callbackHandlers: make(map[string]func(ctx context.Context, cq *tgbotapi.CallbackQuery)),
+		flowDefs:         make(map[tools.FlowID]*flow),
+		stepFlow:         make(map[tools.Step]tools.FlowID),
 	}
+	h.groupChatID.Store(cfg.GroupChatID)
+	return h
+}
+
+// Какие апдейты запрашивать у Telegram (long polling и setWebhook).
+var AllowedUpdates = []string{"message", "callback_query"}
+
+// UpdateSource — источник апдейтов: long polling или вебхук.
+type UpdateSource interface {
+	Updates() <-chan tgbotapi.Update
+	// Stop — перестать принимать новые апдейты. Вызывается при остановке
+	// до ожидания обработки уже принятых.
+	Stop()
 }
 
 // Запуск long-polling. Блокирует до ctx.Done().
@@ -59,20 +97,61 @@ func (h *Handler) RunPolling(ctx context.Context) error {
 	}
 	h.bot.Debug = false
 
+	// getUpdates не работает, пока у бота есть вебхук (например, остался
+	// после аварийной остановки в режиме webhook)
+	if _, err := h.bot.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		return fmt.Errorf("deleteWebhook: %w", err)
+	}
+
 	updateConfig := tgbotapi.NewUpdate(0)
 	updateConfig.Timeout = 30
+	updateConfig.AllowedUpdates = AllowedUpdates
+	return h.Serve(ctx, &pollingSource{bot: h.bot, updates: h.bot.GetUpdatesChan(updateConfig)})
+}
+
+type pollingSource struct {
+	bot     *tgbotapi.BotAPI
+	updates tgbotapi.UpdatesChannel
+}
+
+func (p *pollingSource) Updates() <-chan tgbotapi.Update { return p.updates }
+func (p *pollingSource) Stop()                           { p.bot.StopReceivingUpdates() }
+
+// Serve запускает фоновые задачи и обрабатывает апдейты из src, пока не
+// отменён ctx. Общий конвейер для long polling и вебхука.
+func (h *Handler) Serve(ctx context.Context, src UpdateSource) error {
 	h.registerRoutes()
-	updateConfig.AllowedUpdates = []string{"message", "callback_query"}
+	h.restoreState(ctx)
 
 	n := notifier.New(h.log, h.cfg.OfficeTZ)
 	err := n.AddJob(ctx, h.cfg.NotifierConfig, h.DailySchedule)
 	if err != nil {
 		h.log.Error("failed to add job", "err", err)
 	}
+	if h.cfg.ReminderTick != "" {
+		if err := n.AddJob(ctx, h.cfg.ReminderTick, func() { h.sendReminders(ctx) }); err != nil {
+			h.log.Error("failed to add reminder job", "err", err)
+		}
+	}
+	if h.cfg.CheckinTick != "" {
+		if err := n.AddJob(ctx, h.cfg.CheckinTick, func() { h.releaseNoShows(ctx) }); err != nil {
+			h.log.Error("failed to add checkin job", "err", err)
+		}
+	}
+	if h.cfg.SessionSweep != "" {
+		if err := n.AddJob(ctx, h.cfg.SessionSweep, func() { h.sweepSessions(ctx) }); err != nil {
+			h.log.Error("failed to add session sweep job", "err", err)
+		}
+	}
 	n.Start(ctx)
 
-	updates := h.bot.GetUpdatesChan(updateConfig)
-	defer h.bot.StopReceivingUpdates()
+	d := newDispatcher(h.cfg, h.log, h.dispatch)
+	d.start(ctx)
+	// Сначала перестаём получать апдейты, потом дожидаемся обработки принятых
+	defer d.stop()
+	defer src.Stop()
+
+	updates := src.Updates()
 	for {
 		select {
 		case <-ctx.Done():
@@ -81,8 +160,9 @@ func (h *Handler) RunPolling(ctx context.Context) error {
 			if !ok {
 				return fmt.Errorf("updates channel closed")
 			}
-			h.dispatch(ctx, upd)
-			// go h.dispatch(ctx, upd) // TODO: лимит горутин
+			if !d.push(ctx, upd) {
+				return ctx.Err()
+			}
 		}
 	}
 }
@@ -93,6 +173,12 @@ func (h *Handler) dispatch(ctx context.Context, upd tgbotapi.Update) {
 			h.log.Error("panic in telegram handler", "panic", r)
 		}
 	}()
+	if from := upd.SentFrom(); from != nil {
+		// Сессии сохраняем, даже если обработчик упёрся в таймаут
+		defer h.flushSessions(context.WithoutCancel(ctx), from.ID)
+		// Ник нужен, чтобы находить пользователя по @упоминанию
+		h.uc.RememberUsername(ctx, domain.UserID(from.ID), from.UserName)
+	}
 
 	if upd.Message != nil && upd.Message.IsCommand() && upd.Message.Command() == "register" {
 		h.handleRegisterFromAdmin(ctx, upd.Message)
@@ -117,6 +203,8 @@ func (h *Handler) dispatch(ctx context.Context, upd tgbotapi.Update) {
 		)
 		cmd := upd.Message.Command()
 		if handler, ok := h.commandHandlers[cmd]; ok {
+			// Новая команда прерывает текущий диалог
+			h.cancelFlow(ctx, upd.Message.From.ID)
 			handler(ctx, upd.Message)
 		} else {
 			h.reply(upd.Message.Chat.ID, "Неизвестная команда. Смотри /help")
@@ -147,35 +235,12 @@ func (h *Handler) dispatch(ctx context.Context, upd tgbotapi.Update) {
 			"text", upd.Message.Text,
 		)
 		if handler, ok := h.commandHandlers[upd.Message.Text]; ok {
+			h.cancelFlow(ctx, upd.Message.From.ID)
 			handler(ctx, upd.Message)
 			return
 		}
 
-		bookSess := h.sessions.Get(upd.Message.From.ID)
-		logSess := h.logSession.Get(upd.Message.From.ID)
-		switch {
-		case bookSess == nil && logSess == nil:
-			h.reply(upd.Message.Chat.ID, "Необработанный ввод. Смотри /help")
-			return
-		case bookSess != nil && bookSess.BookState == tools.BookStateChoosingStartTime:
-			h.handleBookTimepick(ctx, upd.Message)
-			return
-		case bookSess != nil && bookSess.BookState == tools.StateProccessingRoomCreation:
-			h.handleCreateRoomProcessing(ctx, upd.Message)
-			return
-		case logSess != nil && logSess.State == tools.StateInputingName:
-			h.handleLogCreate3(ctx, upd.Message)
-			return
-		case logSess != nil && logSess.State == tools.StateInputingDoveritel:
-			h.handleLogCreate4(ctx, upd.Message)
-			return
-		case logSess != nil && logSess.State == tools.StageInputingComment:
-			h.handleLogCreate5(ctx, upd.Message)
-			return
-		default:
-			h.reply(upd.Message.Chat.ID, "Сессия не найдена. Смотри /help")
-			return
-		}
+		h.handleFlowText(ctx, upd.Message)
 	}
 }
 
@@ -195,6 +260,17 @@ func (h *Handler) reply(chatID int64, text string) {
 	_, _ = h.bot.Send(m)
 }
 
+// sendSafe отправляет текст с разметкой MarkdownV2.
+func (h *Handler) sendSafe(chatID int64, text tools.SafeText) {
+	m := tgbotapi.NewMessage(chatID, text.String())
+	m.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(m); err != nil {
+			h.log.Error("Failed to send message", "err", err, "chat_id", chatID)
+		}
+	}()
+}
+
 func (h *Handler) registerRoutes() {
 	// commands
 	h.commandHandlers["start"] = h.handleStart
@@ -202,9 +278,13 @@ func (h *Handler) registerRoutes() {
 	h.commandHandlers["my"] = h.handleMy
 	h.commandHandlers["book"] = h.handleBook
 	h.commandHandlers["schedule"] = h.handleSchedule
+	h.commandHandlers["free"] = h.handleFree
 	h.commandHandlers["create_room"] = h.handleCreateRoom
 	h.commandHandlers["deactivate_room"] = h.handleDeactivateRoom
 	h.commandHandlers["register"] = h.handleRegisterFromAdmin
+	h.commandHandlers["cancel_booking"] = h.handleAdminCancel
+	h.commandHandlers["remind"] = h.handleRemind
+	h.commandHandlers["checkin"] = h.handleCheckin
 
 	// Text commands
 	h.commandHandlers[tools.TextMainBookButton] = h.handleBook
@@ -212,28 +292,32 @@ func (h *Handler) registerRoutes() {
 	h.commandHandlers[tools.TextMainScheduleButton] = h.handleSchedule
 	h.commandHandlers[tools.TextMainCreateRoomButton] = h.handleCreateRoom
 	h.commandHandlers[tools.TextMainDeleteRoomButton] = h.handleDeactivateRoom
+	h.commandHandlers[tools.TextMainAdminCancelButton] = h.handleAdminCancel
 	h.commandHandlers[tools.TextMainHelpButton] = h.handleHelp
 
+	// Диалоги (бронирование, журналы, ...) со своими коллбэками
+	h.registerFlows()
+
 	// callbacks
-	// BOOK
-	h.callbackHandlers["book:list"] = h.handleBookList
-	h.callbackHandlers["book:calendar"] = h.handleBookCalendar
-	h.callbackHandlers["book:calendar_nav"] = h.handleBookCalendarNavigation // book:calendar_nav:-1
-	h.callbackHandlers["book:duration"] = h.handleBookDuration
-	h.callbackHandlers["book:confirm"] = h.handleBookConfirm
-
-	h.callbackHandlers["book:list_back"] = h.handleBookListBack
-	h.callbackHandlers["book:calendar_back"] = h.handleBookCalendarBack
-	h.callbackHandlers["book:timepick_back"] = h.handleBookTimepickBack
-	h.callbackHandlers["book:duration_back"] = h.handleBookDurationBack
-	h.callbackHandlers["book:confirm_back"] = h.handleBookConfirmBack
+	// FREE
+	h.callbackHandlers["free:book"] = h.handleFreeBook
 
 	// MY
 	h.callbackHandlers["my:list"] = h.handleMyList
 	h.callbackHandlers["my:back"] = h.handleMyBack
 	h.callbackHandlers["my:cancel"] = h.handleMyCancel
+	h.callbackHandlers["my:reschedule"] = h.handleMyReschedule
+	h.callbackHandlers["my:cancel_series"] = h.handleMyCancelSeries
 	h.callbackHandlers["my:list_back"] = h.handleMyListBack
 
+	// REMIND
+	h.callbackHandlers["remind:coming"] = h.handleRemindComing // remind:coming:<bookingID>
+	h.callbackHandlers["remind:cancel"] = h.handleRemindCancel // remind:cancel:<bookingID>
+	h.callbackHandlers["remind:set"] = h.handleRemindSet       // remind:set:<minutes>
+
+	// CHECKIN
+	h.callbackHandlers["checkin:do"] = h.handleCheckinDo // checkin:do:<bookingID>
+
 	// no:op
 	h.callbackHandlers["no:op"] = func(ctx context.Context, cq *tgbotapi.CallbackQuery) {
 		h.answerCB(cq, "")
@@ -246,11 +330,21 @@ func (h *Handler) registerRoutes() {
 	h.callbackHandlers["deactivate:confirm_cancel"] = h.handleConfirmCancel
 	h.callbackHandlers["deactivate:confirm_back"] = h.handleDeactivateConfirmBack
 
+	// Сессия истекла — начать заново
+	h.callbackHandlers["session:restart"] = h.handleSessionRestart // session:restart:<kind>
+
 	// ------------ Журналы ------------
 
 	// Журналы. Команды
 	h.commandHandlers["log"] = h.handleLog
 	h.commandHandlers["find"] = h.handleLogFind
+	h.commandHandlers["search"] = h.handleLogSearch
+	h.commandHandlers["merge_clients"] = h.handleMergeClients
+	h.commandHandlers["lawyers"] = h.handleLawyers
+	h.commandHandlers["lawyer_add"] = h.handleLawyerAdd
+	h.commandHandlers["lawyer_fio"] = h.handleLawyerFIO
+	h.commandHandlers["lawyer_off"] = h.handleLawyerOff
+	h.commandHandlers["lawyer_on"] = h.handleLawyerOn
 
 	// Журналы. Кнопки
 	h.commandHandlers[tools.TextMainLogButton] = h.handleLog
@@ -260,19 +354,27 @@ func (h *Handler) registerRoutes() {
 	h.commandHandlers[tools.TextLogMainMenuButton] = h.handleMainMenu
 
 	// Журналы коллбэки
-	h.callbackHandlers["log:create"] = h.handleLogCreate1_0
-	h.callbackHandlers["log:calendar_nav"] = h.handleLogСreate1_1
-	h.callbackHandlers["log:calendar"] = h.handleLogCreate2
-	h.callbackHandlers["log:confirm"] = h.handleLogCreate6
-
-	h.callbackHandlers["log:create_back"] = h.handleLogCreateBack
-	h.callbackHandlers["log:calendar_back"] = h.handleLogCalendarBack
-	h.callbackHandlers["log:step2_back"] = h.handleLogStep2Back
-	h.callbackHandlers["log:step3_back"] = h.handleLogStep3Back
-	h.callbackHandlers["log:step4_back"] = h.handleLogStep4Back
-	h.callbackHandlers["log:confirm_back"] = h.handleLogConfirmBack
-
 	h.callbackHandlers["log:my"] = h.handleLogMy1
+	h.callbackHandlers["logcard:copy"] = h.handleLogCardCopy              // logcard:copy:<kind>:<id>
+	h.callbackHandlers["logcard:owner"] = h.handleLogCardOwner            // logcard:owner:<kind>:<userID>
+	h.callbackHandlers["logmy:page"] = h.handleLogMyPage                  // logmy:page:<kind>:<userID>:<offset>
+	h.callbackHandlers["logmy:export"] = h.handleLogMyExport              // logmy:export:<userID>
+	h.callbackHandlers["search:page"] = h.handleLogSearchPage             // search:page:<offset>
+	h.callbackHandlers["search:open"] = h.handleLogSearchOpen             // search:open:<kind>:<id>
+	h.callbackHandlers["export:period"] = h.handleExportPeriod            // export:period:<period|custom>
+	h.callbackHandlers["export:calendar_nav"] = h.handleExportCalendarNav // export:calendar_nav:<shift>
+	h.callbackHandlers["export:calendar"] = h.handleExportCalendar        // export:calendar:<YYYY-MM-DD>
+	h.callbackHandlers["export:calendar_back"] = h.handleExportPeriodBack
+	h.callbackHandlers["export:period_back"] = h.handleExportPeriodBack
+	h.callbackHandlers["export:kind"] = h.handleExportKind
+	h.callbackHandlers["export:authors"] = h.handleExportAuthors
+	h.callbackHandlers["export:author"] = h.handleExportAuthor // export:author:<userID>
+	h.callbackHandlers["export:filters"] = h.handleExportFilters
+	h.callbackHandlers["export:run"] = h.handleExportRun
+	h.callbackHandlers["export:cancel"] = h.handleExportCancel
+	h.callbackHandlers["logedit:history"] = h.handleLogHistory   // logedit:history:<kind>:<id>
+	h.callbackHandlers["logattach:list"] = h.handleLogAttachList // logattach:list:<kind>:<id>
+
 	// Флоу создания записи в журнале
 	// // Журналы. Управление
 	// h.commandHandlers[tools.TextLogSoglCreateButton] = h.handleLogSoglCreate
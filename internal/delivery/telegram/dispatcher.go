@@ -0,0 +1,139 @@
+package telegram
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/pkg/config"
+	"github.com/leegeev/KomaevBookingBot/pkg/logger"
+)
+
+// Параллельная обработка апдейтов. Апдейты раскладываются по воркерам по
+// ID пользователя: апдейты одного пользователя обрабатывает один воркер
+// строго по очереди (сессии диалогов это предполагают), разные
+// пользователи обрабатываются параллельно.
+
+const (
+	defaultWorkers         = 8
+	defaultWorkerQueue     = 64
+	defaultUpdateTimeout   = time.Minute
+	defaultShutdownTimeout = 30 * time.Second
+)
+
+type dispatcher struct {
+	log      logger.Logger
+	handle   func(ctx context.Context, upd tgbotapi.Update)
+	queues   []chan tgbotapi.Update
+	timeout  time.Duration // на обработку одного апдейта
+	shutdown time.Duration // сколько ждать обработки очереди при остановке
+	wg       sync.WaitGroup
+	stopOnce sync.Once
+}
+
+func newDispatcher(cfg config.Telegram, log logger.Logger, handle func(ctx context.Context, upd tgbotapi.Update)) *dispatcher {
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	queue := cfg.WorkerQueue
+	if queue <= 0 {
+		queue = defaultWorkerQueue
+	}
+	timeout := cfg.UpdateTimeout
+	if timeout <= 0 {
+		timeout = defaultUpdateTimeout
+	}
+	shutdown := cfg.ShutdownTimeout
+	if shutdown <= 0 {
+		shutdown = defaultShutdownTimeout
+	}
+
+	d := &dispatcher{
+		log:      log,
+		handle:   handle,
+		queues:   make([]chan tgbotapi.Update, workers),
+		timeout:  timeout,
+		shutdown: shutdown,
+	}
+	for i := range d.queues {
+		d.queues[i] = make(chan tgbotapi.Update, queue)
+	}
+	return d
+}
+
+// start запускает воркеры. Контекст обработчиков не отменяется вместе с
+// ctx: начатые обработчики при остановке дорабатывают до своего таймаута.
+func (d *dispatcher) start(ctx context.Context) {
+	base := context.WithoutCancel(ctx)
+	for _, q := range d.queues {
+		d.wg.Add(1)
+		go d.work(base, q)
+	}
+	d.log.Info("Update dispatcher started", "workers", len(d.queues), "update_timeout", d.timeout)
+}
+
+func (d *dispatcher) work(base context.Context, q <-chan tgbotapi.Update) {
+	defer d.wg.Done()
+	for upd := range q {
+		ctx, cancel := context.WithTimeout(base, d.timeout)
+		d.handle(ctx, upd)
+		cancel()
+	}
+}
+
+// push ставит апдейт в очередь воркера пользователя. Если очередь полна,
+// ждёт (long-polling при этом не запрашивает новые апдейты). false — ctx
+// отменён и апдейт не поставлен.
+func (d *dispatcher) push(ctx context.Context, upd tgbotapi.Update) bool {
+	q := d.queues[d.shard(upd)]
+	select {
+	case q <- upd:
+		return true
+	default:
+	}
+
+	d.log.Warn("Update queue is full, waiting", "update_id", upd.UpdateID)
+	select {
+	case q <- upd:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+func (d *dispatcher) shard(upd tgbotapi.Update) int {
+	var key int64
+	if from := upd.SentFrom(); from != nil {
+		key = from.ID
+	} else if chat := upd.FromChat(); chat != nil {
+		key = chat.ID
+	}
+	if key < 0 {
+		key = -key
+	}
+	return int(key % int64(len(d.queues)))
+}
+
+// stop закрывает очереди и ждёт, пока воркеры обработают уже принятые
+// апдейты, но не дольше shutdown_timeout. push после stop вызывать нельзя.
+func (d *dispatcher) stop() {
+	d.stopOnce.Do(func() {
+		for _, q := range d.queues {
+			close(q)
+		}
+	})
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		d.log.Info("Update dispatcher drained")
+	case <-time.After(d.shutdown):
+		d.log.Warn("Update dispatcher drain timed out", "timeout", d.shutdown)
+	}
+}
@@ -0,0 +1,58 @@
+package telegram
+
+import (
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/pkg/config"
+)
+
+func TestDispatcherShard(t *testing.T) {
+	d := newDispatcher(config.Telegram{Workers: 4}, nil, nil)
+
+	msg := func(userID, chatID int64) tgbotapi.Update {
+		return tgbotapi.Update{Message: &tgbotapi.Message{
+			From: &tgbotapi.User{ID: userID},
+			Chat: &tgbotapi.Chat{ID: chatID},
+		}}
+	}
+	callback := func(userID, chatID int64) tgbotapi.Update {
+		return tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{
+			From:    &tgbotapi.User{ID: userID},
+			Message: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: chatID}},
+		}}
+	}
+
+	tests := []struct {
+		name string
+		upd  tgbotapi.Update
+		want int
+	}{
+		{"private message", msg(10, 10), 2},
+		{"same user in group", msg(10, -1001234567890), 2},
+		{"callback of same user", callback(10, -1001234567890), 2},
+		{"other user", msg(13, 13), 1},
+		{"channel post without sender", tgbotapi.Update{ChannelPost: &tgbotapi.Message{Chat: &tgbotapi.Chat{ID: -7}}}, 3},
+		{"empty update", tgbotapi.Update{}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := d.shard(tt.upd); got != tt.want {
+				t.Fatalf("shard = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDispatcherDefaults(t *testing.T) {
+	d := newDispatcher(config.Telegram{}, nil, nil)
+	if len(d.queues) != defaultWorkers {
+		t.Fatalf("workers = %d, want %d", len(d.queues), defaultWorkers)
+	}
+	if cap(d.queues[0]) != defaultWorkerQueue {
+		t.Fatalf("queue = %d, want %d", cap(d.queues[0]), defaultWorkerQueue)
+	}
+	if d.timeout != defaultUpdateTimeout || d.shutdown != defaultShutdownTimeout {
+		t.Fatalf("timeouts = %v/%v, want %v/%v", d.timeout, d.shutdown, defaultUpdateTimeout, defaultShutdownTimeout)
+	}
+}
@@ -0,0 +1,280 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/internal/usecase"
+)
+
+// Выгрузка журналов в Excel: период → фильтры → файлы.
+
+func (h *Handler) handleLogExport(ctx context.Context, msg *tgbotapi.Message) {
+	if err := ctx.Err(); err != nil {
+		h.log.Warn("Context canceled in /handleLogExport",
+			"user", msg.From.UserName,
+			"chat_id", msg.Chat.ID,
+			"err", ctx.Err())
+		return
+	}
+
+	if !h.actor(msg.From.ID).IsAdmin() {
+		h.reply(msg.Chat.ID, string(tools.TextExportNotAllowed))
+		return
+	}
+	h.exports.Set(msg.From.ID, &tools.ExportSession{})
+
+	m := tgbotapi.NewMessage(msg.Chat.ID, tools.TextExportChoosePeriod.String())
+	m.ParseMode = "MarkdownV2"
+	m.ReplyMarkup = tools.BuildExportPeriodKB()
+	go func() {
+		if _, err := h.bot.Send(m); err != nil {
+			h.log.Error("Failed to send export period choice", "err", err)
+		}
+	}()
+}
+
+// editExportMessage заменяет текст и клавиатуру сообщения настройки выгрузки.
+func (h *Handler) editExportMessage(cq *tgbotapi.CallbackQuery, text tools.SafeText, kb tgbotapi.InlineKeyboardMarkup) {
+	edit := tgbotapi.NewEditMessageTextAndMarkup(cq.Message.Chat.ID, cq.Message.MessageID, text.String(), kb)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit export message", "err", err, "data", cq.Data)
+		}
+	}()
+}
+
+func (h *Handler) exportSession(cq *tgbotapi.CallbackQuery) *tools.ExportSession {
+	session := h.exports.Get(cq.From.ID)
+	if session == nil {
+		h.reply(cq.Message.Chat.ID, "Сессия не найдена")
+	}
+	return session
+}
+
+// export:period:<period|custom>
+func (h *Handler) handleExportPeriod(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	session := h.exportSession(cq)
+	if session == nil {
+		return
+	}
+
+	parts := strings.Split(cq.Data, ":")
+	if len(parts) < 3 {
+		return
+	}
+	if parts[2] == "custom" {
+		session.PickingTo = false
+		h.editExportMessage(cq, tools.TextExportPickFrom, tools.BuildLogCalendarKB("export", 0))
+		return
+	}
+
+	from, to, err := domain.ReportPeriod(parts[2]).Range(time.Now().In(h.cfg.OfficeTZ))
+	if err != nil {
+		h.log.Warn("Unknown export period", "data", cq.Data)
+		return
+	}
+	session.Query.From, session.Query.To = from, to
+	h.editExportMessage(cq, tools.BuildExportFiltersStr(session), tools.BuildExportFiltersKB(session))
+}
+
+// export:calendar_nav:<shift> — листание календаря только в прошлое.
+func (h *Handler) handleExportCalendarNav(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	parts := strings.Split(cq.Data, ":")
+	shift, _ := strconv.ParseInt(parts[2], 10, 64)
+	if shift > 0 {
+		return
+	}
+	editMarkup := tgbotapi.NewEditMessageReplyMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.BuildLogCalendarKB("export", shift),
+	)
+	go func() {
+		if _, err := h.bot.Request(editMarkup); err != nil {
+			h.log.Error("failed to edit export calendar inline keyboard", "err", err)
+		}
+	}()
+}
+
+// export:calendar:<YYYY-MM-DD> — начало, затем конец своего периода.
+func (h *Handler) handleExportCalendar(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	parts := strings.Split(cq.Data, ":")
+	if len(parts) < 3 {
+		h.answerCB(cq, "")
+		return
+	}
+	date, err := time.ParseInLocation("2006-01-02", parts[2], h.cfg.OfficeTZ)
+	if err != nil {
+		h.answerCB(cq, "")
+		return
+	}
+	session := h.exports.Get(cq.From.ID)
+	if session == nil {
+		h.answerCB(cq, "")
+		h.reply(cq.Message.Chat.ID, "Сессия не найдена")
+		return
+	}
+
+	if !session.PickingTo {
+		h.answerCB(cq, "")
+		session.Query.From = date
+		session.PickingTo = true
+		text := tools.SafeText(fmt.Sprintf(string(tools.TextExportPickTo), date.Format("02.01.2006")))
+		h.editExportMessage(cq, text, tools.BuildLogCalendarKB("export", 0))
+		return
+	}
+
+	if date.Before(session.Query.From) {
+		h.answerCB(cq, tools.TextExportBadRange)
+		return
+	}
+	h.answerCB(cq, "")
+	session.Query.To = date
+	session.PickingTo = false
+	h.editExportMessage(cq, tools.BuildExportFiltersStr(session), tools.BuildExportFiltersKB(session))
+}
+
+// export:calendar_back и export:period_back — обратно к выбору периода.
+func (h *Handler) handleExportPeriodBack(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	session := h.exportSession(cq)
+	if session == nil {
+		return
+	}
+	session.PickingTo = false
+	h.editExportMessage(cq, tools.TextExportChoosePeriod, tools.BuildExportPeriodKB())
+}
+
+// export:kind — все → соглашения → запросы → все.
+func (h *Handler) handleExportKind(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	session := h.exportSession(cq)
+	if session == nil {
+		return
+	}
+
+	switch session.Query.Kind {
+	case "":
+		session.Query.Kind = domain.LogKindSogl
+	case domain.LogKindSogl:
+		session.Query.Kind = domain.LogKindZapros
+	default:
+		session.Query.Kind = ""
+	}
+	h.editExportMessage(cq, tools.BuildExportFiltersStr(session), tools.BuildExportFiltersKB(session))
+}
+
+// export:authors — список адвокатов для фильтра.
+func (h *Handler) handleExportAuthors(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	if h.exportSession(cq) == nil {
+		return
+	}
+
+	users, err := h.logsUC.ListLawyers(ctx)
+	if err != nil {
+		h.reply(cq.Message.Chat.ID, string(tools.TextExportErr))
+		return
+	}
+	h.editExportMessage(cq, tools.TextExportChooseAuthor, tools.BuildExportAuthorsKB(users))
+}
+
+// export:author:<userID> — 0 снимает фильтр.
+func (h *Handler) handleExportAuthor(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	session := h.exportSession(cq)
+	if session == nil {
+		return
+	}
+
+	parts := strings.Split(cq.Data, ":")
+	if len(parts) < 3 {
+		return
+	}
+	userID, _ := strconv.ParseInt(parts[2], 10, 64)
+	session.Query.UserID = domain.UserID(userID)
+	session.AuthorName = ""
+	if userID != 0 {
+		user, err := h.logsUC.GetUser(ctx, userID)
+		if err != nil {
+			h.log.Warn("Failed to get export author", "err", err, "userID", userID)
+			session.Query.UserID = 0
+		} else {
+			session.AuthorName = user.FIO
+		}
+	}
+	h.editExportMessage(cq, tools.BuildExportFiltersStr(session), tools.BuildExportFiltersKB(session))
+}
+
+// export:filters — обратно к фильтрам из списка авторов.
+func (h *Handler) handleExportFilters(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	session := h.exportSession(cq)
+	if session == nil {
+		return
+	}
+	h.editExportMessage(cq, tools.BuildExportFiltersStr(session), tools.BuildExportFiltersKB(session))
+}
+
+func (h *Handler) handleExportCancel(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.exports.Delete(cq.From.ID)
+	h.editExportMessage(cq, tools.TextExportCancelled, tools.BuildBlankInlineKB())
+}
+
+// export:run — формирование и отправка файлов.
+func (h *Handler) handleExportRun(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	session := h.exportSession(cq)
+	if session == nil {
+		return
+	}
+	h.exports.Delete(cq.From.ID)
+
+	q := session.Query
+	from, to := q.From.Format("02.01.2006"), q.To.Format("02.01.2006")
+	h.editExportMessage(cq, tools.SafeText(fmt.Sprintf(string(tools.TextExportInProgress), from, to)), tools.BuildBlankInlineKB())
+
+	chatID := cq.Message.Chat.ID
+	report, err := h.logsUC.CreateExcelReport(ctx, q, h.actor(cq.From.ID))
+	switch {
+	case errors.Is(err, domain.ErrRecordNotFound):
+		h.reply(chatID, fmt.Sprintf(string(tools.TextExportNoRecords), from, to))
+		return
+	case errors.Is(err, domain.ErrNotOwner):
+		h.reply(chatID, string(tools.TextExportNotAllowed))
+		return
+	case err != nil:
+		h.reply(chatID, string(tools.TextExportErr))
+		h.log.Error("CreateExcelReport error", "err", err)
+		return
+	}
+
+	h.sendReport(chatID, report, fmt.Sprintf(tools.TextExportCaption, from, to))
+}
+
+// sendReport отправляет книгу Excel из памяти, без временных файлов.
+func (h *Handler) sendReport(chatID int64, report usecase.ExcelReport, caption string) {
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{Name: report.Name, Bytes: report.Data})
+	doc.Caption = caption
+	go func() {
+		if _, err := h.bot.Send(doc); err != nil {
+			h.log.Error("Failed to send report", "err", err, "file", report.Name)
+			h.reply(chatID, "❌ Ошибка при отправке отчёта")
+			return
+		}
+		h.log.Info("Report sent successfully", "chat_id", chatID, "file", report.Name)
+	}()
+}
@@ -0,0 +1,205 @@
+package telegram
+
+import (
+	"context"
+
+	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+)
+
+// Флоу бота и их шаги. Коллбэки, не привязанные к диалогу (карточки,
+// списки, напоминания), регистрируются в registerRoutes.
+
+const (
+	flowBook        tools.FlowID = "book"
+	flowRoomCreate  tools.FlowID = "room_create"
+	flowAdminCancel tools.FlowID = "admin_cancel"
+	flowLogCreate   tools.FlowID = "log_create"
+	flowLogEdit     tools.FlowID = "log_edit"
+	flowLogAttach   tools.FlowID = "log_attach"
+)
+
+const (
+	// Бронирование и перенос брони
+	stepBookRoom          tools.Step = "book.room"
+	stepBookDate          tools.Step = "book.date"
+	stepBookStartTime     tools.Step = "book.start_time"
+	stepBookDuration      tools.Step = "book.duration"
+	stepBookRecurrence    tools.Step = "book.recurrence"
+	stepBookRecurrenceEnd tools.Step = "book.recurrence_end"
+	stepBookPurpose       tools.Step = "book.purpose"
+	stepBookAttendees     tools.Step = "book.attendees"
+	stepBookConfirm       tools.Step = "book.confirm"
+
+	// Создание переговорки
+	stepRoomName tools.Step = "room_create.name"
+
+	// Отмена чужой брони администратором
+	stepAdminCancelReason tools.Step = "admin_cancel.reason"
+
+	// Создание записи в журнале
+	stepLogDate      tools.Step = "log_create.date"
+	stepLogName      tools.Step = "log_create.name"
+	stepLogDoveritel tools.Step = "log_create.doveritel"
+	stepLogClient    tools.Step = "log_create.client"
+	stepLogComment   tools.Step = "log_create.comment"
+	stepLogConfirm   tools.Step = "log_create.confirm"
+
+	// Правка и аннулирование записи
+	stepLogEditValue  tools.Step = "log_edit.value"
+	stepLogVoidReason tools.Step = "log_edit.void_reason"
+
+	// Вложения к существующей записи
+	stepLogAttachFiles tools.Step = "log_attach.files"
+)
+
+func (h *Handler) registerFlows() {
+	h.registerFlow(flow{
+		ID: flowBook,
+		Steps: map[tools.Step]textHandler{
+			stepBookRoom:          nil,
+			stepBookDate:          nil,
+			stepBookStartTime:     h.handleBookTimepick,
+			stepBookDuration:      nil,
+			stepBookRecurrence:    nil,
+			stepBookRecurrenceEnd: nil,
+			stepBookPurpose:       h.handleBookPurpose,
+			stepBookAttendees:     h.handleBookAttendees,
+			stepBookConfirm:       nil,
+		},
+		Callbacks: map[string]callbackHandler{
+			"book:list":           h.handleBookList,
+			"book:calendar":       h.handleBookCalendar,
+			"book:calendar_nav":   h.handleBookCalendarNavigation, // book:calendar_nav:-1
+			"book:timepick":       h.handleBookTimepickButton,
+			"book:duration":       h.handleBookDuration,
+			"book:recur":          h.handleBookRecurrence,
+			"book:recur_count":    h.handleBookRecurrenceCount,
+			"book:recur_until":    h.handleBookRecurrenceUntil,
+			"book:until":          h.handleBookUntil,
+			"book:until_nav":      h.handleBookUntilNavigation,
+			"book:purpose_skip":   h.handleBookPurposeSkip,
+			"book:attendees_skip": h.handleBookAttendeesSkip,
+			"book:confirm":        h.handleBookConfirm,
+		},
+		Back: map[string]callbackHandler{
+			"book:list_back":      h.handleBookListBack,
+			"book:calendar_back":  h.handleBookCalendarBack,
+			"book:timepick_back":  h.handleBookTimepickBack,
+			"book:duration_back":  h.handleBookDurationBack,
+			"book:recur_back":     h.handleBookRecurrenceBack,
+			"book:recur_end_back": h.handleBookRecurrenceEndBack,
+			"book:until_back":     h.handleBookUntilBack,
+			"book:purpose_back":   h.handleBookPurposeBack,
+			"book:attendees_back": h.handleBookAttendeesBack,
+			"book:confirm_back":   h.handleBookConfirmBack,
+		},
+		Drop:   h.dropBookingSession,
+		Cancel: h.clearBookingKB,
+	})
+
+	h.registerFlow(flow{
+		ID: flowRoomCreate,
+		Steps: map[tools.Step]textHandler{
+			stepRoomName: h.handleCreateRoomProcessing,
+		},
+	})
+
+	h.registerFlow(flow{
+		ID: flowAdminCancel,
+		Steps: map[tools.Step]textHandler{
+			stepAdminCancelReason: h.handleAdminCancelReason,
+		},
+		Callbacks: map[string]callbackHandler{
+			"admin_cancel:list": h.handleAdminCancelList, // admin_cancel:list:<roomID>
+			"admin_cancel:pick": h.handleAdminCancelPick, // admin_cancel:pick:<bookingID>
+		},
+		Back: map[string]callbackHandler{
+			"admin_cancel:list_back":   h.handleDeactivateListBack,
+			"admin_cancel:pick_back":   h.handleAdminCancelPickBack,
+			"admin_cancel:reason_back": h.handleAdminCancelReasonBack, // admin_cancel:reason_back:<roomID>
+		},
+		Drop:   h.dropBookingSession,
+		Cancel: h.clearBookingKB,
+	})
+
+	h.registerFlow(flow{
+		ID: flowLogCreate,
+		Steps: map[tools.Step]textHandler{
+			stepLogDate:      nil,
+			stepLogName:      h.handleLogCreate3,
+			stepLogDoveritel: h.handleLogCreate4,
+			stepLogClient:    h.handleLogCreate4,
+			stepLogComment:   h.handleLogCreate5,
+			stepLogConfirm:   h.handleLogCreateFile, // скан документа до подтверждения
+		},
+		Callbacks: map[string]callbackHandler{
+			"log:create":       h.handleLogCreate1_0,
+			"log:calendar_nav": h.handleLogСreate1_1,
+			"log:calendar":     h.handleLogCreate2,
+			"log:client":       h.handleLogCreate4_1, // log:client:<clientID>
+			"log:confirm":      h.handleLogCreate6,
+		},
+		Back: map[string]callbackHandler{
+			"log:create_back":   h.handleLogCreateBack,
+			"log:calendar_back": h.handleLogCalendarBack,
+			"log:step2_back":    h.handleLogStep2Back,
+			"log:step3_back":    h.handleLogStep3Back,
+			"log:step4_back":    h.handleLogStep4Back,
+			"log:confirm_back":  h.handleLogConfirmBack,
+		},
+		Drop:   h.dropLogSession,
+		Cancel: h.clearLogKB,
+	})
+
+	h.registerFlow(flow{
+		ID: flowLogEdit,
+		Steps: map[tools.Step]textHandler{
+			stepLogEditValue:  h.handleLogEditValue,
+			stepLogVoidReason: h.handleLogVoidReason,
+		},
+		Callbacks: map[string]callbackHandler{
+			"logedit:start": h.handleLogEditStart, // logedit:start:<kind>:<id>
+			"logedit:field": h.handleLogEditField, // logedit:field:<field>:<kind>:<id>
+			"logvoid:start": h.handleLogVoidStart, // logvoid:start:<kind>:<id>
+		},
+		Back: map[string]callbackHandler{
+			"logedit:cancel": h.handleLogEditCancel, // logedit:cancel:<kind>:<id>
+		},
+		Drop:   h.dropLogSession,
+		Cancel: h.clearLogKB,
+	})
+
+	h.registerFlow(flow{
+		ID: flowLogAttach,
+		Steps: map[tools.Step]textHandler{
+			stepLogAttachFiles: h.handleLogAttachFile,
+		},
+		Callbacks: map[string]callbackHandler{
+			"logattach:start": h.handleLogAttachStart, // logattach:start:<kind>:<id>
+			"logattach:done":  h.handleLogAttachDone,  // logattach:done:<kind>:<id>
+		},
+		Drop:   h.dropLogSession,
+		Cancel: h.clearLogKB,
+	})
+}
+
+func (h *Handler) dropBookingSession(ctx context.Context, userID int64) {
+	h.sessions.Delete(ctx, userID)
+}
+
+func (h *Handler) dropLogSession(ctx context.Context, userID int64) {
+	h.logSession.Delete(ctx, userID)
+}
+
+func (h *Handler) clearBookingKB(ctx context.Context, userID int64) {
+	if s := h.sessions.Get(ctx, userID); s != nil {
+		h.clearFlowKB(s.ChatID, s.MessageID)
+	}
+}
+
+// Диалоги журналов идут только в личке, поэтому чат — это пользователь.
+func (h *Handler) clearLogKB(ctx context.Context, userID int64) {
+	if s := h.logSession.Get(ctx, userID); s != nil {
+		h.clearFlowKB(userID, s.MessageID)
+	}
+}
@@ -0,0 +1,150 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+/* ---------- /free ---------- */
+
+// /free               — свободные переговорки сейчас (на 1 час) + свободное время на сегодня
+// /free 15:00         — свободные переговорки сегодня с 15:00 на 1 час
+// /free 15:00 1.5     — то же, на 1.5 часа
+func (h *Handler) handleFree(ctx context.Context, msg *tgbotapi.Message) {
+	if err := ctx.Err(); err != nil {
+		h.log.Warn("Context canceled in /free handler",
+			"user", msg.From.UserName,
+			"chat_id", msg.Chat.ID,
+			"err", ctx.Err())
+		return
+	}
+
+	now := time.Now().In(h.cfg.OfficeTZ)
+	start := now.Truncate(h.uc.Policy().Step)
+	dur := time.Hour
+
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) > 0 {
+		picked, err := tools.ParseTimePick(args[0])
+		if err != nil {
+			h.reply(msg.Chat.ID, tools.TextFreeInvalidArgs.String())
+			return
+		}
+		start = time.Date(now.Year(), now.Month(), now.Day(), picked.Hour(), picked.Minute(), 0, 0, h.cfg.OfficeTZ)
+	}
+	if len(args) > 1 {
+		hours, err := strconv.ParseFloat(strings.ReplaceAll(args[1], ",", "."), 64)
+		if err != nil || hours <= 0 || hours > 24 {
+			h.reply(msg.Chat.ID, tools.TextFreeInvalidArgs.String())
+			return
+		}
+		dur = time.Duration(hours * float64(time.Hour))
+	}
+
+	rooms, err := h.uc.FreeRooms(ctx, start, dur)
+	switch {
+	case errors.Is(err, domain.ErrPastTimeNotAllowed):
+		h.reply(msg.Chat.ID, tools.TextFreeInPast.String())
+		return
+	case errors.Is(err, domain.ErrNoRoomsAvailable):
+		h.reply(msg.Chat.ID, tools.TextBookNoRoomsAvailable.String())
+		return
+	case tools.IsPolicyViolation(err):
+		h.reply(msg.Chat.ID, tools.BuildPolicyViolationStr(err, h.uc.Policy()).String())
+		return
+	case err != nil:
+		h.log.Error("Failed to find free rooms", "user_id", msg.From.ID, "error", err)
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при /free:* `%s`", err.Error()))
+		h.reply(msg.Chat.ID, tools.TextBookNoRoomsErr.String())
+		return
+	}
+
+	var b strings.Builder
+	b.WriteString(tools.BuildFreeRoomsStr(rooms, start, dur).String())
+
+	// Без аргументов дополнительно показываем свободное время каждой переговорки на сегодня
+	if len(args) == 0 {
+		b.WriteString(h.buildTodayFreeSlots(ctx, now))
+	}
+
+	m := tgbotapi.NewMessage(msg.Chat.ID, b.String())
+	m.ParseMode = "MarkdownV2"
+	if len(rooms) > 0 {
+		m.ReplyMarkup = tools.BuildFreeRoomsKB(rooms, start, dur)
+	}
+	go func() {
+		if _, err := h.bot.Send(m); err != nil {
+			h.log.Error("Failed to send /free", "err", err)
+		}
+	}()
+}
+
+func (h *Handler) buildTodayFreeSlots(ctx context.Context, day time.Time) string {
+	rooms, err := h.uc.ListRooms(ctx)
+	if err != nil {
+		h.log.Error("Failed to list rooms for /free", "err", err)
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(tools.TextFreeTodayHeader.String())
+	for _, room := range rooms {
+		free, err := h.uc.FreeSlots(ctx, room.ID, day, 0)
+		if err != nil {
+			h.log.Error("Failed to get free slots for /free", "room", room.Name, "err", err)
+			continue
+		}
+		b.WriteString(tools.BuildRoomFreeSlotsStr(room, free).String())
+	}
+	return b.String()
+}
+
+// free:book:<roomID>:<HHMM>:<minutes>
+// Быстрая бронь из /free: сразу к подтверждению.
+func (h *Handler) handleFreeBook(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	parts := strings.Split(cq.Data, ":")
+	if len(parts) < 5 {
+		h.reply(cq.Message.Chat.ID, "Ошибка: неправильные данные")
+		return
+	}
+	roomID, _ := strconv.ParseInt(parts[2], 10, 64)
+	picked, err := time.Parse("1504", parts[3])
+	if err != nil {
+		h.reply(cq.Message.Chat.ID, "Ошибка: неправильное время")
+		return
+	}
+	minutes, _ := strconv.Atoi(parts[4])
+
+	room, err := h.uc.GetRoom(ctx, roomID)
+	if err != nil {
+		h.reply(cq.Message.Chat.ID, "Ошибка: не удалось получить переговорку.")
+		return
+	}
+
+	now := time.Now().In(h.cfg.OfficeTZ)
+	date := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, h.cfg.OfficeTZ)
+	session := &tools.BookingSession{
+		ChatID:    cq.Message.Chat.ID,
+		UserID:    cq.From.ID,
+		UserName:  displayName(cq.From),
+		MessageID: cq.Message.MessageID,
+		RoomID:    room.ID,
+		RoomName:  room.Name,
+		Date:      date,
+		StartTime: time.Date(now.Year(), now.Month(), now.Day(), picked.Hour(), picked.Minute(), 0, 0, h.cfg.OfficeTZ),
+		Duration:  time.Duration(minutes) * time.Minute,
+	}
+	h.goTo(ctx, cq.From.ID, stepBookConfirm)
+	h.sessions.Set(ctx, session)
+	h.showBookConfirmation(ctx, cq, session)
+}
@@ -0,0 +1,140 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+)
+
+// Конечный автомат диалогов. Каждый флоу (бронирование, создание
+// переговорки, запись в журнал, ...) объявляет свои шаги с обработчиками
+// текста, коллбэки и переходы «назад». У пользователя активен не больше
+// одного флоу: переход на шаг другого флоу или новая команда сбрасывают
+// предыдущий.
+
+type textHandler func(ctx context.Context, msg *tgbotapi.Message)
+type callbackHandler func(ctx context.Context, cq *tgbotapi.CallbackQuery)
+
+type flow struct {
+	ID tools.FlowID
+	// Шаги флоу и обработчик текста на каждом. nil — на шаге ждём кнопку.
+	Steps map[tools.Step]textHandler
+	// Коллбэки флоу и переходы «назад» по префиксу callback data.
+	Callbacks map[string]callbackHandler
+	Back      map[string]callbackHandler
+	// Drop удаляет данные флоу. Вызывается при отмене флоу.
+	Drop func(ctx context.Context, userID int64)
+	// Cancel убирает кнопки последнего сообщения флоу, когда его прервали
+	// новой командой. Необязателен, вызывается до Drop.
+	Cancel func(ctx context.Context, userID int64)
+}
+
+// registerFlow объявляет флоу и регистрирует его коллбэки. Шаг или
+// коллбэк, объявленный дважды, — ошибка программиста.
+func (h *Handler) registerFlow(f flow) {
+	if _, ok := h.flowDefs[f.ID]; ok {
+		panic(fmt.Sprintf("flow %q registered twice", f.ID))
+	}
+	h.flowDefs[f.ID] = &f
+
+	for step := range f.Steps {
+		if owner, ok := h.stepFlow[step]; ok {
+			panic(fmt.Sprintf("step %q declared in flows %q and %q", step, owner, f.ID))
+		}
+		h.stepFlow[step] = f.ID
+	}
+	for _, cbs := range []map[string]callbackHandler{f.Callbacks, f.Back} {
+		for prefix, cb := range cbs {
+			if _, ok := h.callbackHandlers[prefix]; ok {
+				panic(fmt.Sprintf("callback %q registered twice", prefix))
+			}
+			h.callbackHandlers[prefix] = cb
+		}
+	}
+}
+
+// goTo переводит пользователя на шаг. Если шаг из другого флоу, текущий
+// флоу отменяется, поэтому при старте флоу goTo вызывают до сохранения
+// его данных.
+func (h *Handler) goTo(ctx context.Context, userID int64, step tools.Step) {
+	id, ok := h.stepFlow[step]
+	if !ok {
+		panic(fmt.Sprintf("step %q is not declared in any flow", step))
+	}
+
+	if cur := h.flows.Get(ctx, userID); cur != nil && cur.Flow == id {
+		cur.Step = step
+		return
+	}
+	h.dropFlow(ctx, userID, false)
+	h.flows.Set(ctx, &tools.FlowSession{UserID: userID, Flow: id, Step: step})
+}
+
+// inStep — пользователь сейчас на шаге step.
+func (h *Handler) inStep(ctx context.Context, userID int64, step tools.Step) bool {
+	cur := h.flows.Get(ctx, userID)
+	return cur != nil && cur.Step == step
+}
+
+// endFlow завершает флоу, когда диалог закончен. Данные флоу обработчик
+// удаляет сам.
+func (h *Handler) endFlow(ctx context.Context, userID int64) {
+	h.flows.Delete(ctx, userID)
+}
+
+// cancelFlow прерывает текущий флоу: убирает его кнопки и данные.
+func (h *Handler) cancelFlow(ctx context.Context, userID int64) {
+	h.dropFlow(ctx, userID, true)
+}
+
+func (h *Handler) dropFlow(ctx context.Context, userID int64, cancel bool) {
+	cur := h.flows.Get(ctx, userID)
+	if cur == nil {
+		return
+	}
+	if f, ok := h.flowDefs[cur.Flow]; ok {
+		if cancel && f.Cancel != nil {
+			f.Cancel(ctx, userID)
+		}
+		if f.Drop != nil {
+			f.Drop(ctx, userID)
+		}
+	}
+	h.flows.Delete(ctx, userID)
+}
+
+// handleFlowText передаёт текст обработчику текущего шага.
+func (h *Handler) handleFlowText(ctx context.Context, msg *tgbotapi.Message) {
+	cur := h.flows.Get(ctx, msg.From.ID)
+	if cur == nil {
+		h.handleNoSession(ctx, msg)
+		return
+	}
+	f, ok := h.flowDefs[cur.Flow]
+	if !ok {
+		// Флоу из старой версии бота
+		h.flows.Delete(ctx, msg.From.ID)
+		h.handleNoSession(ctx, msg)
+		return
+	}
+
+	handler := f.Steps[cur.Step]
+	if handler == nil {
+		h.sendSafe(msg.Chat.ID, tools.TextFlowUseButtons)
+		return
+	}
+	handler(ctx, msg)
+}
+
+// clearFlowKB убирает кнопки сообщения прерванного флоу.
+func (h *Handler) clearFlowKB(chatID int64, messageID int) {
+	if messageID == 0 {
+		return
+	}
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, tools.BuildBlankInlineKB())
+	if _, err := h.bot.Send(edit); err != nil {
+		h.log.Debug("Failed to clear keyboard of cancelled flow", "err", err)
+	}
+}
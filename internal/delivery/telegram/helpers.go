@@ -8,6 +8,7 @@ import (
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
 )
 
 type roleEntry struct {
@@ -58,7 +59,8 @@ type UserID = int64
 type BookingID = int64
 
 func (h *Handler) getRole(userID int64) (string, error) {
-	if h.cfg.GroupChatID == 0 {
+	groupChatID := h.groupChatID.Load()
+	if groupChatID == 0 {
 		h.notifyAdmin("GroupChatID is not set in config")
 		return "", fmt.Errorf("внутренняя ошибка. тех поддержка уведомлена")
 
@@ -72,7 +74,7 @@ func (h *Handler) getRole(userID int64) (string, error) {
 	// --- если в кеше нет или TTL истёк, идём в Telegram API ---
 	cfg := tgbotapi.GetChatMemberConfig{
 		ChatConfigWithUser: tgbotapi.ChatConfigWithUser{
-			ChatID: h.cfg.GroupChatID,
+			ChatID: groupChatID,
 			UserID: userID,
 		},
 	}
@@ -89,6 +91,21 @@ func (h *Handler) getRole(userID int64) (string, error) {
 	return m.Status, nil
 }
 
+// actor возвращает действующее лицо для usecase-слоя с ролью из группового чата.
+// Если роль получить не удалось — считаем пользователя обычным участником.
+func (h *Handler) actor(userID int64) domain.Actor {
+	a := domain.Actor{UserID: domain.UserID(userID), Role: domain.RoleMember}
+	role, err := h.getRole(userID)
+	if err != nil {
+		h.log.Warn("Failed to get user role for actor", "err", err, "user_id", userID)
+		return a
+	}
+	if tools.CheckRoleIsAdmin(role) {
+		a.Role = domain.RoleAdmin
+	}
+	return a
+}
+
 func (h *Handler) checkSupported(ctx context.Context, upd tgbotapi.Update) error {
 	var userID int64
 	switch {
@@ -146,3 +163,18 @@ func (h *Handler) answerWarning(warning string, cq *tgbotapi.CallbackQuery) {
 		h.log.Error("failed to send main menu", "err", err)
 	}
 }
+
+// displayName — как показывать пользователя в расписании: @ник, «Имя Фамилия» или имя.
+func displayName(u *tgbotapi.User) string {
+	switch {
+	case u.UserName != "":
+		// Есть никнейм → используем его
+		return "@" + u.UserName
+	case u.LastName != "":
+		// Нет ника, но есть имя + фамилия
+		return u.FirstName + " " + u.LastName
+	default:
+		// Остался минимум: только имя
+		return u.FirstName
+	}
+}
@@ -0,0 +1,137 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+/* ---------- /lawyers — управление адвокатами администратором ---------- */
+
+// lawyerCommand проверяет права и разбирает аргументы «<telegram_id> [ФИО]».
+func (h *Handler) lawyerCommand(ctx context.Context, msg *tgbotapi.Message) (domain.Actor, int64, string, bool) {
+	if err := ctx.Err(); err != nil {
+		h.log.Warn("Context canceled in lawyer command",
+			"user", msg.From.UserName,
+			"chat_id", msg.Chat.ID,
+			"err", ctx.Err())
+		return domain.Actor{}, 0, "", false
+	}
+
+	actor := h.actor(msg.From.ID)
+	if !actor.IsAdmin() {
+		h.sendSafe(msg.Chat.ID, tools.TextLawyersNotAllowed)
+		return domain.Actor{}, 0, "", false
+	}
+
+	args := strings.Fields(msg.CommandArguments())
+	if len(args) == 0 {
+		h.sendSafe(msg.Chat.ID, tools.TextLawyersUsage)
+		return domain.Actor{}, 0, "", false
+	}
+	id, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil || id <= 0 {
+		h.sendSafe(msg.Chat.ID, tools.TextLawyersUsage)
+		return domain.Actor{}, 0, "", false
+	}
+	return actor, id, strings.Join(args[1:], " "), true
+}
+
+// lawyerErr отвечает на ошибку команды управления адвокатами.
+func (h *Handler) lawyerErr(chatID, id int64, err error) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInputData):
+		h.sendSafe(chatID, tools.TextLawyersUsage)
+	case errors.Is(err, domain.ErrUserNotFound):
+		h.sendSafe(chatID, tools.SafeText(fmt.Sprintf(tools.TextLawyerNotFound, id)))
+	case errors.Is(err, domain.ErrUnauthorized):
+		h.sendSafe(chatID, tools.TextLawyersNotAllowed)
+	default:
+		h.log.Error("Lawyer command error", "err", err, "userID", id)
+		h.sendSafe(chatID, tools.TextLawyersErr)
+	}
+}
+
+// /lawyers — список адвокатов с Telegram ID и статусом.
+func (h *Handler) handleLawyers(ctx context.Context, msg *tgbotapi.Message) {
+	if !h.actor(msg.From.ID).IsAdmin() {
+		h.sendSafe(msg.Chat.ID, tools.TextLawyersNotAllowed)
+		return
+	}
+
+	users, err := h.logsUC.ListLawyers(ctx)
+	if err != nil {
+		h.sendSafe(msg.Chat.ID, tools.TextLawyersErr)
+		return
+	}
+	h.sendSafe(msg.Chat.ID, tools.BuildLawyersListStr(users))
+}
+
+// /lawyer_add <telegram_id> <ФИО>
+func (h *Handler) handleLawyerAdd(ctx context.Context, msg *tgbotapi.Message) {
+	actor, id, fio, ok := h.lawyerCommand(ctx, msg)
+	if !ok {
+		return
+	}
+
+	user, err := h.logsUC.AddLawyer(ctx, actor, id, fio)
+	if errors.Is(err, domain.ErrUserExists) {
+		h.sendSafe(msg.Chat.ID, tools.SafeText(fmt.Sprintf(tools.TextLawyerExists, id, tools.StripMarkup(user.FIO))))
+		return
+	} else if err != nil {
+		h.lawyerErr(msg.Chat.ID, id, err)
+		return
+	}
+	h.sendSafe(msg.Chat.ID, tools.SafeText(fmt.Sprintf(tools.TextLawyerAdded, tools.StripMarkup(user.FIO), user.ID)))
+}
+
+// /lawyer_fio <telegram_id> <ФИО>
+func (h *Handler) handleLawyerFIO(ctx context.Context, msg *tgbotapi.Message) {
+	actor, id, fio, ok := h.lawyerCommand(ctx, msg)
+	if !ok {
+		return
+	}
+
+	old, err := h.logsUC.RenameLawyer(ctx, actor, id, fio)
+	if err != nil {
+		h.lawyerErr(msg.Chat.ID, id, err)
+		return
+	}
+	h.sendSafe(msg.Chat.ID, tools.SafeText(fmt.Sprintf(tools.TextLawyerRenamed,
+		tools.StripMarkup(old.FIO), tools.StripMarkup(strings.Join(strings.Fields(fio), " ")))))
+}
+
+// /lawyer_off <telegram_id>
+func (h *Handler) handleLawyerOff(ctx context.Context, msg *tgbotapi.Message) {
+	h.setLawyerActive(ctx, msg, false)
+}
+
+// /lawyer_on <telegram_id>
+func (h *Handler) handleLawyerOn(ctx context.Context, msg *tgbotapi.Message) {
+	h.setLawyerActive(ctx, msg, true)
+}
+
+func (h *Handler) setLawyerActive(ctx context.Context, msg *tgbotapi.Message, active bool) {
+	actor, id, _, ok := h.lawyerCommand(ctx, msg)
+	if !ok {
+		return
+	}
+
+	user, err := h.logsUC.SetLawyerActive(ctx, actor, id, active)
+	if err != nil {
+		h.lawyerErr(msg.Chat.ID, id, err)
+		return
+	}
+
+	text := tools.TextLawyerDeactivated
+	if active {
+		text = tools.TextLawyerActivated
+	}
+	h.sendSafe(msg.Chat.ID, tools.SafeText(fmt.Sprintf(text, tools.StripMarkup(user.FIO))))
+}
@@ -2,11 +2,15 @@ package telegram
 
 import (
 	"context"
-	"os"
+	"errors"
+	"fmt"
+	"strconv"
 	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/internal/usecase"
 )
 
 func (h *Handler) handleLog(ctx context.Context, msg *tgbotapi.Message) {
@@ -59,7 +63,6 @@ func (h *Handler) handleLogMy1(ctx context.Context, cq *tgbotapi.CallbackQuery)
 
 	parts := strings.Split(cq.Data, ":")
 	logType := parts[2]
-	msgText := ""
 
 	edit := tgbotapi.NewEditMessageReplyMarkup(
 		cq.Message.Chat.ID,
@@ -70,75 +73,99 @@ func (h *Handler) handleLogMy1(ctx context.Context, cq *tgbotapi.CallbackQuery)
 		h.log.Error("Failed to EDIT message on handleLogCreate6 confirmation", "err", err)
 	}
 
-	if logType == "sogl" {
-		logs, _ := h.logsUC.GetSoglasheniyaByUserID(ctx, cq.From.ID)
-		msgText = tools.BuildLogSoglListStr(logs, h.cfg.OfficeTZ).String()
-	}
+	h.sendLogPage(ctx, cq.Message.Chat.ID, domain.LogKind(logType), domain.UserID(cq.From.ID), cq.From.ID)
+}
 
-	if logType == "zapros" {
-		logs, _ := h.logsUC.GetZaprosiByUserID(ctx, cq.From.ID)
-		msgText = tools.BuildLogZaprosiListStr(logs, h.cfg.OfficeTZ).String()
+// sendLogPage отправляет первую страницу записей адвоката userID.
+func (h *Handler) sendLogPage(ctx context.Context, chatID int64, kind domain.LogKind, userID domain.UserID, actorID int64) {
+	recs, total, err := h.logsUC.ListUserLogs(ctx, kind, userID, 0, h.actor(actorID))
+	switch {
+	case errors.Is(err, domain.ErrNotOwner):
+		h.reply(chatID, string(tools.TextLogOwnerLogsNotAllowed))
+		return
+	case err != nil:
+		h.reply(chatID, string(tools.TextLogListErr))
+		return
 	}
 
-	msg := tgbotapi.NewMessage(
-		cq.Message.Chat.ID,
-		msgText,
-	)
-
+	msg := tgbotapi.NewMessage(chatID, tools.BuildLogListStr(kind, recs, total, 0, h.cfg.OfficeTZ).String())
 	msg.ParseMode = "MarkdownV2"
+	if total > 0 {
+		msg.ReplyMarkup = tools.BuildLogListKB(kind, userID, recs, total, 0, usecase.LogListPageSize)
+	}
 	go func() {
 		if _, err := h.bot.Send(msg); err != nil {
-			h.log.Error("Failed to edit message on handleLogMy1 list", "err", err)
+			h.log.Error("Failed to send log list", "err", err)
 		}
 	}()
 }
 
-func (h *Handler) handleLogExport(ctx context.Context, msg *tgbotapi.Message) {
-	if err := ctx.Err(); err != nil {
-		h.log.Warn("Context canceled in /handleLogCreate",
-			"user", msg.From.UserName,
-			"chat_id", msg.Chat.ID,
-			"err", ctx.Err())
+// logmy:page:<kind>:<userID>:<offset>
+func (h *Handler) handleLogMyPage(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	parts := strings.Split(cq.Data, ":")
+	if len(parts) < 5 {
 		return
 	}
-	zaprosiPath, sogliPath, err := h.logsUC.CreateExcelReport(ctx)
-	if err != nil {
-		h.reply(msg.Chat.ID, "Ошибка при создании отчета 😔")
-		h.log.Error("CreateExcelReport error", "err", err)
+	kind := domain.LogKind(parts[2])
+	userID, _ := strconv.ParseInt(parts[3], 10, 64)
+	offset, _ := strconv.Atoi(parts[4])
+
+	recs, total, err := h.logsUC.ListUserLogs(ctx, kind, domain.UserID(userID), offset, h.actor(cq.From.ID))
+	switch {
+	case errors.Is(err, domain.ErrNotOwner):
+		h.reply(cq.Message.Chat.ID, string(tools.TextLogOwnerLogsNotAllowed))
+		return
+	case err != nil:
+		h.reply(cq.Message.Chat.ID, string(tools.TextLogListErr))
 		return
 	}
 
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.BuildLogListStr(kind, recs, total, offset, h.cfg.OfficeTZ).String(),
+		tools.BuildLogListKB(kind, domain.UserID(userID), recs, total, offset, usecase.LogListPageSize),
+	)
+	edit.ParseMode = "MarkdownV2"
 	go func() {
-		defer os.Remove(zaprosiPath)
-		doc1 := tgbotapi.NewDocument(msg.Chat.ID, tgbotapi.FilePath(zaprosiPath))
-		doc1.Caption = "📊 Отчёт по запросам"
-		if _, err := h.bot.Send(doc1); err != nil {
-			h.log.Error("Failed to send zapros report", "err", err)
-			h.reply(msg.Chat.ID, "❌ Ошибка при отправке отчёта по запросам")
-			return
-		}
-		h.log.Info("Zapros report sent successfully", "chat_id", msg.Chat.ID)
-	}()
-
-	go func() {
-		defer os.Remove(sogliPath)
-		doc2 := tgbotapi.NewDocument(msg.Chat.ID, tgbotapi.FilePath(sogliPath))
-		doc2.Caption = "📑 Отчёт по соглашениям"
-		if _, err := h.bot.Send(doc2); err != nil {
-			h.log.Error("Failed to send soglasheniya report", "err", err)
-			h.reply(msg.Chat.ID, "❌ Ошибка при отправке отчёта по соглашениям")
-			return
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on log list page", "err", err)
 		}
-		h.log.Info("Soglasheniya report sent successfully", "chat_id", msg.Chat.ID)
 	}()
 }
 
-func (h *Handler) handleLogFind(ctx context.Context, msg *tgbotapi.Message) {
-	// TODO
-	h.reply(msg.Chat.ID, "Команда еще не реализована 😔")
+// logmy:export:<userID> — все записи адвоката за всё время одной книгой Excel.
+func (h *Handler) handleLogMyExport(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	parts := strings.Split(cq.Data, ":")
+	if len(parts) < 3 {
+		return
+	}
+	userID, _ := strconv.ParseInt(parts[2], 10, 64)
+	chatID := cq.Message.Chat.ID
+
+	q := domain.ReportQuery{UserID: domain.UserID(userID)}
+	report, err := h.logsUC.CreateExcelReport(ctx, q, h.actor(cq.From.ID))
+	switch {
+	case errors.Is(err, domain.ErrRecordNotFound):
+		h.reply(chatID, string(tools.TextLogListExportEmpty))
+		return
+	case errors.Is(err, domain.ErrNotOwner):
+		h.reply(chatID, string(tools.TextLogOwnerLogsNotAllowed))
+		return
+	case err != nil:
+		h.reply(chatID, string(tools.TextExportErr))
+		h.log.Error("CreateExcelReport error", "err", err, "userID", userID)
+		return
+	}
+
+	h.sendReport(chatID, report, fmt.Sprintf(tools.TextLogListExportCaption, usecase.ReportPeriodLabel(q)))
 }
 
-/*
+// /find ЭС-2026/0012 — карточка записи журнала.
 func (h *Handler) handleLogFind(ctx context.Context, msg *tgbotapi.Message) {
 	if err := ctx.Err(); err != nil {
 		h.log.Warn("Context canceled in /handleLogFind",
@@ -148,81 +175,102 @@ func (h *Handler) handleLogFind(ctx context.Context, msg *tgbotapi.Message) {
 		return
 	}
 
-	role, err := h.getRole(msg.From.ID)
-	if err != nil {
-		h.log.Warn("Failed to get user role on user", "err", err, "user_id", msg.From.ID, "username", msg.From.UserName)
-		role = tools.Member
+	num := strings.TrimSpace(msg.CommandArguments())
+	if num == "" {
+		h.reply(msg.Chat.ID, string(tools.TextLogFindUsage))
+		return
 	}
 
-	if !tools.CheckRoleIsAdmin(role) {
-		msgText := "Команда доступна только администраторам."
-		h.reply(msg.Chat.ID, msgText)
+	h.sendLogCard(ctx, msg.Chat.ID, num, h.actor(msg.From.ID))
+}
+
+// sendLogCard отправляет карточку записи по номеру с учётом прав.
+func (h *Handler) sendLogCard(ctx context.Context, chatID int64, num string, actor domain.Actor) {
+	rec, err := h.logsUC.FindLog(ctx, num, actor)
+	h.sendLogCardResult(ctx, chatID, num, rec, err, actor)
+}
+
+// sendLogCardResult отправляет найденную карточку или текст ошибки поиска.
+// num — номер, как его запрашивали, для сообщений об ошибке.
+func (h *Handler) sendLogCardResult(ctx context.Context, chatID int64, num string, rec domain.LogRecord, err error, actor domain.Actor) {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInputData):
+		h.reply(chatID, string(tools.TextLogFindUsage))
+		return
+	case errors.Is(err, domain.ErrRecordNotFound):
+		h.reply(chatID, fmt.Sprintf(string(tools.TextLogFindNotFound), tools.StripMarkup(num)))
+		return
+	case errors.Is(err, domain.ErrNotOwner):
+		h.reply(chatID, fmt.Sprintf(string(tools.TextLogFindForbidden), tools.StripMarkup(num)))
+		return
+	case err != nil:
+		h.log.Error("FindLog error", "err", err, "number", num)
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при /find:* `%s`", err.Error()))
+		h.reply(chatID, string(tools.TextLogFindErr))
 		return
 	}
 
-	num := strings.TrimSpace(msg.Text)
+	h.sendLogRecordCard(ctx, chatID, rec, actor)
+}
 
-	// Проверяем длину и префикс
-	if len(num) < 3 {
-		h.reply(msg.Chat.ID, "Некорректный номер. Пример: ЭС12345 или ЭЗ12345")
-		return
+// sendLogRecordCard отправляет карточку уже найденной записи.
+// Кнопки правки и вложений показываются, только если actor может их использовать.
+func (h *Handler) sendLogRecordCard(ctx context.Context, chatID int64, rec domain.LogRecord, actor domain.Actor) {
+	files, err := h.logsUC.LogAttachmentCount(ctx, rec)
+	if err != nil {
+		h.log.Warn("Failed to count log attachments for card", "err", err, "number", rec.Number())
 	}
 
-	// Определяем тип и отделяем числовую часть
-	var (
-		prefix string
-		idStr  string
-	)
+	m := tgbotapi.NewMessage(chatID, tools.BuildLogCardStr(rec, h.cfg.OfficeTZ).String())
+	m.ParseMode = "MarkdownV2"
+	m.ReplyMarkup = tools.BuildLogCardKB(rec, tools.LogCardOpts{
+		Editable:    h.logsUC.CanEditLog(rec, actor) == nil,
+		Attachable:  h.logsUC.CanAttachLog(rec, actor) == nil,
+		Attachments: files,
+	})
+	go func() {
+		if _, err := h.bot.Send(m); err != nil {
+			h.log.Error("Failed to send log card", "err", err)
+		}
+	}()
+}
 
-	switch {
-	case strings.HasPrefix(num, "ЭС"):
-		prefix = "ЭС"
-		idStr = strings.TrimPrefix(num, "ЭС")
-	case strings.HasPrefix(num, "ЭЗ"):
-		prefix = "ЭЗ"
-		idStr = strings.TrimPrefix(num, "ЭЗ")
-	default:
-		h.reply(msg.Chat.ID, "Введите корректный номер: ЭС12345 или ЭЗ12345")
+// logcard:copy:<kind>:<id> — номер отдельным сообщением моноширинным шрифтом,
+// чтобы его можно было скопировать одним нажатием.
+func (h *Handler) handleLogCardCopy(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	parts := strings.Split(cq.Data, ":")
+	if len(parts) < 4 {
 		return
 	}
-
-	// Преобразуем числовую часть в int64
-	id, err := strconv.ParseInt(idStr, 10, 64)
+	id, _ := strconv.ParseInt(parts[3], 10, 64)
+	rec, err := h.logsUC.GetLog(ctx, domain.LogKind(parts[2]), id, h.actor(cq.From.ID))
 	if err != nil {
-		h.reply(msg.Chat.ID, "Некорректный формат номера. Пример: ЭС12345 или ЭЗ12345")
+		h.log.Warn("Failed to get log record for copy", "err", err, "data", cq.Data)
+		h.reply(cq.Message.Chat.ID, string(tools.TextLogFindErr))
 		return
 	}
 
-	// Обработка в зависимости от типа
-	switch prefix {
-	case "ЭС":
-		record, err := h.logsUC.GetSoglasheniyaById(ctx, id)
-		// if err != nil {
-		// 	h.reply(msg.Chat.ID, "Ошибка при поиске соглашения")
-		// 	h.log.Error("GetSoglasheniyaById error", "err", err)
-		// 	h.notifyAdmin("Не получилось обработать LogFind из-за ошибки в UC")
-		// 	return
-		// }
-		// h.reply(msg.Chat.ID, fmt.Sprintf("Найден номер соглашения: ЭС%d", id))
+	m := tgbotapi.NewMessage(cq.Message.Chat.ID, tools.SafeText(fmt.Sprintf(tools.TextLogCopyNumber, rec.Number())).String())
+	m.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(m); err != nil {
+			h.log.Error("Failed to send log number", "err", err)
+		}
+	}()
+}
 
-	case "ЭЗ":
-		record, err := h.logsUC.GetZaprosById(ctx, id)
-		// if err != nil {
-		// 	h.reply(msg.Chat.ID, "Ошибка при поиске запроса")
-		// 	h.log.Error("GetZaprosById error", "err", err)
-		// 	h.notifyAdmin("Не получилось обработать LogFind из-за ошибки в UC")
-		// 	return
-		// }
-		// h.reply(msg.Chat.ID, fmt.Sprintf("Найден номер запроса: ЭЗ%d", id))
-	}
+// logcard:owner:<kind>:<userID> — остальные записи того же адвоката.
+func (h *Handler) handleLogCardOwner(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("handleLogCardOwner", "data", cq.Data, "user", cq.From.UserName)
 
-	if err != nil {
-		h.reply(msg.Chat.ID, "Ошибка при поиске соглашения")
-		h.log.Error("GetSoglasheniyaById error", "err", err)
-		h.notifyAdmin("Не получилось обработать LogFind из-за ошибки в UC")
+	parts := strings.Split(cq.Data, ":")
+	if len(parts) < 4 {
 		return
 	}
-	// Обработать найденную запись. Вывести сведения
-	h.reply(msg.Chat.ID, fmt.Sprintf("Найден номер запроса: ЭЗ%d", id))
+	ownerID, _ := strconv.ParseInt(parts[3], 10, 64)
+
+	h.sendLogPage(ctx, cq.Message.Chat.ID, domain.LogKind(parts[2]), domain.UserID(ownerID), cq.From.ID)
 }
-*/
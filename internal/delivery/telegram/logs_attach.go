@@ -0,0 +1,249 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+// Сканы документов к записям журналов: при создании записи (до
+// подтверждения) и позже из карточки записи.
+
+// attachmentFromMessage — файл или фото из сообщения. У фото берётся
+// самый крупный размер.
+func attachmentFromMessage(msg *tgbotapi.Message) (domain.LogAttachment, bool) {
+	switch {
+	case msg.Document != nil:
+		return domain.LogAttachment{
+			Type:         domain.AttachmentDocument,
+			FileID:       msg.Document.FileID,
+			FileUniqueID: msg.Document.FileUniqueID,
+			FileName:     msg.Document.FileName,
+			MimeType:     msg.Document.MimeType,
+			FileSize:     int64(msg.Document.FileSize),
+		}, true
+	case len(msg.Photo) > 0:
+		p := msg.Photo[len(msg.Photo)-1]
+		return domain.LogAttachment{
+			Type:         domain.AttachmentPhoto,
+			FileID:       p.FileID,
+			FileUniqueID: p.FileUniqueID,
+			MimeType:     "image/jpeg",
+			FileSize:     int64(p.FileSize),
+		}, true
+	}
+	return domain.LogAttachment{}, false
+}
+
+// logAttachErrText — текст для пользователя по ошибке приложения файла.
+func (h *Handler) logAttachErrText(err error) tools.SafeText {
+	switch {
+	case errors.Is(err, domain.ErrAttachmentExists):
+		return tools.TextLogAttachExists
+	case errors.Is(err, domain.ErrAttachmentLimit):
+		return tools.SafeText(fmt.Sprintf(string(tools.TextLogAttachLimit), domain.MaxLogAttachments))
+	case errors.Is(err, domain.ErrNotOwner):
+		return tools.TextLogAttachForbidden
+	case errors.Is(err, domain.ErrRecordVoided):
+		return tools.TextLogAttachVoided
+	default:
+		h.log.Error("Log attach error", "err", err)
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при приложении файла к записи:* `%s`", err.Error()))
+		return tools.TextLogAttachErr
+	}
+}
+
+// attachSessionFiles прикладывает к только что созданной записи файлы,
+// присланные до подтверждения. Возвращает, сколько удалось сохранить.
+func (h *Handler) attachSessionFiles(ctx context.Context, rec domain.LogRecord, files []domain.LogAttachment, actor domain.Actor) int {
+	saved := 0
+	for _, a := range files {
+		a.Kind, a.RecordID = rec.Kind, rec.ID
+		if _, _, err := h.logsUC.AttachLog(ctx, a, actor); err != nil {
+			h.log.Error("Failed to attach session file", "err", err, "number", rec.Number())
+			continue
+		}
+		saved++
+	}
+	return saved
+}
+
+// Файл на шаге подтверждения создания записи: запоминаем его в сессии и
+// присылаем подтверждение заново, уже с числом вложений.
+func (h *Handler) handleLogCreateFile(ctx context.Context, msg *tgbotapi.Message) {
+	session := h.logSession.Get(ctx, msg.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, msg.Chat.ID, msg.From.ID, domain.SessionLogs)
+		return
+	}
+
+	a, ok := attachmentFromMessage(msg)
+	if !ok {
+		h.sendSafe(msg.Chat.ID, tools.TextLogAttachConfirm)
+		return
+	}
+	for _, f := range session.Files {
+		if f.FileUniqueID == a.FileUniqueID {
+			h.sendSafe(msg.Chat.ID, tools.TextLogAttachExists)
+			return
+		}
+	}
+	if len(session.Files) >= domain.MaxLogAttachments {
+		h.sendSafe(msg.Chat.ID, tools.SafeText(fmt.Sprintf(string(tools.TextLogAttachLimit), domain.MaxLogAttachments)))
+		return
+	}
+	session.Files = append(session.Files, a)
+
+	edit := tgbotapi.NewEditMessageReplyMarkup(msg.Chat.ID, session.MessageID, tools.BuildBlankInlineKB())
+	if _, err := h.bot.Send(edit); err != nil {
+		h.log.Error("Failed to edit message on handleLogCreateFile", "err", err)
+	}
+
+	// Отправляем синхронно: следующий файл (например, из альбома) должен
+	// убрать кнопки уже с этого сообщения.
+	newMsg := tgbotapi.NewMessage(msg.Chat.ID, tools.BuildLogConfirmationStr(session).String())
+	newMsg.ParseMode = "MarkdownV2"
+	newMsg.ReplyMarkup = tools.BuildConfirmationKB("log")
+	sent, err := h.bot.Send(newMsg)
+	if err != nil {
+		h.log.Error("Failed to send a new message on handleLogCreateFile", "err", err)
+		return
+	}
+	session.MessageID = sent.MessageID
+}
+
+// logattach:start:<kind>:<id> — приём файлов к существующей записи.
+func (h *Handler) handleLogAttachStart(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("handleLogAttachStart", "data", cq.Data, "user", cq.From.UserName)
+
+	kind, id, ok := parseLogRef(strings.Split(cq.Data, ":")[2:])
+	if !ok {
+		return
+	}
+	actor := h.actor(cq.From.ID)
+	rec, err := h.logsUC.GetLog(ctx, kind, id, actor)
+	if err == nil {
+		err = h.logsUC.CanAttachLog(rec, actor)
+	}
+	if err != nil {
+		h.sendSafe(cq.Message.Chat.ID, h.logAttachErrText(err))
+		return
+	}
+
+	m := tgbotapi.NewMessage(cq.Message.Chat.ID,
+		tools.SafeText(fmt.Sprintf(string(tools.TextLogAttachAsk), rec.Number(), domain.MaxLogAttachments)).String())
+	m.ParseMode = "MarkdownV2"
+	m.ReplyMarkup = tools.BuildLogAttachDoneKB(rec)
+	sent, err := h.bot.Send(m)
+	if err != nil {
+		h.log.Error("Failed to send log attach prompt", "err", err)
+		return
+	}
+
+	h.goTo(ctx, cq.From.ID, stepLogAttachFiles)
+	h.logSession.Set(ctx, &tools.LogsSession{
+		UserID:    cq.From.ID,
+		MessageID: sent.MessageID,
+		Type:      string(kind),
+		EditID:    id,
+	})
+}
+
+// Файл к существующей записи.
+func (h *Handler) handleLogAttachFile(ctx context.Context, msg *tgbotapi.Message) {
+	session := h.logSession.Get(ctx, msg.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, msg.Chat.ID, msg.From.ID, domain.SessionLogs)
+		return
+	}
+
+	a, ok := attachmentFromMessage(msg)
+	if !ok {
+		h.sendSafe(msg.Chat.ID, tools.TextLogAttachNotFile)
+		return
+	}
+	a.Kind, a.RecordID = domain.LogKind(session.Type), session.EditID
+
+	_, count, err := h.logsUC.AttachLog(ctx, a, h.actor(msg.From.ID))
+	if err != nil {
+		h.sendSafe(msg.Chat.ID, h.logAttachErrText(err))
+		return
+	}
+	h.sendSafe(msg.Chat.ID, tools.SafeText(fmt.Sprintf(string(tools.TextLogAttachSaved), count, domain.MaxLogAttachments)))
+}
+
+// logattach:done:<kind>:<id> — приём файлов закончен, присылаем карточку.
+func (h *Handler) handleLogAttachDone(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	if h.inStep(ctx, cq.From.ID, stepLogAttachFiles) {
+		h.logSession.Delete(ctx, cq.From.ID)
+		h.endFlow(ctx, cq.From.ID)
+	}
+	edit := tgbotapi.NewEditMessageReplyMarkup(cq.Message.Chat.ID, cq.Message.MessageID, tools.BuildBlankInlineKB())
+	if _, err := h.bot.Send(edit); err != nil {
+		h.log.Error("Failed to edit message on handleLogAttachDone", "err", err)
+	}
+
+	kind, id, ok := parseLogRef(strings.Split(cq.Data, ":")[2:])
+	if !ok {
+		return
+	}
+	ref := domain.LogRecord{Kind: kind, ID: id}
+	actor := h.actor(cq.From.ID)
+	rec, err := h.logsUC.GetLog(ctx, kind, id, actor)
+	h.sendLogCardResult(ctx, cq.Message.Chat.ID, ref.Number(), rec, err, actor)
+}
+
+// logattach:list:<kind>:<id> — все вложения записи отдельными сообщениями.
+func (h *Handler) handleLogAttachList(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	kind, id, ok := parseLogRef(strings.Split(cq.Data, ":")[2:])
+	if !ok {
+		return
+	}
+	rec, list, err := h.logsUC.LogAttachments(ctx, kind, id, h.actor(cq.From.ID))
+	switch {
+	case errors.Is(err, domain.ErrNotOwner):
+		h.sendSafe(cq.Message.Chat.ID, tools.TextLogOwnerLogsNotAllowed)
+		return
+	case err != nil:
+		h.log.Error("LogAttachments error", "err", err, "kind", kind, "id", id)
+		h.sendSafe(cq.Message.Chat.ID, tools.TextLogAttachListErr)
+		return
+	case len(list) == 0:
+		h.sendSafe(cq.Message.Chat.ID, tools.SafeText(fmt.Sprintf(string(tools.TextLogAttachEmpty), rec.Number())))
+		return
+	}
+
+	chatID := cq.Message.Chat.ID
+	go func() {
+		for i, a := range list {
+			caption := fmt.Sprintf(tools.TextLogAttachCaption, rec.Number(), i+1, len(list))
+			if a.FileName != "" {
+				caption += "\n" + a.FileName
+			}
+
+			var c tgbotapi.Chattable
+			if a.Type == domain.AttachmentPhoto {
+				p := tgbotapi.NewPhoto(chatID, tgbotapi.FileID(a.FileID))
+				p.Caption = caption
+				c = p
+			} else {
+				d := tgbotapi.NewDocument(chatID, tgbotapi.FileID(a.FileID))
+				d.Caption = caption
+				c = d
+			}
+			if _, err := h.bot.Send(c); err != nil {
+				h.log.Error("Failed to send log attachment", "err", err, "attachmentID", a.ID)
+			}
+		}
+	}()
+}
@@ -5,6 +5,7 @@ import (
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
 )
 
 func (h *Handler) handleLogCreateBack(ctx context.Context, cq *tgbotapi.CallbackQuery) {
@@ -55,18 +56,18 @@ func (h *Handler) handleLogCalendarBack(ctx context.Context, cq *tgbotapi.Callba
 func (h *Handler) handleLogStep2Back(ctx context.Context, cq *tgbotapi.CallbackQuery) {
 	h.answerCB(cq, "")
 	h.log.Debug("User clicked назад на handleLogCreate2. обработчик: handleLogStep2Back") // это он тыкнул дату и должен вводить имя, но мы вернем его на календарь
-	session := h.logSession.Get(cq.From.ID)
+	session := h.logSession.Get(ctx, cq.From.ID)
 	if session == nil {
-		h.reply(cq.Message.Chat.ID, "Сессия не найдена")
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionLogs)
 		return
 	}
-	session.State = tools.StateProcessingLogCreating
+	h.goTo(ctx, session.UserID, stepLogDate)
 
 	edit := tgbotapi.NewEditMessageTextAndMarkup(
 		cq.Message.Chat.ID,
 		cq.Message.MessageID,
 		tools.TextLogCalendar.String(),
-		tools.BuildLogCalendarKB(0),
+		tools.BuildLogCalendarKB("log", 0),
 	)
 	edit.ParseMode = "MarkdownV2"
 	go func() {
@@ -80,14 +81,14 @@ func (h *Handler) handleLogStep3Back(ctx context.Context, cq *tgbotapi.CallbackQ
 	h.answerCB(cq, "")
 	h.log.Debug("User clicked назад на handleLogCreate3. обработчик: handleLogStep3Back") // это пользователь уже на вводе доверителя
 
-	session := h.logSession.Get(cq.From.ID)
+	session := h.logSession.Get(ctx, cq.From.ID)
 	if session == nil {
-		h.reply(cq.Message.Chat.ID, "Сессия не найдена")
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionLogs)
 		return
 	}
 
 	if session.Registration { // Если человек до этого вводил ФИО, то вернуть на ввод ФИО
-		session.State = tools.StateInputingName
+		h.goTo(ctx, session.UserID, stepLogName)
 		edit := tgbotapi.NewEditMessageTextAndMarkup(
 			cq.Message.Chat.ID,
 			cq.Message.MessageID,
@@ -106,12 +107,12 @@ func (h *Handler) handleLogStep3Back(ctx context.Context, cq *tgbotapi.CallbackQ
 		return
 	} // Иначе вернуть на выбор даты
 
-	session.State = tools.StateProcessingLogCreating
+	h.goTo(ctx, session.UserID, stepLogDate)
 	edit := tgbotapi.NewEditMessageTextAndMarkup(
 		cq.Message.Chat.ID,
 		cq.Message.MessageID,
 		tools.TextLogCalendar.String(),
-		tools.BuildLogCalendarKB(0),
+		tools.BuildLogCalendarKB("log", 0),
 	)
 	edit.ParseMode = "MarkdownV2"
 	go func() {
@@ -124,12 +125,12 @@ func (h *Handler) handleLogStep3Back(ctx context.Context, cq *tgbotapi.CallbackQ
 func (h *Handler) handleLogStep4Back(ctx context.Context, cq *tgbotapi.CallbackQuery) {
 	h.answerCB(cq, "")
 	h.log.Debug("User clicked назад на handleLogCreate4. обработчик: handleLogStep4Back") // это пользователь уже на вводе комментария
-	session := h.logSession.Get(cq.From.ID)
+	session := h.logSession.Get(ctx, cq.From.ID)
 	if session == nil {
-		h.reply(cq.Message.Chat.ID, "Сессия не найдена")
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionLogs)
 		return
 	}
-	session.State = tools.StateInputingDoveritel
+	h.goTo(ctx, session.UserID, stepLogDoveritel)
 
 	replyKB := tgbotapi.NewInlineKeyboardMarkup(
 		[]tgbotapi.InlineKeyboardButton{
@@ -154,12 +155,12 @@ func (h *Handler) handleLogStep4Back(ctx context.Context, cq *tgbotapi.CallbackQ
 func (h *Handler) handleLogConfirmBack(ctx context.Context, cq *tgbotapi.CallbackQuery) {
 	h.answerCB(cq, "")
 	h.log.Debug("User clicked назад на handleLogCreate5. обработчик: handleLogConfirmBack") // Парсер комментария и Подтверждение создания
-	session := h.logSession.Get(cq.From.ID)
+	session := h.logSession.Get(ctx, cq.From.ID)
 	if session == nil {
-		h.reply(cq.Message.Chat.ID, "Сессия не найдена")
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionLogs)
 		return
 	}
-	session.State = tools.StageInputingComment
+	h.goTo(ctx, session.UserID, stepLogComment)
 
 	replyKB := tgbotapi.NewInlineKeyboardMarkup(
 		[]tgbotapi.InlineKeyboardButton{
@@ -2,6 +2,7 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"strconv"
 	"strings"
 	"time"
@@ -44,14 +45,14 @@ func (h *Handler) handleLogCreate0(ctx context.Context, msg *tgbotapi.Message) {
 // Step 1_0 (тип выбран)
 // Хендлер выбора типа записи и переход к выбору даты
 func (h *Handler) handleLogCreate1_0(ctx context.Context, cq *tgbotapi.CallbackQuery) {
-	h.log.Debug("recieved log command handleLogCreate1_0", "session", h.logSession.Get(cq.From.ID))
+	h.log.Debug("recieved log command handleLogCreate1_0", "session", h.logSession.Get(ctx, cq.From.ID))
 	h.answerCB(cq, "")
 
 	parts := strings.Split(cq.Data, ":")
 	logType := parts[2]
 
-	h.logSession.Set(&tools.LogsSession{
-		State:     tools.StateProcessingLogCreating,
+	h.goTo(ctx, cq.From.ID, stepLogDate)
+	h.logSession.Set(ctx, &tools.LogsSession{
 		Type:      logType,
 		UserID:    cq.From.ID,
 		MessageID: cq.Message.MessageID,
@@ -61,7 +62,7 @@ func (h *Handler) handleLogCreate1_0(ctx context.Context, cq *tgbotapi.CallbackQ
 		cq.Message.Chat.ID,
 		cq.Message.MessageID,
 		tools.TextLogCalendar.String(),
-		tools.BuildLogCalendarKB(0),
+		tools.BuildLogCalendarKB("log", 0),
 	)
 	edit.ParseMode = "MarkdownV2"
 	go func() {
@@ -74,7 +75,7 @@ func (h *Handler) handleLogCreate1_0(ctx context.Context, cq *tgbotapi.CallbackQ
 // Step 1_1 (календарь сдвинут)
 // Хендлер сдвига календаря
 func (h *Handler) handleLogСreate1_1(ctx context.Context, cq *tgbotapi.CallbackQuery) {
-	h.log.Debug("recieved log command handleLogСreate1_1", "session", h.logSession.Get(cq.From.ID))
+	h.log.Debug("recieved log command handleLogСreate1_1", "session", h.logSession.Get(ctx, cq.From.ID))
 	h.answerCB(cq, "")
 
 	// 1. Парсим направление навигации
@@ -89,7 +90,7 @@ func (h *Handler) handleLogСreate1_1(ctx context.Context, cq *tgbotapi.Callback
 	editMarkup := tgbotapi.NewEditMessageReplyMarkup(
 		cq.Message.Chat.ID,
 		cq.Message.MessageID,
-		tools.BuildLogCalendarKB(shift),
+		tools.BuildLogCalendarKB("log", shift),
 	)
 	go func() {
 		if _, err := h.bot.Request(editMarkup); err != nil {
@@ -101,7 +102,7 @@ func (h *Handler) handleLogСreate1_1(ctx context.Context, cq *tgbotapi.Callback
 // Step 2 (дата выбрана)
 // Парсер выбора даты и переход к вводу ФИО/Доверителя
 func (h *Handler) handleLogCreate2(ctx context.Context, cq *tgbotapi.CallbackQuery) {
-	h.log.Debug("recieved log command handleLogCreate2", "session", h.logSession.Get(cq.From.ID))
+	h.log.Debug("recieved log command handleLogCreate2", "session", h.logSession.Get(ctx, cq.From.ID))
 	h.answerCB(cq, "")
 
 	parts := strings.Split(cq.Data, ":")
@@ -114,9 +115,9 @@ func (h *Handler) handleLogCreate2(ctx context.Context, cq *tgbotapi.CallbackQue
 		return
 	}
 
-	session := h.logSession.Get(cq.From.ID)
+	session := h.logSession.Get(ctx, cq.From.ID)
 	if session == nil {
-		h.reply(cq.Message.Chat.ID, "Сессия не найдена")
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionLogs)
 		return
 	}
 
@@ -126,8 +127,16 @@ func (h *Handler) handleLogCreate2(ctx context.Context, cq *tgbotapi.CallbackQue
 
 	// Запрашивается, либо ввод ФИО
 	// либо ввод ДОВЕРИТЕЛЯ
-	if user, err := h.logsUC.GetUser(ctx, cq.From.ID); err == nil { // ФИО есть в БД
-		session.State = tools.StateInputingDoveritel
+	user, err := h.logsUC.GetUser(ctx, cq.From.ID)
+	if err == nil && !user.Active { // адвокат отключён администратором
+		h.logSession.Delete(ctx, cq.From.ID)
+		h.endFlow(ctx, cq.From.ID)
+		h.reply(cq.Message.Chat.ID, string(tools.TextLogUserInactive))
+		return
+	}
+
+	if err == nil { // ФИО есть в БД
+		h.goTo(ctx, session.UserID, stepLogDoveritel)
 		session.UserName = user.FIO
 		edit = tgbotapi.NewEditMessageTextAndMarkup(
 			cq.Message.Chat.ID,
@@ -139,7 +148,7 @@ func (h *Handler) handleLogCreate2(ctx context.Context, cq *tgbotapi.CallbackQue
 				}),
 		)
 	} else { // ФИО нет в БД, запрашиваем ввод
-		session.State = tools.StateInputingName
+		h.goTo(ctx, session.UserID, stepLogName)
 		session.Registration = true
 		edit = tgbotapi.NewEditMessageTextAndMarkup(
 			cq.Message.Chat.ID,
@@ -163,15 +172,15 @@ func (h *Handler) handleLogCreate2(ctx context.Context, cq *tgbotapi.CallbackQue
 // Step 3 ФИО введено
 // Парсер Фио и ввод доверителя
 func (h *Handler) handleLogCreate3(ctx context.Context, msg *tgbotapi.Message) {
-	h.log.Debug("recieved log command handleLogCreate3", "session", h.logSession.Get(msg.From.ID))
+	h.log.Debug("recieved log command handleLogCreate3", "session", h.logSession.Get(ctx, msg.From.ID))
 	FIO := strings.TrimSpace(msg.Text)
-	session := h.logSession.Get(msg.From.ID)
+	session := h.logSession.Get(ctx, msg.From.ID)
 	if session == nil {
-		h.reply(msg.Chat.ID, "Сессия не найдена")
+		h.sessionLost(ctx, msg.Chat.ID, msg.From.ID, domain.SessionLogs)
 		return
 	}
 
-	session.State = tools.StateInputingDoveritel // следующий шаг - ввод доверителя
+	h.goTo(ctx, session.UserID, stepLogDoveritel) // следующий шаг - ввод доверителя
 	session.UserName = FIO
 
 	edit := tgbotapi.NewEditMessageReplyMarkup(
@@ -207,18 +216,19 @@ func (h *Handler) handleLogCreate3(ctx context.Context, msg *tgbotapi.Message) {
 }
 
 // Step 4 Доверитель введен. Сразу сюда, если ФИО есть в БД
-// Парсер Доверителя и Ввод комментария
+// Парсер Доверителя, подсказка похожих клиентов и Ввод комментария
 func (h *Handler) handleLogCreate4(ctx context.Context, msg *tgbotapi.Message) {
-	h.log.Debug("recieved log command handleLogCreate4", "session", h.logSession.Get(msg.From.ID))
+	h.log.Debug("recieved log command handleLogCreate4", "session", h.logSession.Get(ctx, msg.From.ID))
 	Doveritel := strings.TrimSpace(msg.Text)
-	session := h.logSession.Get(msg.From.ID)
+	session := h.logSession.Get(ctx, msg.From.ID)
 	if session == nil {
-		h.reply(msg.Chat.ID, "Сессия не найдена")
+		h.sessionLost(ctx, msg.Chat.ID, msg.From.ID, domain.SessionLogs)
 		return
 	}
 
-	session.State = tools.StageInputingComment
+	h.goTo(ctx, session.UserID, stepLogComment)
 	session.Doveritel = Doveritel
+	session.ClientID = 0
 
 	edit := tgbotapi.NewEditMessageReplyMarkup(
 		msg.Chat.ID,
@@ -229,15 +239,19 @@ func (h *Handler) handleLogCreate4(ctx context.Context, msg *tgbotapi.Message) {
 		h.log.Error("Failed to edit message on handleLogCreate3", "err", err)
 	}
 
+	// Подсказки не обязательны: при ошибке реестра просто идём дальше
 	newMsg := tgbotapi.NewMessage(msg.Chat.ID, tools.TextLogAskComment.String())
 	newMsg.ParseMode = "MarkdownV2"
-
-	replyKB := tgbotapi.NewInlineKeyboardMarkup(
+	newMsg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
 		[]tgbotapi.InlineKeyboardButton{
 			tools.BuildBackInlineKBButton("log:step4_back"),
 		},
 	)
-	newMsg.ReplyMarkup = replyKB
+	if clients, err := h.logsUC.SuggestClients(ctx, Doveritel); err == nil && usecase.NeedsClientChoice(Doveritel, clients) {
+		h.goTo(ctx, session.UserID, stepLogClient)
+		newMsg.Text = tools.TextLogClientSuggest.String()
+		newMsg.ReplyMarkup = tools.BuildLogClientSuggestKB(clients)
+	}
 
 	go func() {
 		sentMsg, err := h.bot.Send(newMsg)
@@ -252,18 +266,54 @@ func (h *Handler) handleLogCreate4(ctx context.Context, msg *tgbotapi.Message) {
 	}()
 }
 
+// Step 4_1 (клиент выбран из подсказок)
+// log:client:<clientID>, 0 — новый доверитель. Дальше — ввод комментария
+func (h *Handler) handleLogCreate4_1(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.log.Debug("recieved log command handleLogCreate4_1", "session", h.logSession.Get(ctx, cq.From.ID))
+	h.answerCB(cq, "")
+
+	parts := strings.Split(cq.Data, ":")
+	clientID, _ := strconv.ParseInt(parts[2], 10, 64)
+
+	session := h.logSession.Get(ctx, cq.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionLogs)
+		return
+	}
+
+	h.goTo(ctx, session.UserID, stepLogComment)
+	session.ClientID = clientID
+	session.MessageID = cq.Message.MessageID
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.TextLogAskComment.String(),
+		tgbotapi.NewInlineKeyboardMarkup(
+			[]tgbotapi.InlineKeyboardButton{
+				tools.BuildBackInlineKBButton("log:step4_back"),
+			}),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on handleLogCreate4_1", "err", err)
+		}
+	}()
+}
+
 // Step 5 Комментарий введен.
 // Парсер комментария и Подтверждение создания
 func (h *Handler) handleLogCreate5(ctx context.Context, msg *tgbotapi.Message) {
-	h.log.Debug("recieved log command handleLogCreate5", "session", h.logSession.Get(msg.From.ID))
+	h.log.Debug("recieved log command handleLogCreate5", "session", h.logSession.Get(ctx, msg.From.ID))
 	comment := strings.TrimSpace(msg.Text)
-	session := h.logSession.Get(msg.From.ID)
+	session := h.logSession.Get(ctx, msg.From.ID)
 	if session == nil {
-		h.reply(msg.Chat.ID, "Сессия не найдена")
+		h.sessionLost(ctx, msg.Chat.ID, msg.From.ID, domain.SessionLogs)
 		return
 	}
 
-	session.State = tools.StateCreateConfirming
+	h.goTo(ctx, session.UserID, stepLogConfirm)
 	session.Comment = comment
 
 	edit := tgbotapi.NewEditMessageReplyMarkup(
@@ -295,15 +345,15 @@ func (h *Handler) handleLogCreate5(ctx context.Context, msg *tgbotapi.Message) {
 // Step 6 Финиш
 // Парсер подтверждения и Создание записи
 func (h *Handler) handleLogCreate6(ctx context.Context, cq *tgbotapi.CallbackQuery) {
-	h.log.Debug("recieved log command handleLogCreate6", "session", h.logSession.Get(cq.From.ID))
+	h.log.Debug("recieved log command handleLogCreate6", "session", h.logSession.Get(ctx, cq.From.ID))
 	h.answerCB(cq, "")
 
 	parts := strings.Split(cq.Data, ":")
 	confirm, _ := strconv.ParseInt(parts[2], 10, 64) // 0 || 1
 
-	session := h.logSession.Get(cq.From.ID)
+	session := h.logSession.Get(ctx, cq.From.ID)
 	if session == nil {
-		h.reply(cq.Message.Chat.ID, "Сессия не найдена")
+		h.sessionLost(ctx, cq.Message.Chat.ID, cq.From.ID, domain.SessionLogs)
 		return
 	}
 
@@ -314,6 +364,7 @@ func (h *Handler) handleLogCreate6(ctx context.Context, cq *tgbotapi.CallbackQue
 		Date:      session.Date,
 		Doveritel: session.Doveritel,
 		Comment:   session.Comment,
+		ClientID:  domain.ClientID(session.ClientID),
 	}
 
 	edit := tgbotapi.NewEditMessageReplyMarkup(
@@ -334,16 +385,20 @@ func (h *Handler) handleLogCreate6(ctx context.Context, cq *tgbotapi.CallbackQue
 				h.notifyAdmin("Ошибка при сохранении ФИО")
 			}
 		}
-		num, err := h.logsUC.CreateLog(ctx, cmd)
-		if err != nil {
+		rec, err := h.logsUC.CreateLog(ctx, cmd)
+		if errors.Is(err, domain.ErrUserInactive) {
+			replyText = tools.TextLogUserInactive.String()
+		} else if err != nil {
 			replyText = tools.TextLogError.String()
 		} else {
-			replyText = tools.BuildLogConfirmedStr(session.Type, num).String()
+			saved := h.attachSessionFiles(ctx, rec, session.Files, h.actor(cq.From.ID))
+			replyText = tools.BuildLogConfirmedStr(rec.Number(), saved, len(session.Files)).String()
 		}
 	} else {
 		replyText = tools.TextLogNo.String()
 	}
-	h.sessions.Delete(cq.From.ID)
+	h.logSession.Delete(ctx, cq.From.ID)
+	h.endFlow(ctx, cq.From.ID)
 
 	role, err := h.getRole(cq.From.ID)
 	if err != nil {
@@ -0,0 +1,294 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/internal/usecase"
+)
+
+// Правка и аннулирование записей журналов из карточки записи.
+
+// parseLogRef разбирает хвост callback-данных вида <kind>:<id>.
+func parseLogRef(parts []string) (domain.LogKind, int64, bool) {
+	if len(parts) < 2 {
+		return "", 0, false
+	}
+	kind := domain.LogKind(parts[0])
+	if kind != domain.LogKindSogl && kind != domain.LogKindZapros {
+		return "", 0, false
+	}
+	id, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return kind, id, true
+}
+
+// logEditErrText — текст для пользователя по ошибке правки/аннулирования.
+func (h *Handler) logEditErrText(err error) string {
+	switch {
+	case errors.Is(err, domain.ErrNotOwner):
+		return string(tools.TextLogEditForbidden)
+	case errors.Is(err, domain.ErrEditWindowOver):
+		return fmt.Sprintf(string(tools.TextLogEditWindowOver), tools.FormatDuration(h.logsUC.EditWindow()))
+	case errors.Is(err, domain.ErrRecordVoided):
+		return string(tools.TextLogEditVoided)
+	case errors.Is(err, domain.ErrRecordNotFound):
+		return string(tools.TextLogEditErr)
+	default:
+		h.log.Error("Log edit error", "err", err)
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при правке записи журнала:* `%s`", err.Error()))
+		return string(tools.TextLogEditErr)
+	}
+}
+
+// editableLog находит запись и проверяет, что пользователь может её изменить.
+func (h *Handler) editableLog(ctx context.Context, kind domain.LogKind, id int64, actor domain.Actor) (domain.LogRecord, error) {
+	rec, err := h.logsUC.GetLog(ctx, kind, id, actor)
+	if err != nil {
+		return domain.LogRecord{}, err
+	}
+	if err := h.logsUC.CanEditLog(rec, actor); err != nil {
+		return domain.LogRecord{}, err
+	}
+	return rec, nil
+}
+
+// actorName — ФИО из справочника адвокатов, иначе имя в Telegram.
+func (h *Handler) actorName(ctx context.Context, from *tgbotapi.User) string {
+	if user, err := h.logsUC.GetUser(ctx, from.ID); err == nil && user.FIO != "" {
+		return user.FIO
+	}
+	if from.UserName != "" {
+		return "@" + from.UserName
+	}
+	return strings.TrimSpace(from.FirstName + " " + from.LastName)
+}
+
+// logedit:start:<kind>:<id> — выбор поля для правки.
+func (h *Handler) handleLogEditStart(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("handleLogEditStart", "data", cq.Data, "user", cq.From.UserName)
+
+	kind, id, ok := parseLogRef(strings.Split(cq.Data, ":")[2:])
+	if !ok {
+		return
+	}
+	rec, err := h.editableLog(ctx, kind, id, h.actor(cq.From.ID))
+	if err != nil {
+		h.reply(cq.Message.Chat.ID, h.logEditErrText(err))
+		return
+	}
+
+	m := tgbotapi.NewMessage(cq.Message.Chat.ID,
+		tools.SafeText(fmt.Sprintf(string(tools.TextLogEditChooseField), rec.Number())).String())
+	m.ParseMode = "MarkdownV2"
+	m.ReplyMarkup = tools.BuildLogEditFieldKB(rec)
+	go func() {
+		if _, err := h.bot.Send(m); err != nil {
+			h.log.Error("Failed to send log edit field choice", "err", err)
+		}
+	}()
+}
+
+// logedit:field:<field>:<kind>:<id> — запрос нового значения поля.
+func (h *Handler) handleLogEditField(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	parts := strings.Split(cq.Data, ":")
+	if len(parts) < 5 {
+		return
+	}
+	field := domain.LogField(parts[2])
+	kind, id, ok := parseLogRef(parts[3:])
+	if !ok || !field.Valid() {
+		return
+	}
+	rec, err := h.editableLog(ctx, kind, id, h.actor(cq.From.ID))
+	if err != nil {
+		h.reply(cq.Message.Chat.ID, h.logEditErrText(err))
+		return
+	}
+
+	h.goTo(ctx, cq.From.ID, stepLogEditValue)
+	h.logSession.Set(ctx, &tools.LogsSession{
+		UserID:    cq.From.ID,
+		MessageID: cq.Message.MessageID,
+		Type:      string(kind),
+		EditID:    id,
+		EditField: string(field),
+	})
+
+	text := tools.SafeText(fmt.Sprintf(string(tools.TextLogEditAskValue),
+		tools.LogFieldName(field), rec.Number(), tools.StripMarkup(rec.Value(field))))
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		text.String(),
+		tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(tools.BuildLogEditCancelButton(rec))),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on handleLogEditField", "err", err)
+		}
+	}()
+}
+
+// logvoid:start:<kind>:<id> — запрос причины аннулирования.
+func (h *Handler) handleLogVoidStart(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("handleLogVoidStart", "data", cq.Data, "user", cq.From.UserName)
+
+	kind, id, ok := parseLogRef(strings.Split(cq.Data, ":")[2:])
+	if !ok {
+		return
+	}
+	rec, err := h.editableLog(ctx, kind, id, h.actor(cq.From.ID))
+	if err != nil {
+		h.reply(cq.Message.Chat.ID, h.logEditErrText(err))
+		return
+	}
+
+	m := tgbotapi.NewMessage(cq.Message.Chat.ID,
+		tools.SafeText(fmt.Sprintf(string(tools.TextLogVoidAskReason), rec.Number())).String())
+	m.ParseMode = "MarkdownV2"
+	m.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(tools.BuildLogEditCancelButton(rec)))
+	// Синхронно: сессия должна быть сохранена до конца обработки апдейта
+	sent, err := h.bot.Send(m)
+	if err != nil {
+		h.log.Error("Failed to send void reason prompt", "err", err)
+		return
+	}
+	h.goTo(ctx, cq.From.ID, stepLogVoidReason)
+	h.logSession.Set(ctx, &tools.LogsSession{
+		UserID:    cq.From.ID,
+		MessageID: sent.MessageID,
+		Type:      string(kind),
+		EditID:    id,
+	})
+}
+
+// logedit:cancel:<kind>:<id> — отмена правки или аннулирования.
+func (h *Handler) handleLogEditCancel(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.logSession.Delete(ctx, cq.From.ID)
+	h.endFlow(ctx, cq.From.ID)
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.TextLogEditCancelled.String(),
+		tools.BuildBlankInlineKB(),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on handleLogEditCancel", "err", err)
+		}
+	}()
+}
+
+// Ввод нового значения поля записи.
+func (h *Handler) handleLogEditValue(ctx context.Context, msg *tgbotapi.Message) {
+	session := h.logSession.Get(ctx, msg.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, msg.Chat.ID, msg.From.ID, domain.SessionLogs)
+		return
+	}
+
+	value := strings.TrimSpace(msg.Text)
+	if value == "" || len([]rune(value)) > domain.MaxLogTextLen {
+		h.reply(msg.Chat.ID, fmt.Sprintf(string(tools.TextLogEditTooLong), domain.MaxLogTextLen))
+		return
+	}
+
+	actor := h.actor(msg.From.ID)
+	rec, err := h.logsUC.EditLog(ctx, usecase.EditLogCmd{
+		Kind:      domain.LogKind(session.Type),
+		ID:        session.EditID,
+		Field:     domain.LogField(session.EditField),
+		Value:     value,
+		Actor:     actor,
+		ActorName: h.actorName(ctx, msg.From),
+	})
+	h.finishLogEdit(ctx, msg.Chat.ID, session, rec, actor, err, tools.TextLogEditDone)
+}
+
+// Ввод причины аннулирования записи.
+func (h *Handler) handleLogVoidReason(ctx context.Context, msg *tgbotapi.Message) {
+	session := h.logSession.Get(ctx, msg.From.ID)
+	if session == nil {
+		h.sessionLost(ctx, msg.Chat.ID, msg.From.ID, domain.SessionLogs)
+		return
+	}
+
+	reason := strings.TrimSpace(msg.Text)
+	if n := len([]rune(reason)); n < domain.MinVoidReasonLen || n > domain.MaxVoidReasonLen {
+		h.reply(msg.Chat.ID, fmt.Sprintf(string(tools.TextLogVoidReasonBad), domain.MinVoidReasonLen, domain.MaxVoidReasonLen))
+		return
+	}
+
+	actor := h.actor(msg.From.ID)
+	rec, err := h.logsUC.VoidLog(ctx, usecase.VoidLogCmd{
+		Kind:      domain.LogKind(session.Type),
+		ID:        session.EditID,
+		Reason:    reason,
+		Actor:     actor,
+		ActorName: h.actorName(ctx, msg.From),
+	})
+	h.finishLogEdit(ctx, msg.Chat.ID, session, rec, actor, err, tools.TextLogVoidDone)
+}
+
+// finishLogEdit закрывает сессию, убирает кнопку отмены и присылает обновлённую карточку.
+func (h *Handler) finishLogEdit(ctx context.Context, chatID int64, session *tools.LogsSession, rec domain.LogRecord, actor domain.Actor, err error, done tools.SafeText) {
+	h.logSession.Delete(ctx, session.UserID)
+	h.endFlow(ctx, session.UserID)
+
+	edit := tgbotapi.NewEditMessageReplyMarkup(chatID, session.MessageID, tools.BuildBlankInlineKB())
+	if _, e := h.bot.Send(edit); e != nil {
+		h.log.Error("Failed to edit message on finishLogEdit", "err", e)
+	}
+
+	if err != nil {
+		h.reply(chatID, h.logEditErrText(err))
+		return
+	}
+	h.reply(chatID, string(done))
+	h.sendLogRecordCard(ctx, chatID, rec, actor)
+}
+
+// logedit:history:<kind>:<id> — история изменений записи.
+func (h *Handler) handleLogHistory(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	kind, id, ok := parseLogRef(strings.Split(cq.Data, ":")[2:])
+	if !ok {
+		return
+	}
+	rec, revs, err := h.logsUC.LogHistory(ctx, kind, id, h.actor(cq.From.ID))
+	switch {
+	case errors.Is(err, domain.ErrNotOwner):
+		h.reply(cq.Message.Chat.ID, string(tools.TextLogOwnerLogsNotAllowed))
+		return
+	case err != nil:
+		h.log.Error("LogHistory error", "err", err, "kind", kind, "id", id)
+		h.reply(cq.Message.Chat.ID, string(tools.TextLogFindErr))
+		return
+	}
+
+	m := tgbotapi.NewMessage(cq.Message.Chat.ID, tools.BuildLogHistoryStr(rec, revs, h.cfg.OfficeTZ).String())
+	m.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(m); err != nil {
+			h.log.Error("Failed to send log history", "err", err)
+		}
+	}()
+}
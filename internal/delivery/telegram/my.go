@@ -2,12 +2,14 @@ package telegram
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
 )
 
 /* ---------- /my ---------- */
@@ -97,11 +99,21 @@ func (h *Handler) handleMyList(ctx context.Context, cq *tgbotapi.CallbackQuery)
 		return
 	}
 
+	// Если бронь из серии — подгружаем правило для отображения
+	var series *domain.BookingSeries
+	if bk.SeriesID != 0 {
+		if sr, err := h.uc.GetSeries(ctx, int64(bk.SeriesID)); err == nil {
+			series = &sr
+		} else {
+			h.log.Warn("Failed to get series for my:list", "err", err, "series_id", bk.SeriesID)
+		}
+	}
+
 	edit := tgbotapi.NewEditMessageTextAndMarkup(
 		cq.Message.Chat.ID,
 		cq.Message.MessageID,
-		tools.BuildMyOperationStr(bk).String(),
-		tools.BuildMyOperationsKB(id),
+		tools.BuildMyOperationStr(bk, series).String(),
+		tools.BuildMyOperationsKB(id, bk.SeriesID),
 	)
 
 	edit.ParseMode = "MarkdownV2"
@@ -119,19 +131,13 @@ func (h *Handler) handleMyCancel(ctx context.Context, cq *tgbotapi.CallbackQuery
 	parts := strings.Split(cq.Data, ":")
 	id, _ := strconv.ParseInt(parts[2], 10, 64) // id of the picked booking.
 
-	// bk, err := h.uc.GetById(ctx, id)
-	// if err != nil {
-	// 	h.log.Error("Failed to get booking for my:cancel", "err", err, "user_id", cq.From.ID, "bk_id", id)
-	// 	h.answerCB(cq, "Не удалось загрузить бронь 😕")
-	// 	return
-	// }
-
-	// if cq.Message.From.ID == int64(bk.CreatedBy) {
-	// 	h.log.Info("User owns the booking", "userID", cq.From.ID, "bookingID", id)
-	// 	h.uc.CancelBooking(ctx, id)
-	// }
-
-	if err := h.uc.CancelBooking(ctx, id); err != nil {
+	// Права (владелец или админ) проверяет usecase: id приходит из callback data
+	bk, err := h.uc.CancelBooking(ctx, id, h.actor(cq.From.ID))
+	if errors.Is(err, domain.ErrForbiddenCancellation) {
+		h.log.Warn("Forbidden booking cancellation", "user_id", cq.From.ID, "bk_id", id)
+		h.answerWarning(tools.TextMyCancelForbidden.String(), cq)
+		return
+	} else if err != nil {
 		h.log.Error("Failed to cansel booking", "user_id", cq.From.ID, "error", err)
 		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при /my_cancel:* `%s`", err.Error()))
 		h.reply(cq.From.ID, tools.TextMyBookingCancelErr.String())
@@ -139,6 +145,7 @@ func (h *Handler) handleMyCancel(ctx context.Context, cq *tgbotapi.CallbackQuery
 	}
 
 	go h.wake()
+	h.notifyAttendees(bk.Attendees, domain.UserID(cq.From.ID), tools.BuildAttendeeCancelledStr(bk, 0))
 
 	edit := tgbotapi.NewEditMessageTextAndMarkup(
 		cq.Message.Chat.ID,
@@ -155,6 +162,103 @@ func (h *Handler) handleMyCancel(ctx context.Context, cq *tgbotapi.CallbackQuery
 	}()
 }
 
+// Перенос брони: переиспользует шаги /book (переговорка, календарь, время, длительность),
+// сессия помечается RescheduleID.
+func (h *Handler) handleMyReschedule(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("handleMyReschedule", "data", cq.Data, "user", cq.From.UserName)
+
+	parts := strings.Split(cq.Data, ":")
+	id, _ := strconv.ParseInt(parts[2], 10, 64) // id of the picked booking.
+
+	bk, err := h.uc.GetById(ctx, id)
+	if err != nil {
+		h.log.Error("Failed to get booking for my:reschedule", "err", err, "user_id", cq.From.ID, "bk_id", id)
+		h.answerCB(cq, "Не удалось загрузить бронь 😕")
+		return
+	}
+	if !h.actor(cq.From.ID).CanManage(bk.UserID) {
+		h.answerWarning(tools.TextBookRescheduleForbidden.String(), cq)
+		return
+	}
+
+	rooms, err := h.uc.ListRooms(ctx)
+	if errors.Is(err, domain.ErrNoRoomsAvailable) {
+		h.reply(cq.From.ID, tools.TextBookNoRoomsAvailable.String())
+		return
+	} else if err != nil {
+		h.log.Error("Failed to list rooms", "user_id", cq.From.ID, "error", err)
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при /my_reschedule:* `%s`", err.Error()))
+		h.reply(cq.From.ID, tools.TextBookNoRoomsErr.String())
+		return
+	}
+
+	h.goTo(ctx, cq.From.ID, stepBookRoom)
+	h.sessions.Set(ctx, &tools.BookingSession{
+		ChatID:       cq.Message.Chat.ID,
+		UserID:       cq.From.ID,
+		UserName:     bk.UserName,
+		MessageID:    cq.Message.MessageID,
+		RoomID:       bk.RoomID,
+		RoomName:     bk.RoomName,
+		RescheduleID: bk.ID,
+	})
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.TextMyRescheduleIntro.String(),
+		tgbotapi.NewInlineKeyboardMarkup(tools.BuildRoomListKB(rooms, "book")...),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on my reschedule", "err", err)
+		}
+	}()
+}
+
+func (h *Handler) handleMyCancelSeries(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("handleMyCancelSeries", "data", cq.Data, "user", cq.From.UserName)
+
+	parts := strings.Split(cq.Data, ":")
+	id, _ := strconv.ParseInt(parts[2], 10, 64) // id of the series.
+
+	canceled, err := h.uc.CancelSeries(ctx, id, h.actor(cq.From.ID))
+	if errors.Is(err, domain.ErrForbiddenCancellation) {
+		h.log.Warn("Forbidden series cancellation", "user_id", cq.From.ID, "series_id", id)
+		h.answerWarning(tools.TextMyCancelForbidden.String(), cq)
+		return
+	} else if err != nil {
+		h.log.Error("Failed to cancel series", "user_id", cq.From.ID, "series_id", id, "error", err)
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при /my_cancel_series:* `%s`", err.Error()))
+		h.reply(cq.From.ID, tools.TextMyBookingCancelErr.String())
+		return
+	}
+
+	go h.wake()
+	if len(canceled) > 0 {
+		// участники одинаковы для всех повторений — одно уведомление на серию
+		h.notifyAttendees(canceled[0].Attendees, domain.UserID(cq.From.ID),
+			tools.BuildAttendeeCancelledStr(canceled[0], len(canceled)))
+	}
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.SafeText(fmt.Sprintf(string(tools.TextMySeriesCancelled), len(canceled))).String(),
+		tools.BuildBlankInlineKB(),
+	)
+
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on series cancel", "err", err)
+		}
+	}()
+}
+
 func (h *Handler) handleMyListBack(ctx context.Context, cq *tgbotapi.CallbackQuery) {
 	h.answerCB(cq, "")
 	h.log.Info("handleMyListBack", "data", cq.Data, "user", cq.From.UserName)
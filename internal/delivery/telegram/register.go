@@ -2,9 +2,10 @@ package telegram
 
 import (
 	"context"
+	"slices"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	// "github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
 )
 
 func (h *Handler) handleRegisterFromAdmin(ctx context.Context, msg *tgbotapi.Message) {
@@ -16,9 +17,22 @@ func (h *Handler) handleRegisterFromAdmin(ctx context.Context, msg *tgbotapi.Mes
 		return
 	}
 
-	if msg.From.ID == h.cfg.AdminID {
-		h.log.Error("Registering group", "chatID", msg.Chat.ID)
-		h.cfg.GroupChatID = msg.Chat.ID
-		h.DailySchedule()
+	if msg.From.ID != h.cfg.AdminID {
+		return
+	}
+
+	h.log.Info("Registering group", "chatID", msg.Chat.ID)
+	// Сохраняем группу в БД, чтобы регистрация пережила рестарт
+	if err := h.state.RegisterGroupChat(ctx, msg.Chat.ID, domain.UserID(msg.From.ID)); err != nil {
+		h.notifyAdmin("Не удалось сохранить группу после /register: " + err.Error())
+	}
+
+	h.msgMu.Lock()
+	defer h.msgMu.Unlock()
+
+	h.groupChatID.Store(msg.Chat.ID)
+	if !slices.Contains(h.groups, msg.Chat.ID) {
+		h.groups = append(h.groups, msg.Chat.ID)
 	}
+	h.postSchedule(msg.Chat.ID, h.buildTodaySchedule())
 }
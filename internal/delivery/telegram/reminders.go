@@ -0,0 +1,180 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+/* ---------- Напоминания о начале брони и /remind ---------- */
+
+// sendReminders вызывается по расписанию (telegram.reminder_tick).
+// Брони помечаются отправленными до отправки, поэтому напоминание
+// не придёт дважды даже после рестарта бота.
+func (h *Handler) sendReminders(ctx context.Context) {
+	bks, err := h.uc.DueReminders(ctx)
+	if err != nil {
+		h.log.Error("Failed to get due reminders", "err", err)
+		return
+	}
+
+	now := time.Now().In(h.cfg.OfficeTZ)
+	for _, bk := range bks {
+		left := bk.Range.Start.Sub(now).Round(time.Minute)
+		if left < time.Minute {
+			left = time.Minute
+		}
+
+		m := tgbotapi.NewMessage(int64(bk.UserID), tools.BuildReminderStr(bk, left, h.uc.CheckinGrace()).String())
+		m.ParseMode = "MarkdownV2"
+		m.ReplyMarkup = tools.BuildReminderKB(bk.ID)
+		if _, err := h.bot.Send(m); err != nil {
+			if isPermanentSendErr(err) {
+				// владелец не начинал диалог с ботом или заблокировал его — повторять бессмысленно
+				h.log.Warn("Reminder not delivered", "owner_id", bk.UserID, "bk_id", bk.ID, "err", err)
+				continue
+			}
+			h.log.Error("Failed to send reminder, will retry", "owner_id", bk.UserID, "bk_id", bk.ID, "err", err)
+			_ = h.uc.ReleaseReminder(ctx, bk.ID)
+		}
+	}
+}
+
+// isPermanentSendErr — Telegram ответил отказом, который не исправится повтором.
+func isPermanentSendErr(err error) bool {
+	var tgErr *tgbotapi.Error
+	if !errors.As(err, &tgErr) {
+		return false
+	}
+	return tgErr.Code == http.StatusForbidden || tgErr.Code == http.StatusBadRequest
+}
+
+// remind:coming:<bookingID> — «Приду» засчитывается как check-in.
+func (h *Handler) handleRemindComing(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.log.Info("handleRemindComing", "data", cq.Data, "user", cq.From.UserName)
+
+	parts := strings.Split(cq.Data, ":")
+	id, _ := strconv.ParseInt(parts[2], 10, 64)
+
+	if _, err := h.uc.CheckIn(ctx, id, h.actor(cq.From.ID)); err != nil {
+		h.answerCheckinErr(cq, id, err)
+		return
+	}
+	h.answerCB(cq, tools.TextReminderComing)
+
+	edit := tgbotapi.NewEditMessageReplyMarkup(cq.Message.Chat.ID, cq.Message.MessageID, tools.BuildBlankInlineKB())
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Warn("Failed to remove keyboard on reminder", "err", err)
+		}
+	}()
+}
+
+// remind:cancel:<bookingID>
+func (h *Handler) handleRemindCancel(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("handleRemindCancel", "data", cq.Data, "user", cq.From.UserName)
+
+	parts := strings.Split(cq.Data, ":")
+	id, _ := strconv.ParseInt(parts[2], 10, 64)
+
+	bk, err := h.uc.CancelBooking(ctx, id, h.actor(cq.From.ID))
+	switch {
+	case errors.Is(err, domain.ErrForbiddenCancellation):
+		h.answerWarning(tools.TextMyCancelForbidden.String(), cq)
+		return
+	case errors.Is(err, domain.ErrBookingNotFound):
+		h.answerWarning(tools.TextReminderNotFound.String(), cq)
+		return
+	case err != nil:
+		h.log.Error("Failed to cancel booking from reminder", "user_id", cq.From.ID, "bk_id", id, "error", err)
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при отмене из напоминания:* `%s`", err.Error()))
+		h.answerWarning(tools.TextMyBookingCancelErr.String(), cq)
+		return
+	}
+
+	go h.wake()
+	h.notifyAttendees(bk.Attendees, domain.UserID(cq.From.ID), tools.BuildAttendeeCancelledStr(bk, 0))
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.TextMyBookingCancelled.String(),
+		tools.BuildBlankInlineKB(),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on reminder cancel", "err", err)
+		}
+	}()
+}
+
+// /remind — настройка, за сколько до начала брони присылать напоминание.
+func (h *Handler) handleRemind(ctx context.Context, msg *tgbotapi.Message) {
+	if err := ctx.Err(); err != nil {
+		h.log.Warn("Context canceled in handleRemind handler",
+			"user", msg.From.UserName,
+			"chat_id", msg.Chat.ID,
+			"err", ctx.Err())
+		return
+	}
+
+	current, err := h.uc.RemindBefore(ctx, domain.UserID(msg.From.ID))
+	if err != nil {
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при /remind:* `%s`", err.Error()))
+		h.reply(msg.Chat.ID, string(tools.TextRemindErr))
+		return
+	}
+
+	text := tools.SafeText(fmt.Sprintf(string(tools.TextRemindSettings), tools.FormatRemindBefore(current)))
+	m := tgbotapi.NewMessage(msg.Chat.ID, text.String())
+	m.ParseMode = "MarkdownV2"
+	m.ReplyMarkup = tools.BuildRemindSettingsKB(current)
+	go func() {
+		if _, err := h.bot.Send(m); err != nil {
+			h.log.Error("Failed to send remind settings", "err", err)
+		}
+	}()
+}
+
+// remind:set:<minutes>
+func (h *Handler) handleRemindSet(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+	h.log.Info("handleRemindSet", "data", cq.Data, "user", cq.From.UserName)
+
+	parts := strings.Split(cq.Data, ":")
+	mins, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return
+	}
+	before := time.Duration(mins) * time.Minute
+
+	if err := h.uc.SetRemindBefore(ctx, domain.UserID(cq.From.ID), before); err != nil {
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при /remind:* `%s`", err.Error()))
+		h.answerWarning(tools.TextRemindErr.String(), cq)
+		return
+	}
+
+	text := tools.SafeText(fmt.Sprintf(string(tools.TextRemindSaved), tools.FormatRemindBefore(before)))
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		text.String(),
+		tools.BuildBlankInlineKB(),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on remind set", "err", err)
+		}
+	}()
+}
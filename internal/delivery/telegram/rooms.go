@@ -34,12 +34,7 @@ func (h *Handler) handleCreateRoom(ctx context.Context, msg *tgbotapi.Message) {
 		return
 	}
 
-	h.sessions.Set(&tools.BookingSession{
-		BookState: tools.StateProccessingRoomCreation,
-		UserID:    msg.From.ID,
-		UserName:  msg.From.UserName,
-		ChatID:    msg.Chat.ID,
-	})
+	h.goTo(ctx, msg.From.ID, stepRoomName)
 
 	newMsg := tgbotapi.NewMessage(msg.Chat.ID, tools.TextRoomNameInput.String())
 	newMsg.ParseMode = "MarkdownV2"
@@ -75,7 +70,7 @@ func (h *Handler) handleCreateRoomProcessing(ctx context.Context, msg *tgbotapi.
 		h.reply(msg.Chat.ID, string(tools.TextRoomCreated))
 		go h.wake()
 	}
-	h.sessions.Delete(msg.From.ID)
+	h.endFlow(ctx, msg.From.ID)
 }
 
 func (h *Handler) handleDeactivateRoom(ctx context.Context, msg *tgbotapi.Message) {
@@ -117,39 +117,93 @@ func (h *Handler) buildTodaySchedule() string {
 	return b.String()
 }
 
+// DailySchedule публикует расписание на сегодня во все зарегистрированные группы.
 func (h *Handler) DailySchedule() {
 	h.msgMu.Lock()
 	defer h.msgMu.Unlock()
-	msg := tgbotapi.NewMessage(h.cfg.GroupChatID, h.buildTodaySchedule())
+
+	text := h.buildTodaySchedule()
+	for _, chatID := range h.groups {
+		h.postSchedule(chatID, text)
+	}
+}
+
+// postSchedule отправляет расписание в чат и запоминает сообщение,
+// чтобы wake() мог его редактировать и после рестарта. Вызывать под msgMu.
+func (h *Handler) postSchedule(chatID int64, text string) {
+	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ParseMode = "MarkdownV2"
 
 	sent, err := h.bot.Send(msg)
 	if err != nil {
-		h.log.Error("failed to send DailySchedule", "err", err)
+		h.log.Error("failed to send DailySchedule", "chat_id", chatID, "err", err)
+		return
+	}
+
+	today := time.Now().In(h.cfg.OfficeTZ)
+	if day := today.Format(time.DateOnly); day != h.scheduleDay {
+		h.scheduleDay = day
+		h.messages = make(map[int64]int)
+	}
+	h.messages[chatID] = sent.MessageID
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := h.state.SaveScheduleMessage(ctx, chatID, today, sent.MessageID); err != nil {
+		h.log.Error("failed to persist schedule message", "chat_id", chatID, "err", err)
 	}
-	h.messageID = int64(sent.MessageID)
 }
 
+// wake обновляет сегодняшние сообщения с расписанием после изменений броней.
 func (h *Handler) wake() {
-	if h.messageID == 0 {
+	h.msgMu.Lock()
+	defer h.msgMu.Unlock()
+
+	// вчерашние сообщения не трогаем — сегодняшнее опубликует cron
+	if len(h.messages) == 0 || h.scheduleDay != time.Now().In(h.cfg.OfficeTZ).Format(time.DateOnly) {
 		return
 	}
+
+	text := h.buildTodaySchedule()
+	for chatID, messageID := range h.messages {
+		edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+		edit.ParseMode = "MarkdownV2"
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("failed to wake", "chat_id", chatID, "err", err)
+		}
+	}
+}
+
+// restoreState подтягивает из БД зарегистрированные группы и сегодняшние
+// сообщения с расписанием, чтобы после рестарта wake() продолжил их редактировать.
+func (h *Handler) restoreState(ctx context.Context) {
+	groups, err := h.state.GroupChats(ctx)
+	if err != nil {
+		h.log.Error("failed to restore group chats", "err", err)
+		return
+	}
+
 	h.msgMu.Lock()
 	defer h.msgMu.Unlock()
-	edit := tgbotapi.NewEditMessageText(
-		h.cfg.GroupChatID,
-		int(h.messageID),
-		h.buildTodaySchedule(),
-	)
 
-	edit.ParseMode = "MarkdownV2"
-	// sent, err := h.bot.Send(edit)
-	// if err != nil {
-	// 	h.log.Error("Wake didnt work out", "err", err)
-	// }
+	h.groups = groups
+	if len(groups) > 0 {
+		// роли проверяются по основной (последней зарегистрированной) группе
+		h.groupChatID.Store(groups[len(groups)-1])
+	}
 
-	// h.messageID = int64(sent.MessageID)
-	if _, err := h.bot.Send(edit); err != nil {
-		h.log.Error("failed to wake", "err", err)
+	today := time.Now().In(h.cfg.OfficeTZ)
+	h.scheduleDay = today.Format(time.DateOnly)
+	h.messages = make(map[int64]int)
+	for _, chatID := range groups {
+		id, err := h.state.ScheduleMessage(ctx, chatID, today)
+		if err != nil {
+			h.log.Error("failed to restore schedule message", "chat_id", chatID, "err", err)
+			continue
+		}
+		if id != 0 {
+			h.messages[chatID] = id
+		}
 	}
+	h.log.Info("bot state restored", "groups", len(groups), "schedule_messages", len(h.messages))
 }
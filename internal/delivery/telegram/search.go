@@ -0,0 +1,154 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/internal/usecase"
+)
+
+/* ---------- /search — поиск по журналам ---------- */
+
+// parseSearchArgs разбирает «Иванов аренда тип:ЭС с:01.01.2024 по:31.12.2024 мои».
+func (h *Handler) parseSearchArgs(args string, userID int64) (domain.LogSearchFilter, error) {
+	var (
+		f     domain.LogSearchFilter
+		words []string
+	)
+	for _, tok := range strings.Fields(args) {
+		key, val, ok := strings.Cut(tok, ":")
+		switch {
+		case ok && strings.EqualFold(key, "тип"):
+			switch strings.ToUpper(val) {
+			case domain.LogKindSogl.Prefix():
+				f.Kind = domain.LogKindSogl
+			case domain.LogKindZapros.Prefix():
+				f.Kind = domain.LogKindZapros
+			default:
+				return f, domain.ErrInvalidInputData
+			}
+		case ok && strings.EqualFold(key, "с"):
+			d, err := time.ParseInLocation("02.01.2006", val, h.cfg.OfficeTZ)
+			if err != nil {
+				return f, domain.ErrInvalidInputData
+			}
+			f.From = d
+		case ok && strings.EqualFold(key, "по"):
+			d, err := time.ParseInLocation("02.01.2006", val, h.cfg.OfficeTZ)
+			if err != nil {
+				return f, domain.ErrInvalidInputData
+			}
+			f.To = d
+		case strings.EqualFold(tok, "мои"):
+			f.UserID = domain.UserID(userID)
+		default:
+			words = append(words, tok)
+		}
+	}
+	f.Query = strings.Join(words, " ")
+	return f, nil
+}
+
+func (h *Handler) handleLogSearch(ctx context.Context, msg *tgbotapi.Message) {
+	if err := ctx.Err(); err != nil {
+		h.log.Warn("Context canceled in /handleLogSearch",
+			"user", msg.From.UserName,
+			"chat_id", msg.Chat.ID,
+			"err", ctx.Err())
+		return
+	}
+
+	f, err := h.parseSearchArgs(msg.CommandArguments(), msg.From.ID)
+	if err != nil || len(f.Words()) == 0 {
+		h.reply(msg.Chat.ID, string(tools.TextLogSearchUsage))
+		return
+	}
+
+	recs, total, ok := h.runLogSearch(ctx, msg.Chat.ID, msg.From.ID, f)
+	if !ok {
+		return
+	}
+	h.searches.Set(msg.From.ID, f)
+
+	m := tgbotapi.NewMessage(msg.Chat.ID, tools.BuildLogSearchStr(f.Query, recs, total, 0).String())
+	m.ParseMode = "MarkdownV2"
+	m.ReplyMarkup = tools.BuildLogSearchKB(recs, total, 0, usecase.LogSearchPageSize)
+	go func() {
+		if _, err := h.bot.Send(m); err != nil {
+			h.log.Error("Failed to send search results", "err", err)
+		}
+	}()
+}
+
+// runLogSearch выполняет поиск и сам отвечает пользователю на ошибки и пустой результат.
+func (h *Handler) runLogSearch(ctx context.Context, chatID, userID int64, f domain.LogSearchFilter) ([]domain.LogRecord, int, bool) {
+	recs, total, err := h.logsUC.Search(ctx, f, h.actor(userID))
+	switch {
+	case errors.Is(err, domain.ErrInvalidInputData):
+		h.reply(chatID, string(tools.TextLogSearchUsage))
+		return nil, 0, false
+	case err != nil:
+		h.log.Error("Search error", "err", err, "query", f.Query)
+		h.notifyAdmin(fmt.Sprintf("❗ *Ошибка при /search:* `%s`", err.Error()))
+		h.reply(chatID, string(tools.TextLogSearchErr))
+		return nil, 0, false
+	case total == 0 && f.Offset == 0:
+		h.reply(chatID, fmt.Sprintf(string(tools.TextLogSearchEmpty), tools.StripMarkup(f.Query)))
+		return nil, 0, false
+	}
+	return recs, total, true
+}
+
+// search:page:<offset>
+func (h *Handler) handleLogSearchPage(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	f, ok := h.searches.Get(cq.From.ID)
+	if !ok {
+		h.reply(cq.Message.Chat.ID, string(tools.TextLogSearchExpired))
+		return
+	}
+	parts := strings.Split(cq.Data, ":")
+	f.Offset, _ = strconv.Atoi(parts[2])
+
+	recs, total, ok := h.runLogSearch(ctx, cq.Message.Chat.ID, cq.From.ID, f)
+	if !ok {
+		return
+	}
+
+	edit := tgbotapi.NewEditMessageTextAndMarkup(
+		cq.Message.Chat.ID,
+		cq.Message.MessageID,
+		tools.BuildLogSearchStr(f.Query, recs, total, f.Offset).String(),
+		tools.BuildLogSearchKB(recs, total, f.Offset, usecase.LogSearchPageSize),
+	)
+	edit.ParseMode = "MarkdownV2"
+	go func() {
+		if _, err := h.bot.Send(edit); err != nil {
+			h.log.Error("Failed to edit message on search page", "err", err)
+		}
+	}()
+}
+
+// search:open:<kind>:<id> — карточка найденной записи.
+func (h *Handler) handleLogSearchOpen(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	parts := strings.Split(cq.Data, ":")
+	if len(parts) < 4 {
+		return
+	}
+	id, _ := strconv.ParseInt(parts[3], 10, 64)
+	ref := domain.LogRecord{Kind: domain.LogKind(parts[2]), ID: id}
+
+	actor := h.actor(cq.From.ID)
+	rec, err := h.logsUC.GetLog(ctx, ref.Kind, ref.ID, actor)
+	h.sendLogCardResult(ctx, cq.Message.Chat.ID, ref.Number(), rec, err, actor)
+}
@@ -0,0 +1,91 @@
+package telegram
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/internal/delivery/telegram/tools"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+// Сессии диалогов хранятся в БД и истекают через telegram.session_ttl.
+
+// flushSessions сохраняет сессии пользователя после обработки апдейта:
+// обработчики меняют их по указателю.
+func (h *Handler) flushSessions(ctx context.Context, userID int64) {
+	h.sessions.Flush(ctx, userID)
+	h.logSession.Flush(ctx, userID)
+	h.flows.Flush(ctx, userID)
+}
+
+// sweepSessions — периодическая очистка истёкших сессий.
+func (h *Handler) sweepSessions(ctx context.Context) {
+	now := time.Now()
+	h.sessions.Evict(now)
+	h.logSession.Evict(now)
+	h.flows.Evict(now)
+	h.sessionsUC.SweepSessions(ctx)
+}
+
+// sessionLost сообщает, что сессия диалога истекла или не найдена, и
+// предлагает начать его заново.
+func (h *Handler) sessionLost(ctx context.Context, chatID, userID int64, kind domain.SessionKind) {
+	expired := false
+	switch kind {
+	case domain.SessionBooking:
+		expired = h.sessions.Expired(ctx, userID)
+	case domain.SessionLogs:
+		expired = h.logSession.Expired(ctx, userID)
+	}
+
+	text := tools.TextSessionNotFound
+	if expired {
+		text = tools.TextSessionExpired
+	}
+	m := tgbotapi.NewMessage(chatID, text.String())
+	m.ParseMode = "MarkdownV2"
+	m.ReplyMarkup = tools.BuildSessionRestartKB(kind)
+	go func() {
+		if _, err := h.bot.Send(m); err != nil {
+			h.log.Error("Failed to send session lost message", "err", err)
+		}
+	}()
+}
+
+// Текст без активной сессии: если диалог истёк, говорим об этом.
+func (h *Handler) handleNoSession(ctx context.Context, msg *tgbotapi.Message) {
+	switch {
+	case h.sessions.Expired(ctx, msg.From.ID):
+		h.sessionLost(ctx, msg.Chat.ID, msg.From.ID, domain.SessionBooking)
+	case h.logSession.Expired(ctx, msg.From.ID):
+		h.sessionLost(ctx, msg.Chat.ID, msg.From.ID, domain.SessionLogs)
+	default:
+		h.reply(msg.Chat.ID, "Необработанный ввод. Смотри /help")
+	}
+}
+
+// session:restart:<kind> — начать диалог заново: бронирование с выбора
+// переговорки, журналы — с меню журналов.
+func (h *Handler) handleSessionRestart(ctx context.Context, cq *tgbotapi.CallbackQuery) {
+	h.answerCB(cq, "")
+
+	parts := strings.Split(cq.Data, ":")
+	if len(parts) < 3 {
+		return
+	}
+	edit := tgbotapi.NewEditMessageReplyMarkup(cq.Message.Chat.ID, cq.Message.MessageID, tools.BuildBlankInlineKB())
+	if _, err := h.bot.Send(edit); err != nil {
+		h.log.Error("Failed to edit message on handleSessionRestart", "err", err)
+	}
+
+	msg := &tgbotapi.Message{From: cq.From, Chat: cq.Message.Chat}
+	switch domain.SessionKind(parts[2]) {
+	case domain.SessionBooking:
+		h.handleBook(ctx, msg)
+	case domain.SessionLogs:
+		h.cancelFlow(ctx, cq.From.ID)
+		h.handleLog(ctx, msg)
+	}
+}
@@ -1,56 +1,63 @@
 package tools
 
 import (
-	"sync"
+	"context"
 	"time"
 
 	"github.com/leegeev/KomaevBookingBot/internal/domain"
 )
 
 type BookingSession struct {
-	BookState int64
-	ChatID    int64
-	UserID    int64
-	UserName  string
-	MessageID int // сообщение, которое редактируем
-	RoomID    domain.RoomID
-	RoomName  string
-	Date      time.Time // без времени, локаль офиса
-	StartTime time.Time // полноценный time с датой+временем
-	EndTime   time.Time
-	Duration  time.Duration
-}
-
-const (
-	StateIdle = iota
-	StateProcessingCommand
-	StateProccessingRoomCreation
-	BookStateChoosingRoom
-	BookStateChoosingDate
-	BookStateChoosingStartTime
-	BookStateChoosingDuration
-	BookStateConfirmingBooking
-)
+	ChatID     int64
+	UserID     int64
+	UserName   string
+	MessageID  int // сообщение, которое редактируем
+	RoomID     domain.RoomID
+	RoomName   string
+	Date       time.Time // без времени, локаль офиса
+	StartTime  time.Time // полноценный time с датой+временем
+	EndTime    time.Time
+	Duration   time.Duration
+	Recurrence domain.RecurrenceRule // пустое правило — разовая бронь
+	Note       string                // цель встречи
+	Attendees  []domain.Attendee
+
+	RescheduleID domain.BookingID // != 0, если сессия переносит существующую бронь
+	CancelID     domain.BookingID // бронь, которую отменяет администратор (ждём причину)
+}
 
+// Сессии бронирования: переживают рестарт, истекают через telegram.session_ttl.
 type SessionsStore struct {
-	data sync.Map // key: int64, value: *BookingSession
+	store *sessionStore
 }
 
-func NewSessionStore() *SessionsStore {
-	return &SessionsStore{}
+func NewSessionStore(backend SessionBackend) *SessionsStore {
+	return &SessionsStore{store: newSessionStore(domain.SessionBooking, backend)}
 }
 
-func (s *SessionsStore) Get(userID int64) *BookingSession {
-	if val, ok := s.data.Load(userID); ok {
+func (s *SessionsStore) Get(ctx context.Context, userID int64) *BookingSession {
+	if val := s.store.get(ctx, userID, &BookingSession{}); val != nil {
 		return val.(*BookingSession)
 	}
 	return nil
 }
 
-func (s *SessionsStore) Set(session *BookingSession) {
-	s.data.Store(session.UserID, session)
+func (s *SessionsStore) Set(ctx context.Context, session *BookingSession) {
+	s.store.set(ctx, session.UserID, session)
+}
+
+func (s *SessionsStore) Delete(ctx context.Context, userID int64) {
+	s.store.delete(ctx, userID)
+}
+
+func (s *SessionsStore) Expired(ctx context.Context, userID int64) bool {
+	return s.store.expired(ctx, userID)
+}
+
+func (s *SessionsStore) Flush(ctx context.Context, userID int64) {
+	s.store.flush(ctx, userID)
 }
 
-func (s *SessionsStore) Delete(userID int64) {
-	s.data.Delete(userID)
+func (s *SessionsStore) Evict(now time.Time) {
+	s.store.evict(now)
 }
@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"sync"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+// Настройка выгрузки журналов: период и фильтры выбираются в несколько шагов.
+type ExportSession struct {
+	Query      domain.ReportQuery
+	AuthorName string // ФИО выбранного автора, пусто — все
+	PickingTo  bool   // в календаре выбирается конец периода
+}
+
+type ExportStore struct {
+	data sync.Map // key: int64, value: *ExportSession
+}
+
+func NewExportStore() *ExportStore {
+	return &ExportStore{}
+}
+
+func (s *ExportStore) Get(userID int64) *ExportSession {
+	if val, ok := s.data.Load(userID); ok {
+		return val.(*ExportSession)
+	}
+	return nil
+}
+
+func (s *ExportStore) Set(userID int64, session *ExportSession) {
+	s.data.Store(userID, session)
+}
+
+func (s *ExportStore) Delete(userID int64) {
+	s.data.Delete(userID)
+}
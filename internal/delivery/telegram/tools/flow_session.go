@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+// Диалог (флоу) и его шаг. Шаги уникальны среди всех флоу.
+type FlowID string
+type Step string
+
+// Текущий диалог пользователя. Данные диалога (черновик брони или
+// записи журнала) лежат в своих сессиях, здесь — только где он сейчас.
+type FlowSession struct {
+	UserID int64
+	Flow   FlowID
+	Step   Step
+}
+
+type FlowStore struct {
+	store *sessionStore
+}
+
+func NewFlowStore(backend SessionBackend) *FlowStore {
+	return &FlowStore{store: newSessionStore(domain.SessionFlow, backend)}
+}
+
+func (s *FlowStore) Get(ctx context.Context, userID int64) *FlowSession {
+	if val := s.store.get(ctx, userID, &FlowSession{}); val != nil {
+		return val.(*FlowSession)
+	}
+	return nil
+}
+
+func (s *FlowStore) Set(ctx context.Context, session *FlowSession) {
+	s.store.set(ctx, session.UserID, session)
+}
+
+func (s *FlowStore) Delete(ctx context.Context, userID int64) {
+	s.store.delete(ctx, userID)
+}
+
+func (s *FlowStore) Expired(ctx context.Context, userID int64) bool {
+	return s.store.expired(ctx, userID)
+}
+
+func (s *FlowStore) Flush(ctx context.Context, userID int64) {
+	s.store.flush(ctx, userID)
+}
+
+func (s *FlowStore) Evict(now time.Time) {
+	s.store.evict(now)
+}
@@ -8,6 +8,11 @@ import (
 	"github.com/leegeev/KomaevBookingBot/internal/domain"
 )
 
+const (
+	maxDurationButtons = 8
+	defaultMaxDuration = 4 * time.Hour
+)
+
 // Step -1.
 // Возвращает пустую клавиатуру для выхода в главное меню
 func BuildBlankInlineKB() tgbotapi.InlineKeyboardMarkup {
@@ -42,10 +47,21 @@ func BuildRoomListKB(rooms []domain.Room, route string) [][]tgbotapi.InlineKeybo
 // Step 1.
 // Строит календарь. Вызывается из хендлера.
 func BuildCalendarKB(shift int64) tgbotapi.InlineKeyboardMarkup {
+	return buildFutureCalendarKB("book:calendar", shift)
+}
+
+// Календарь выбора даты окончания серии броней.
+func BuildUntilCalendarKB(shift int64) tgbotapi.InlineKeyboardMarkup {
+	return buildFutureCalendarKB("book:until", shift)
+}
+
+// Календарь на неделю, прошедшие дни заблокированы.
+// Коллбэки: <route>:<date>, <route>_nav:<shift>, <route>_back
+func buildFutureCalendarKB(route string, shift int64) tgbotapi.InlineKeyboardMarkup {
 	// Навигация
 	row1 := tgbotapi.NewInlineKeyboardRow(
-		tgbotapi.NewInlineKeyboardButtonData("⏪", fmt.Sprintf("book:calendar_nav:%d", shift-1)),
-		tgbotapi.NewInlineKeyboardButtonData("⏩", fmt.Sprintf("book:calendar_nav:%d", shift+1)),
+		tgbotapi.NewInlineKeyboardButtonData("⏪", fmt.Sprintf("%s_nav:%d", route, shift-1)),
+		tgbotapi.NewInlineKeyboardButtonData("⏩", fmt.Sprintf("%s_nav:%d", route, shift+1)),
 	)
 
 	// Определяем понедельник текущей недели
@@ -77,39 +93,82 @@ func BuildCalendarKB(shift int64) tgbotapi.InlineKeyboardMarkup {
 			callback = "no:op"
 		} else {
 			row3display = day.Format("02.01")
-			callback = fmt.Sprintf("book:calendar:%s", day.Format("2006-01-02"))
+			callback = fmt.Sprintf("%s:%s", route, day.Format("2006-01-02"))
 		}
 		row3 = append(row3, tgbotapi.NewInlineKeyboardButtonData(row3display, callback))
 	}
 
 	// Назад
-	row4 := tgbotapi.NewInlineKeyboardRow(BuildBackInlineKBButton("book:calendar_back"))
+	row4 := tgbotapi.NewInlineKeyboardRow(BuildBackInlineKBButton(route + "_back"))
 
 	return tgbotapi.NewInlineKeyboardMarkup(row1, row2, row3, row4)
 }
 
-// Step 2.
-// Строит клавиатуру для выбора длительности брони.
-func BuildDurationKB() tgbotapi.InlineKeyboardMarkup {
-	rows := make([][]tgbotapi.InlineKeyboardButton, 0, 4)
+// Step 1.1
+// Строит клавиатуру со свободными временами начала брони (по 4 в ряд).
+func BuildTimePickKB(starts []time.Time) tgbotapi.InlineKeyboardMarkup {
+	const perRow = 4
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(starts)/perRow+2)
+	row := make([]tgbotapi.InlineKeyboardButton, 0, perRow)
+	for _, st := range starts {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			st.Format("15:04"),
+			fmt.Sprintf("book:timepick:%s", st.Format("1504")),
+		))
+		if len(row) == perRow {
+			rows = append(rows, row)
+			row = make([]tgbotapi.InlineKeyboardButton, 0, perRow)
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(BuildBackInlineKBButton("book:timepick_back")))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
 
-	// for i := 1; i <= 8; i += 2 {
-	for i := 1; i <= 4; i += 2 {
-		// (0.5, 1.5, 2.5, 3.5)
-		left := float64(i) * 0.5
-		// (1.0, 2.0, 3.0, 4.0)
-		right := float64(i+1) * 0.5
+// /free Кнопки быстрой брони свободных переговорок на выбранный слот.
+func BuildFreeRoomsKB(rooms []domain.Room, start time.Time, dur time.Duration) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(rooms))
+	for _, room := range rooms {
+		btnText := fmt.Sprintf("📝 #%s %s", room.Name, start.Format("15:04"))
+		data := fmt.Sprintf("free:book:%d:%s:%d", room.ID, start.Format("1504"), int(dur.Minutes()))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(btnText, data)))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
 
-		leftBtn := tgbotapi.NewInlineKeyboardButtonData(
-			formatDurationButtonText(left),
-			fmt.Sprintf("book:duration:%.1f", left),
-		)
-		rightBtn := tgbotapi.NewInlineKeyboardButtonData(
-			formatDurationButtonText(right),
-			fmt.Sprintf("book:duration:%.1f", right),
-		)
+// Step 2.
+// Строит клавиатуру для выбора длительности брони в пределах политики.
+// Кнопок не больше maxDurationButtons: при мелком шаге сетки он укрупняется.
+func BuildDurationKB(p domain.BookingPolicy) tgbotapi.InlineKeyboardMarkup {
+	minDur, maxDur, inc := p.MinDuration, p.MaxDuration, p.Step
+	if inc <= 0 {
+		inc = 30 * time.Minute
+	}
+	if minDur < inc {
+		minDur = inc
+	}
+	if maxDur <= 0 {
+		maxDur = defaultMaxDuration
+	}
+	for int((maxDur-minDur)/inc)+1 > maxDurationButtons {
+		inc *= 2
+	}
 
-		row := tgbotapi.NewInlineKeyboardRow(leftBtn, rightBtn)
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, maxDurationButtons/2+1)
+	var row []tgbotapi.InlineKeyboardButton
+	for d := minDur; d <= maxDur; d += inc {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			formatDurationButtonText(d),
+			fmt.Sprintf("book:duration:%d", int(d.Minutes())),
+		))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
 		rows = append(rows, row)
 	}
 
@@ -119,6 +178,53 @@ func BuildDurationKB() tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
 
+// Step 3.
+// Строит клавиатуру выбора правила повторения брони.
+func BuildRecurrenceKB() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("1️⃣ Не повторять", "book:recur:none"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Ежедневно", fmt.Sprintf("book:recur:%s", domain.RecurDaily)),
+			tgbotapi.NewInlineKeyboardButtonData("По будням", fmt.Sprintf("book:recur:%s", domain.RecurWeekdays)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("Еженедельно", fmt.Sprintf("book:recur:%s", domain.RecurWeekly)),
+			tgbotapi.NewInlineKeyboardButtonData("Раз в 2 недели", fmt.Sprintf("book:recur:%s", domain.RecurBiweekly)),
+		),
+		tgbotapi.NewInlineKeyboardRow(BuildBackInlineKBButton("book:recur_back")),
+	)
+}
+
+// Step 3.1
+// Строит клавиатуру выбора окончания серии: N повторений или до даты.
+func BuildRecurrenceEndKB() tgbotapi.InlineKeyboardMarkup {
+	counts := []int{2, 4, 8, 12}
+	row := make([]tgbotapi.InlineKeyboardButton, 0, len(counts))
+	for _, n := range counts {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("%d раз", n),
+			fmt.Sprintf("book:recur_count:%d", n),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(
+		row,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📅 До даты", "book:recur_until:0"),
+		),
+		tgbotapi.NewInlineKeyboardRow(BuildBackInlineKBButton("book:recur_end_back")),
+	)
+}
+
+// Шаг с необязательным текстовым вводом: «Пропустить» и «Назад».
+func BuildSkipKB(skipData, backData string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(TextSkipInlineKBButton, skipData)),
+		tgbotapi.NewInlineKeyboardRow(BuildBackInlineKBButton(backData)),
+	)
+}
+
 func BuildConfirmationKB(route string) tgbotapi.InlineKeyboardMarkup {
 	rows := make([][]tgbotapi.InlineKeyboardButton, 0, 2)
 	// Кнопка с
@@ -157,11 +263,34 @@ func BuildRoomDeleteKB(id int64) tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
 
-func formatDurationButtonText(d float64) string {
-	if d == float64(int64(d)) {
-		return fmt.Sprintf("%.0fч", d)
+func formatDurationButtonText(d time.Duration) string {
+	h, m := int(d/time.Hour), int(d%time.Hour/time.Minute)
+	switch m {
+	case 0:
+		return fmt.Sprintf("%dч", h)
+	case 30:
+		return fmt.Sprintf("%d.5ч", h)
 	}
-	return fmt.Sprintf("%.1fч", d)
+	if h == 0 {
+		return fmt.Sprintf("%dм", m)
+	}
+	return fmt.Sprintf("%dч%02dм", h, m)
+}
+
+// Список ближайших броней переговорки для отмены администратором.
+func BuildAdminCancelListKB(bks []domain.Booking) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(bks)+1)
+	for _, bk := range bks {
+		btnText := fmt.Sprintf("%s %s–%s - %s",
+			bk.Range.Start.Format("02.01"),
+			bk.Range.Start.Format("15:04"),
+			bk.Range.End.Format("15:04"),
+			bk.UserName)
+		data := fmt.Sprintf("admin_cancel:pick:%d", bk.ID)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(btnText, data)))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(BuildBackInlineKBButton("admin_cancel:pick_back")))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
 
 func BuildMyListKB(bks []domain.Booking, OfficeTZ *time.Location) tgbotapi.InlineKeyboardMarkup {
@@ -174,6 +303,9 @@ func BuildMyListKB(bks []domain.Booking, OfficeTZ *time.Location) tgbotapi.Inlin
 			start.Format("02.01"),
 			start.Hour(), start.Minute(), end.Hour(), end.Minute(),
 			bk.RoomName)
+		if bk.SeriesID != 0 {
+			btnText = "🔁 " + btnText
+		}
 
 		data := fmt.Sprintf("my:list:%d", bk.ID)
 
@@ -185,17 +317,23 @@ func BuildMyListKB(bks []domain.Booking, OfficeTZ *time.Location) tgbotapi.Inlin
 	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
 
-func BuildMyOperationsKB(bookingID int64) tgbotapi.InlineKeyboardMarkup {
-	// rescheduleBtn := tgbotapi.NewInlineKeyboardButtonData("🔄 Перенести", fmt.Sprintf("my:reschedule:%d", bookingID))
+func BuildMyOperationsKB(bookingID int64, seriesID domain.SeriesID) tgbotapi.InlineKeyboardMarkup {
+	rescheduleBtn := tgbotapi.NewInlineKeyboardButtonData("🔄 Перенести", fmt.Sprintf("my:reschedule:%d", bookingID))
 	cancelBtn := tgbotapi.NewInlineKeyboardButtonData("❌ Отменить", fmt.Sprintf("my:cancel:%d", bookingID))
 
 	backBtn := BuildBackInlineKBButton("my:list_back")
 
-	// row1 := tgbotapi.NewInlineKeyboardRow(rescheduleBtn, cancelBtn)
-	row1 := tgbotapi.NewInlineKeyboardRow(cancelBtn)
-	row2 := tgbotapi.NewInlineKeyboardRow(backBtn)
+	row1 := tgbotapi.NewInlineKeyboardRow(rescheduleBtn, cancelBtn)
+	rows := [][]tgbotapi.InlineKeyboardButton{row1}
+
+	// Бронь из серии можно отменить целиком
+	if seriesID != 0 {
+		cancelSeriesBtn := tgbotapi.NewInlineKeyboardButtonData("🔁❌ Отменить всю серию", fmt.Sprintf("my:cancel_series:%d", seriesID))
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(cancelSeriesBtn))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(backBtn))
 
-	return tgbotapi.NewInlineKeyboardMarkup(row1, row2)
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
 
 func BuildMainMenuKB(role string) tgbotapi.ReplyKeyboardMarkup {
@@ -220,7 +358,10 @@ func BuildMainMenuKB(role string) tgbotapi.ReplyKeyboardMarkup {
 			tgbotapi.NewKeyboardButton(TextMainCreateRoomButton),
 			tgbotapi.NewKeyboardButton(TextMainDeleteRoomButton),
 		)
-		rows = append(rows, row3)
+		row4 := tgbotapi.NewKeyboardButtonRow(
+			tgbotapi.NewKeyboardButton(TextMainAdminCancelButton),
+		)
+		rows = append(rows, row3, row4)
 	}
 
 	// собираем клавиатуру
@@ -292,12 +433,13 @@ func BuildLogCreateKB(faze string) tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
 
-func BuildLogCalendarKB(shift int64) tgbotapi.InlineKeyboardMarkup {
+// prefix — первая часть callback data: "log" для создания записи, "export" для выгрузки.
+func BuildLogCalendarKB(prefix string, shift int64) tgbotapi.InlineKeyboardMarkup {
 	rows := make([][]tgbotapi.InlineKeyboardButton, 0, 4)
 	{ // Навигация
 		row := tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⏪", fmt.Sprintf("log:calendar_nav:%d", shift-1)),
-			tgbotapi.NewInlineKeyboardButtonData("⏩", fmt.Sprintf("log:calendar_nav:%d", shift+1)),
+			tgbotapi.NewInlineKeyboardButtonData("⏪", fmt.Sprintf("%s:calendar_nav:%d", prefix, shift-1)),
+			tgbotapi.NewInlineKeyboardButtonData("⏩", fmt.Sprintf("%s:calendar_nav:%d", prefix, shift+1)),
 		)
 		rows = append(rows, row)
 	}
@@ -332,7 +474,7 @@ func BuildLogCalendarKB(shift int64) tgbotapi.InlineKeyboardMarkup {
 				callback = "no:op"
 			} else {
 				row3display = day.Format("02.01")
-				callback = fmt.Sprintf("log:calendar:%s", day.Format("2006-01-02"))
+				callback = fmt.Sprintf("%s:calendar:%s", prefix, day.Format("2006-01-02"))
 			}
 			row3 = append(row3, tgbotapi.NewInlineKeyboardButtonData(row3display, callback))
 		}
@@ -340,8 +482,260 @@ func BuildLogCalendarKB(shift int64) tgbotapi.InlineKeyboardMarkup {
 		rows = append(rows, row3)
 	}
 	{ // Назад
-		row := tgbotapi.NewInlineKeyboardRow(BuildBackInlineKBButton("log:calendar_back"))
+		row := tgbotapi.NewInlineKeyboardRow(BuildBackInlineKBButton(prefix + ":calendar_back"))
 		rows = append(rows, row)
 	}
 	return tgbotapi.NewInlineKeyboardMarkup(rows...)
 }
+
+// Кнопки под напоминанием о брони.
+func BuildReminderKB(id domain.BookingID) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Приду", fmt.Sprintf("remind:coming:%d", id)),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Отменить бронь", fmt.Sprintf("remind:cancel:%d", id)),
+		),
+	)
+}
+
+// /checkin: выбор брони, если подходящих несколько.
+func BuildCheckinKB(bks []domain.Booking) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(bks))
+	for _, bk := range bks {
+		label := fmt.Sprintf("%s %s-%s",
+			bk.RoomName,
+			bk.Range.Start.Format("15:04"),
+			bk.Range.End.Format("15:04"),
+		)
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("checkin:do:%d", bk.ID)),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// Действия под карточкой записи журнала. editable — показывать правку и аннулирование.
+// Что доступно на карточке записи.
+type LogCardOpts struct {
+	Editable    bool // правка и аннулирование
+	Attachable  bool // можно приложить файл
+	Attachments int  // сколько файлов уже приложено
+}
+
+func BuildLogCardKB(rec domain.LogRecord, opts LogCardOpts) tgbotapi.InlineKeyboardMarkup {
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TextLogCopyButton, fmt.Sprintf("logcard:copy:%s:%d", rec.Kind, rec.ID)),
+			tgbotapi.NewInlineKeyboardButtonData(TextLogOwnerLogsButton, fmt.Sprintf("logcard:owner:%s:%d", rec.Kind, rec.UserID)),
+		),
+	}
+	if opts.Editable {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TextLogEditButton, fmt.Sprintf("logedit:start:%s:%d", rec.Kind, rec.ID)),
+			tgbotapi.NewInlineKeyboardButtonData(TextLogVoidButton, fmt.Sprintf("logvoid:start:%s:%d", rec.Kind, rec.ID)),
+		))
+	}
+	var files []tgbotapi.InlineKeyboardButton
+	if opts.Attachable {
+		files = append(files, tgbotapi.NewInlineKeyboardButtonData(TextLogAttachButton,
+			fmt.Sprintf("logattach:start:%s:%d", rec.Kind, rec.ID)))
+	}
+	if opts.Attachments > 0 {
+		files = append(files, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf(TextLogAttachListButton, opts.Attachments),
+			fmt.Sprintf("logattach:list:%s:%d", rec.Kind, rec.ID)))
+	}
+	if len(files) > 0 {
+		rows = append(rows, files)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TextLogHistoryButton, fmt.Sprintf("logedit:history:%s:%d", rec.Kind, rec.ID)),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// Кнопка перезапуска диалога, сессия которого потерялась.
+func BuildSessionRestartKB(kind domain.SessionKind) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TextSessionRestartButton, "session:restart:"+string(kind)),
+		),
+	)
+}
+
+// Кнопка завершения приёма файлов к записи.
+func BuildLogAttachDoneKB(rec domain.LogRecord) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TextLogAttachDoneButton, fmt.Sprintf("logattach:done:%s:%d", rec.Kind, rec.ID)),
+		),
+	)
+}
+
+// Выбор поля для правки записи.
+func BuildLogEditFieldKB(rec domain.LogRecord) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TextLogFieldDoveritel,
+				fmt.Sprintf("logedit:field:%s:%s:%d", domain.LogFieldDoveritel, rec.Kind, rec.ID)),
+			tgbotapi.NewInlineKeyboardButtonData(TextLogFieldComment,
+				fmt.Sprintf("logedit:field:%s:%s:%d", domain.LogFieldComment, rec.Kind, rec.ID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(BuildLogEditCancelButton(rec)),
+	)
+}
+
+func BuildLogEditCancelButton(rec domain.LogRecord) tgbotapi.InlineKeyboardButton {
+	return BuildBackInlineKBButton(fmt.Sprintf("logedit:cancel:%s:%d", rec.Kind, rec.ID))
+}
+
+// Похожие клиенты при вводе доверителя: log:client:<clientID>, 0 — новый.
+func BuildLogClientSuggestKB(clients []domain.Client) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(clients)+2)
+	for _, c := range clients {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(ClientButtonLabel(c), fmt.Sprintf("log:client:%d", c.ID)),
+		))
+	}
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(TextLogClientNewButton, "log:client:0")),
+		tgbotapi.NewInlineKeyboardRow(BuildBackInlineKBButton("log:step4_back")),
+	)
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// Результаты /search: кнопка на каждую доступную карточку и листание страниц.
+func BuildLogSearchKB(recs []domain.LogRecord, total, offset, pageSize int) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, 2)
+
+	open := make([]tgbotapi.InlineKeyboardButton, 0, len(recs))
+	for _, rec := range recs {
+		if rec.Hidden {
+			continue
+		}
+		open = append(open, tgbotapi.NewInlineKeyboardButtonData(
+			rec.Number(), fmt.Sprintf("search:open:%s:%d", rec.Kind, rec.ID)))
+	}
+	if len(open) > 0 {
+		rows = append(rows, open)
+	}
+
+	if total > pageSize {
+		pages := (total + pageSize - 1) / pageSize
+		page := offset/pageSize + 1
+
+		nav := make([]tgbotapi.InlineKeyboardButton, 0, 3)
+		if offset > 0 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("◀️", fmt.Sprintf("search:page:%d", offset-pageSize)))
+		}
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d/%d", page, pages), "no:op"))
+		if offset+pageSize < total {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("▶️", fmt.Sprintf("search:page:%d", offset+pageSize)))
+		}
+		rows = append(rows, nav)
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// Записи адвоката: карточки, листание и выгрузка всех его записей в Excel.
+func BuildLogListKB(kind domain.LogKind, userID domain.UserID, recs []domain.LogRecord, total, offset, pageSize int) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, 3)
+
+	open := make([]tgbotapi.InlineKeyboardButton, 0, len(recs))
+	for _, rec := range recs {
+		open = append(open, tgbotapi.NewInlineKeyboardButtonData(
+			rec.Number(), fmt.Sprintf("search:open:%s:%d", rec.Kind, rec.ID)))
+	}
+	if len(open) > 0 {
+		rows = append(rows, open)
+	}
+
+	if total > pageSize {
+		pages := (total + pageSize - 1) / pageSize
+		page := offset/pageSize + 1
+
+		nav := make([]tgbotapi.InlineKeyboardButton, 0, 3)
+		if offset > 0 {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("◀️",
+				fmt.Sprintf("logmy:page:%s:%d:%d", kind, userID, offset-pageSize)))
+		}
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d/%d", page, pages), "no:op"))
+		if offset+pageSize < total {
+			nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("▶️",
+				fmt.Sprintf("logmy:page:%s:%d:%d", kind, userID, offset+pageSize)))
+		}
+		rows = append(rows, nav)
+	}
+
+	if total > 0 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TextLogListExportButton, fmt.Sprintf("logmy:export:%d", userID)),
+		))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// Варианты настройки /remind, в минутах. 0 — выключить.
+var remindOptions = []int{0, 5, 10, 15, 30, 60}
+
+func BuildRemindSettingsKB(current time.Duration) tgbotapi.InlineKeyboardMarkup {
+	row := make([]tgbotapi.InlineKeyboardButton, 0, len(remindOptions))
+	for _, m := range remindOptions {
+		label := fmt.Sprintf("%d мин", m)
+		if m == 0 {
+			label = "Выкл"
+		}
+		if time.Duration(m)*time.Minute == current {
+			label = "• " + label
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("remind:set:%d", m)))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(row[:3], row[3:])
+}
+
+// Выбор периода выгрузки журналов.
+func BuildExportPeriodKB() tgbotapi.InlineKeyboardMarkup {
+	btn := func(text string, p domain.ReportPeriod) tgbotapi.InlineKeyboardButton {
+		return tgbotapi.NewInlineKeyboardButtonData(text, fmt.Sprintf("export:period:%s", p))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(btn(TextExportMonth, domain.ReportPeriodMonth), btn(TextExportPrevMonth, domain.ReportPeriodPrevMonth)),
+		tgbotapi.NewInlineKeyboardRow(btn(TextExportQuarter, domain.ReportPeriodQuarter), btn(TextExportPrevQuarter, domain.ReportPeriodPrevQuarter)),
+		tgbotapi.NewInlineKeyboardRow(btn(TextExportYear, domain.ReportPeriodYear), btn(TextExportPrevYear, domain.ReportPeriodPrevYear)),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(TextExportCustom, "export:period:custom")),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(TextExportCancelButton, "export:cancel")),
+	)
+}
+
+// Фильтры выгрузки: тип переключается по кругу, автор выбирается из списка.
+func BuildExportFiltersKB(session *ExportSession) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📑 Тип: "+ExportKindName(session.Query.Kind), "export:kind"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("👤 Автор: "+ExportAuthorName(session.AuthorName), "export:authors"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(TextExportRunButton, "export:run"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			BuildBackInlineKBButton("export:period_back"),
+			tgbotapi.NewInlineKeyboardButtonData(TextExportCancelButton, "export:cancel"),
+		),
+	)
+}
+
+// Выбор автора записей для выгрузки.
+func BuildExportAuthorsKB(users []domain.User) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(users)+2)
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(TextExportAllAuthors, "export:author:0"),
+	))
+	for _, u := range users {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(u.FIO, fmt.Sprintf("export:author:%d", u.ID)),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(BuildBackInlineKBButton("export:filters")))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
@@ -1,12 +1,13 @@
 package tools
 
 import (
-	"sync"
+	"context"
 	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
 )
 
 type LogsSession struct {
-	State        int64
 	UserID       int64
 	MessageID    int
 	Type         string // "sogl" или "zapros"
@@ -15,36 +16,48 @@ type LogsSession struct {
 	Doveritel    string
 	Comment      string
 	Registration bool
-}
+	ClientID     int64 // клиент из реестра, выбранный из подсказок
 
-// states
-const (
-	StateProcessingLogCreating = iota
-	StateInputingName
-	StateInputingDoveritel
-	StageInputingComment
-	StateCreateConfirming
-)
+	// Файлы, присланные до подтверждения; прикладываются после создания записи
+	Files []domain.LogAttachment
 
+	// Правка и аннулирование существующей записи (Type — её тип)
+	EditID    int64
+	EditField string
+}
+
+// Сессии журналов: переживают рестарт, истекают через telegram.session_ttl.
 type LogsStore struct {
-	data sync.Map // key: int64, value: *LogsSession
+	store *sessionStore
 }
 
-func NewLogsStore() *LogsStore {
-	return &LogsStore{}
+func NewLogsStore(backend SessionBackend) *LogsStore {
+	return &LogsStore{store: newSessionStore(domain.SessionLogs, backend)}
 }
 
-func (s *LogsStore) Get(userID int64) *LogsSession {
-	if val, ok := s.data.Load(userID); ok {
+func (s *LogsStore) Get(ctx context.Context, userID int64) *LogsSession {
+	if val := s.store.get(ctx, userID, &LogsSession{}); val != nil {
 		return val.(*LogsSession)
 	}
 	return nil
 }
 
-func (s *LogsStore) Set(session *LogsSession) {
-	s.data.Store(session.UserID, session)
+func (s *LogsStore) Set(ctx context.Context, session *LogsSession) {
+	s.store.set(ctx, session.UserID, session)
+}
+
+func (s *LogsStore) Delete(ctx context.Context, userID int64) {
+	s.store.delete(ctx, userID)
+}
+
+func (s *LogsStore) Expired(ctx context.Context, userID int64) bool {
+	return s.store.expired(ctx, userID)
+}
+
+func (s *LogsStore) Flush(ctx context.Context, userID int64) {
+	s.store.flush(ctx, userID)
 }
 
-func (s *LogsStore) Delete(userID int64) {
-	s.data.Delete(userID)
+func (s *LogsStore) Evict(now time.Time) {
+	s.store.evict(now)
 }
@@ -0,0 +1,28 @@
+package tools
+
+import (
+	"sync"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+// Последний поиск пользователя по журналам — для листания страниц:
+// в callback data весь фильтр не помещается.
+type SearchStore struct {
+	data sync.Map // key: int64, value: domain.LogSearchFilter
+}
+
+func NewSearchStore() *SearchStore {
+	return &SearchStore{}
+}
+
+func (s *SearchStore) Get(userID int64) (domain.LogSearchFilter, bool) {
+	if val, ok := s.data.Load(userID); ok {
+		return val.(domain.LogSearchFilter), true
+	}
+	return domain.LogSearchFilter{}, false
+}
+
+func (s *SearchStore) Set(userID int64, f domain.LogSearchFilter) {
+	s.data.Store(userID, f)
+}
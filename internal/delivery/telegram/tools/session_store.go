@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+// Хранилище сессий диалогов (usecase.SessionService).
+type SessionBackend interface {
+	LoadSession(ctx context.Context, kind domain.SessionKind, userID int64) (domain.Session, error)
+	SaveSession(ctx context.Context, kind domain.SessionKind, userID int64, data []byte) (time.Time, error)
+	DeleteSession(ctx context.Context, kind domain.SessionKind, userID int64) error
+}
+
+// sessionStore держит рабочие копии сессий в памяти и сохраняет их в
+// SessionBackend. Обработчики меняют сессию по указателю, поэтому
+// сохраняется она в Flush после обработки апдейта, а из хранилища
+// читается, только если в памяти её нет (например, после рестарта).
+type sessionStore struct {
+	kind    domain.SessionKind
+	backend SessionBackend
+
+	mu   sync.Mutex
+	live map[int64]*liveSession
+}
+
+type liveSession struct {
+	value     any
+	dirty     bool // менялась с последнего сохранения
+	expiresAt time.Time
+}
+
+func newSessionStore(kind domain.SessionKind, backend SessionBackend) *sessionStore {
+	return &sessionStore{kind: kind, backend: backend, live: make(map[int64]*liveSession)}
+}
+
+// get возвращает сессию или nil. target — пустое значение нужного типа,
+// в него читается сессия из хранилища.
+func (s *sessionStore) get(ctx context.Context, userID int64, target any) any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if l, ok := s.live[userID]; ok {
+		if !l.expiresAt.IsZero() && time.Now().After(l.expiresAt) {
+			delete(s.live, userID)
+			return nil
+		}
+		l.dirty = true
+		return l.value
+	}
+
+	sess, err := s.backend.LoadSession(ctx, s.kind, userID)
+	if err != nil || sess.Expired(time.Now()) {
+		return nil
+	}
+	if err := json.Unmarshal(sess.Data, target); err != nil {
+		return nil
+	}
+	s.live[userID] = &liveSession{value: target, dirty: true, expiresAt: sess.ExpiresAt}
+	return target
+}
+
+func (s *sessionStore) set(ctx context.Context, userID int64, value any) {
+	s.mu.Lock()
+	l := &liveSession{value: value}
+	s.live[userID] = l
+	s.mu.Unlock()
+
+	s.save(ctx, userID, l)
+}
+
+func (s *sessionStore) delete(ctx context.Context, userID int64) {
+	s.mu.Lock()
+	delete(s.live, userID)
+	s.mu.Unlock()
+
+	_ = s.backend.DeleteSession(ctx, s.kind, userID)
+}
+
+// expired — сессия пользователя истекла (а не была завершена или не начиналась).
+func (s *sessionStore) expired(ctx context.Context, userID int64) bool {
+	sess, err := s.backend.LoadSession(ctx, s.kind, userID)
+	return err == nil && sess.Expired(time.Now())
+}
+
+// flush сохраняет сессию пользователя, если её читали или меняли.
+func (s *sessionStore) flush(ctx context.Context, userID int64) {
+	s.mu.Lock()
+	l, ok := s.live[userID]
+	if !ok || !l.dirty {
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+
+	s.save(ctx, userID, l)
+}
+
+func (s *sessionStore) save(ctx context.Context, userID int64, l *liveSession) {
+	s.mu.Lock()
+	l.dirty = false
+	s.mu.Unlock()
+
+	data, err := json.Marshal(l.value)
+	if err != nil {
+		return
+	}
+	expiresAt, err := s.backend.SaveSession(ctx, s.kind, userID, data)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		// Хранилище недоступно: рабочая копия остаётся в памяти,
+		// сохраним её со следующим апдейтом
+		l.dirty = true
+		return
+	}
+	l.expiresAt = expiresAt
+}
+
+// evict убирает из памяти истёкшие сессии.
+func (s *sessionStore) evict(now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, l := range s.live {
+		if !l.expiresAt.IsZero() && now.After(l.expiresAt) {
+			delete(s.live, id)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -21,15 +22,17 @@ const (
 // тексты кнопок
 const (
 	TextBackInlineKBButton = "🔙 Назад"
+	TextSkipInlineKBButton = "⏭ Пропустить"
 
 	TextMainBookButton = "📝 Забронировать"
 	TextMainMyButton   = "📋 Мои бронирования"
 
 	TextMainScheduleButton = "📅 Расписание"
 
-	TextMainCreateRoomButton = "➕ Создать комнату"
-	TextMainDeleteRoomButton = "🗑️ Удалить комнату"
-	TextMainHelpButton       = "ℹ️ Помощь"
+	TextMainCreateRoomButton  = "➕ Создать комнату"
+	TextMainDeleteRoomButton  = "🗑️ Удалить комнату"
+	TextMainAdminCancelButton = "🚫 Отменить чужую бронь"
+	TextMainHelpButton        = "ℹ️ Помощь"
 
 	// Журналы
 	TextMainLogButton = "📔 Соглашения и Запросы"
@@ -43,7 +46,7 @@ const (
 
 // тексты Журналов
 const (
-	TextLogMainMenu SafeText = "📔 Вы в меню журналов. Выберите действие:"
+	TextLogMainMenu SafeText = "📔 Вы в меню журналов. Выберите действие:\n🔎 Найти запись по номеру: */find ЭС-2026/0012*\n🔍 Поиск по доверителю и комментарию: */search Иванов*"
 	// Тексты inline
 	TextLogSogl   = "Соглашение"
 	TextLogZapros = "Запрос"
@@ -62,11 +65,324 @@ const (
 👤 ФИО: *%s*
 📜 Доверитель: *%s*
 💬 Комментарий: *%s*`
-	TextLogYes            = "🎉 Запись успешно создана!\nВаш номер записи: `%s%d`"
-	TextLogError SafeText = `⚠️ Не получилось создать запись. Тех поддержка уже уведомлена`
-	TextLogNo    SafeText = "❌ Создание записи отменено."
+	TextLogYes                       = "🎉 Запись успешно создана!\nВаш номер записи: `%s`"
+	TextLogError            SafeText = `⚠️ Не получилось создать запись. Тех поддержка уже уведомлена`
+	TextLogNo               SafeText = "❌ Создание записи отменено."
+	TextLogUserInactive     SafeText = "🚫 Создание записей для вас отключено. Обратитесь к администратору."
+	TextLogConfirmFiles              = "\n📎 Вложения: *%d*"
+	TextLogConfirmFilesHint          = "\n\n📎 До подтверждения можно прислать скан документа файлом или фото — он будет приложен к записи."
+	TextLogYesFiles                  = "\n📎 Приложено файлов: %d из %d"
+
+	// Тексты /find
+	TextLogFindUsage     SafeText = "🔎 Укажите номер записи, например: */find ЭС-2026/0012* или */find ЭЗ-2026/7*"
+	TextLogFindNotFound  SafeText = "📄 Запись *%s* не найдена."
+	TextLogFindForbidden SafeText = "⚠️ Запись *%s* принадлежит другому адвокату. Смотреть можно только свои записи."
+	TextLogFindErr       SafeText = "⚠️ Не удалось найти запись. Тех поддержка уже уведомлена"
+	TextLogCard          SafeText = `📄 *%s %s*
+📅 Дата: *%s*
+👤 Адвокат: *%s*
+📜 Доверитель: %s
+💬 Комментарий: %s
+⏰ Создано: %s`
+	TextLogCopyNumber                   = "`%s`"
+	TextLogCopyButton                   = "📋 Номер"
+	TextLogOwnerLogsButton              = "🗂 Другие записи адвоката"
+	TextLogOwnerLogsNotAllowed SafeText = "⚠️ Смотреть можно только свои записи."
+	TextLogListErr             SafeText = "Ошибка при получении записей 😔"
+	TextLogListExportButton             = "📥 Скачать все записи"
+	TextLogListExportEmpty     SafeText = "📭 Записей для выгрузки нет."
+	TextLogListExportCaption            = "📊 Записи журналов %s: запросы, соглашения и сводка"
+)
+
+// тексты реестра доверителей
+const (
+	TextLogClientSuggest SafeText = `👥 Похожие доверители уже есть в реестре.
+Выберите клиента, если это он, или оставьте нового:`
+	TextLogClientNewButton = "➕ Новый доверитель"
+
+	TextClientsMergeUsage SafeText = `👥 *Объединение дублей доверителей*
+*/merge_clients Ромашка* — найти клиентов и их номера
+*/merge_clients 12 7* — перенести записи клиента №12 на клиента №7 и удалить №12`
+	TextClientsNotFound   SafeText = "👥 Похожих клиентов не найдено."
+	TextClientsMerged              = "✅ Записи клиента №%d перенесены на «%s» (№%d), всего записей у клиента: %d."
+	TextClientsMissing    SafeText = "⚠️ Клиент не найден. Номера клиентов: */merge_clients Ромашка*"
+	TextClientsNotAllowed SafeText = "⚠️ Объединять клиентов могут только администраторы."
+	TextClientsMergeErr   SafeText = "Ошибка при объединении клиентов 😔"
+)
+
+// Подпись кнопки клиента: имя (число записей). Длинные имена обрезаются,
+// чтобы кнопка оставалась читаемой.
+func ClientButtonLabel(c domain.Client) string {
+	name := []rune(c.Name)
+	if len(name) > 40 {
+		name = append(name[:39], '…')
+	}
+	return fmt.Sprintf("%s (%d)", string(name), c.Records)
+}
+
+func BuildClientListStr(clients []domain.Client) SafeText {
+	if len(clients) == 0 {
+		return TextClientsNotFound
+	}
+
+	var b strings.Builder
+	b.WriteString("*👥 Клиенты:*\n\n")
+	for _, c := range clients {
+		b.WriteString(fmt.Sprintf("№%d *%s* — записей: %d\n", c.ID, StripMarkup(c.Name), c.Records))
+	}
+	b.WriteString("\nОбъединить: */merge_clients <из> <в>*")
+	return SafeText(b.String())
+}
+
+// тексты управления адвокатами
+const (
+	TextLawyersUsage SafeText = `👩‍⚖️ *Адвокаты*
+*/lawyers* — список зарегистрированных адвокатов
+*/lawyer_add 123456789 Иванов Иван Иванович* — зарегистрировать заранее по Telegram ID
+*/lawyer_fio 123456789 Иванов Иван Иванович* — исправить ФИО (в созданных записях останется прежнее)
+*/lawyer_off 123456789* — отключить ушедшего адвоката, его записи сохранятся
+*/lawyer_on 123456789* — включить обратно`
+	TextLawyersEmpty      SafeText = "👩‍⚖️ Зарегистрированных адвокатов пока нет."
+	TextLawyersNotAllowed SafeText = "⚠️ Управлять адвокатами могут только администраторы."
+	TextLawyersErr        SafeText = "Ошибка при изменении данных адвоката 😔"
+	TextLawyerAdded                = "✅ Адвокат *%s* зарегистрирован (ID `%d`)."
+	TextLawyerRenamed              = "✅ ФИО изменено: *%s* → *%s*. В созданных записях осталось прежнее ФИО."
+	TextLawyerDeactivated          = "🚫 *%s* отключён: новые записи создавать нельзя, старые сохранены."
+	TextLawyerActivated            = "✅ *%s* снова может создавать записи."
+	TextLawyerNotFound             = "⚠️ Адвокат с ID `%d` не найден. Список: */lawyers*"
+	TextLawyerExists               = "⚠️ Адвокат с ID `%d` уже зарегистрирован: *%s*. Исправить ФИО: */lawyer_fio*"
+)
+
+func BuildLawyersListStr(users []domain.User) SafeText {
+	if len(users) == 0 {
+		return TextLawyersEmpty
+	}
+
+	var b strings.Builder
+	b.WriteString("*👩‍⚖️ Адвокаты:*\n\n")
+	for _, u := range users {
+		status := ""
+		if !u.Active {
+			status = " — 🚫 отключён"
+		}
+		b.WriteString(fmt.Sprintf("`%d` *%s*%s\n", u.ID, StripMarkup(u.FIO), status))
+	}
+	b.WriteString("\nКоманды: */lawyer_add*, */lawyer_fio*, */lawyer_off*, */lawyer_on*")
+	return SafeText(b.String())
+}
+
+// тексты /search
+const (
+	TextLogSearchUsage SafeText = `🔎 *Поиск по доверителю и комментарию*
+Пример: */search Иванов аренда*
+Фильтры: *тип:ЭС* или *тип:ЭЗ*, *с:01.01.2024*, *по:31.12.2024*, *мои* — только свои записи.
+Каждое слово — не короче 3 символов.`
+	TextLogSearchEmpty   SafeText = "📄 По запросу *%s* ничего не найдено."
+	TextLogSearchHeader           = "🔎 *%s* — найдено: %d\n\n"
+	TextLogSearchItem             = "%d. `%s` · %s · %s\n%s\n"
+	TextLogSearchHidden           = "🔒 запись другого адвоката"
+	TextLogSearchExpired SafeText = "⚠️ Результаты поиска устарели, повторите /search."
+	TextLogSearchErr     SafeText = "⚠️ Не удалось выполнить поиск. Тех поддержка уже уведомлена"
+)
+
+// Короткая выжимка записи для списка результатов. У чужих записей
+// содержимого нет — только отметка.
+func logSnippet(rec domain.LogRecord) string {
+	if rec.Hidden {
+		return TextLogSearchHidden
+	}
+	s := rec.Doveritel
+	if rec.Comment != "" {
+		s += " — " + rec.Comment
+	}
+	r := []rune(StripMarkup(s))
+	if len(r) > 80 {
+		return "📜 " + string(r[:79]) + "…"
+	}
+	return "📜 " + string(r)
+}
+
+func BuildLogSearchStr(query string, recs []domain.LogRecord, total, offset int) SafeText {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(TextLogSearchHeader, StripMarkup(query), total))
+	for i, rec := range recs {
+		b.WriteString(fmt.Sprintf(TextLogSearchItem,
+			offset+i+1,
+			rec.Number(),
+			rec.Date.Format("02.01.2006"),
+			StripMarkup(rec.UserName),
+			logSnippet(rec),
+		))
+	}
+	return SafeText(b.String())
+}
+
+// тексты выгрузки журналов
+const (
+	TextExportMonth        = "Этот месяц"
+	TextExportPrevMonth    = "Прошлый месяц"
+	TextExportQuarter      = "Этот квартал"
+	TextExportPrevQuarter  = "Прошлый квартал"
+	TextExportYear         = "Этот год"
+	TextExportPrevYear     = "Прошлый год"
+	TextExportCustom       = "📅 Свой период"
+	TextExportRunButton    = "📤 Сформировать"
+	TextExportCancelButton = "❌ Отмена"
+	TextExportAll          = "Все"
+	TextExportAllAuthors   = "Все авторы"
+
+	TextExportChoosePeriod SafeText = "📤 *Экспорт журналов*\nВыберите период (по дате записи):"
+	TextExportPickFrom     SafeText = "📅 Выберите *первый* день периода:"
+	TextExportPickTo       SafeText = "📅 Начало периода: *%s*\nВыберите *последний* день:"
+	TextExportBadRange              = "Конец периода не может быть раньше начала"
+	TextExportFilters      SafeText = `📤 *Экспорт журналов*
+
+📅 Период: %s — %s
+📑 Тип: %s
+👤 Автор: %s
+
+Уточните фильтры или нажмите «Сформировать».`
+	TextExportChooseAuthor SafeText = "👤 Выберите автора записей:"
+	TextExportInProgress   SafeText = "⏳ Формирую отчёт за %s — %s…"
+	TextExportNoRecords    SafeText = "📭 За период %s — %s подходящих записей нет."
+	TextExportNotAllowed   SafeText = "⚠️ Экспорт журналов доступен только администраторам."
+	TextExportCancelled    SafeText = "❎ Экспорт отменён."
+	TextExportErr          SafeText = "Ошибка при создании отчета 😔"
+	TextExportCaption               = "📊 Журналы за %s — %s: запросы, соглашения и сводка"
+)
+
+func ExportKindName(k domain.LogKind) string {
+	if k == "" {
+		return TextExportAll
+	}
+	return logKindName(k)
+}
+
+func ExportAuthorName(name string) string {
+	if name == "" {
+		return TextExportAllAuthors
+	}
+	return name
+}
+
+func BuildExportFiltersStr(session *ExportSession) SafeText {
+	q := session.Query
+	return SafeText(fmt.Sprintf(string(TextExportFilters),
+		q.From.Format("02.01.2006"),
+		q.To.Format("02.01.2006"),
+		ExportKindName(q.Kind),
+		StripMarkup(ExportAuthorName(session.AuthorName)),
+	))
+}
+
+// тексты правки и аннулирования записей
+const (
+	TextLogEditButton     = "✏️ Изменить"
+	TextLogVoidButton     = "🚫 Аннулировать"
+	TextLogHistoryButton  = "🕓 История"
+	TextLogFieldDoveritel = "📜 Доверитель"
+	TextLogFieldComment   = "💬 Комментарий"
+	TextLogCardVoided     = "\n🚫 *АННУЛИРОВАНА* %s: %s"
+	TextLogCardUpdated    = "\n✏️ Изменена: %s"
+
+	TextLogEditChooseField SafeText = "✏️ Что исправить в записи *%s*?"
+	TextLogEditAskValue    SafeText = `✏️ Введите новое значение поля «%s» записи *%s*
+Сейчас: %s`
+	TextLogVoidAskReason SafeText = `🚫 Аннулирование записи *%s*
+Номер останется занятым, а запись — в журнале с отметкой и причиной.
+
+📝 Напишите причину аннулирования:`
+	TextLogEditTooLong    SafeText = "⚠️ Текст пустой или слишком длинный. Максимум %d символов."
+	TextLogVoidReasonBad  SafeText = "⚠️ Причина должна быть от %d до %d символов."
+	TextLogEditDone       SafeText = "✅ Запись обновлена."
+	TextLogVoidDone       SafeText = "✅ Запись аннулирована."
+	TextLogEditCancelled  SafeText = "❎ Изменение записи отменено."
+	TextLogEditForbidden  SafeText = "⚠️ Изменять можно только свои записи."
+	TextLogEditWindowOver SafeText = "⚠️ Срок самостоятельной правки записи истёк (%s после создания). Обратитесь к администратору."
+	TextLogEditVoided     SafeText = "⚠️ Запись аннулирована, изменить её нельзя."
+	TextLogEditErr        SafeText = "⚠️ Не удалось изменить запись. Тех поддержка уже уведомлена"
+	TextLogHistoryHeader           = "🕓 *История записи %s*\n"
+	TextLogHistoryEmpty            = "\nИзменений не было."
+	TextLogHistoryEdit             = "\n%s · %s\n%s: «%s» → «%s»\n"
+	TextLogHistoryVoid             = "\n%s · %s\n🚫 Аннулирована: %s\n"
+
+	// Сессии диалогов
+	TextSessionExpired       SafeText = "⌛ Сессия истекла: вы долго не отвечали, и введённые данные не сохранились. Начните заново."
+	TextSessionNotFound      SafeText = "⚠️ Сессия не найдена. Начните заново."
+	TextSessionRestartButton          = "🔄 Начать заново"
+	TextFlowUseButtons       SafeText = "Сейчас нужно выбрать вариант кнопками выше. Чтобы начать другое действие, отправьте команду из /help."
+
+	// Вложения записей журналов
+	TextLogAttachButton              = "📎 Приложить"
+	TextLogAttachListButton          = "📎 Вложения (%d)"
+	TextLogAttachDoneButton          = "✅ Готово"
+	TextLogAttachAsk        SafeText = `📎 Пришлите скан документа к записи *%s* файлом или фото. Можно несколько, до %d файлов.
+Когда закончите, нажмите «Готово».`
+	TextLogAttachSaved     SafeText = "📎 Файл приложен (%d из %d)."
+	TextLogAttachNotFile   SafeText = "⚠️ Пришлите документ файлом или фото либо нажмите «Готово»."
+	TextLogAttachConfirm   SafeText = "⚠️ Подтвердите или отмените создание записи кнопками выше. Скан документа можно прислать файлом или фото."
+	TextLogAttachExists    SafeText = "⚠️ Этот файл уже приложен к записи."
+	TextLogAttachLimit     SafeText = "⚠️ К записи можно приложить не больше %d файлов."
+	TextLogAttachForbidden SafeText = "⚠️ Прикладывать файлы можно только к своим записям."
+	TextLogAttachVoided    SafeText = "⚠️ Запись аннулирована, приложить к ней файл нельзя."
+	TextLogAttachErr       SafeText = "⚠️ Не удалось приложить файл. Тех поддержка уже уведомлена"
+	TextLogAttachEmpty     SafeText = "📎 К записи *%s* ничего не приложено."
+	TextLogAttachListErr   SafeText = "⚠️ Не удалось получить вложения записи. Тех поддержка уже уведомлена"
+	TextLogAttachCaption            = "%s · %d/%d"
 )
 
+func logKindName(k domain.LogKind) string {
+	if k == domain.LogKindSogl {
+		return TextLogSogl
+	}
+	return TextLogZapros
+}
+
+func LogFieldName(f domain.LogField) string {
+	if f == domain.LogFieldDoveritel {
+		return TextLogFieldDoveritel
+	}
+	return TextLogFieldComment
+}
+
+func BuildLogCardStr(rec domain.LogRecord, tz *time.Location) SafeText {
+	str := fmt.Sprintf(string(TextLogCard),
+		logKindName(rec.Kind),
+		rec.Number(),
+		rec.Date.Format("02.01.2006"),
+		StripMarkup(rec.UserName),
+		StripMarkup(rec.Doveritel),
+		StripMarkup(rec.Comment),
+		rec.CreatedAt.In(tz).Format("02.01.2006 15:04"),
+	)
+	if !rec.UpdatedAt.IsZero() {
+		str += fmt.Sprintf(TextLogCardUpdated, rec.UpdatedAt.In(tz).Format("02.01.2006 15:04"))
+	}
+	if rec.Voided() {
+		str += fmt.Sprintf(TextLogCardVoided, rec.VoidedAt.In(tz).Format("02.01.2006 15:04"), StripMarkup(rec.VoidReason))
+	}
+	return SafeText(str)
+}
+
+func BuildLogHistoryStr(rec domain.LogRecord, revs []domain.LogRevision, tz *time.Location) SafeText {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(TextLogHistoryHeader, rec.Number()))
+	if len(revs) == 0 {
+		b.WriteString(TextLogHistoryEmpty)
+	}
+	for _, rev := range revs {
+		when := rev.CreatedAt.In(tz).Format("02.01.2006 15:04")
+		switch rev.Action {
+		case domain.LogRevisionVoid:
+			b.WriteString(fmt.Sprintf(TextLogHistoryVoid, when, StripMarkup(rev.ActorName), StripMarkup(rev.Reason)))
+		default:
+			b.WriteString(fmt.Sprintf(TextLogHistoryEdit, when, StripMarkup(rev.ActorName),
+				LogFieldName(rev.Field), StripMarkup(rev.OldValue), StripMarkup(rev.NewValue)))
+		}
+	}
+	return SafeText(b.String())
+}
+
 // тексты /start /help menu
 const (
 	TextMainMenu              SafeText = "🏠 Вы в главном меню"
@@ -82,17 +398,26 @@ const (
 
 	TextHelpMessage SafeText = `👋 *Описание всего функционала:*
 
-📝 • *Забронировать* — укажите *переговорку*, удобную *дату* и *время* для встречи. В прошлое и занятое время забронировать не получится. 
-📋 • *Мои брони* — покажу список ваших броней с возможностью их *отменить*
+📝 • *Забронировать* — укажите *переговорку*, удобную *дату* и *время* для встречи. В прошлое и занятое время забронировать не получится. Бронь можно *повторять* (ежедневно, по будням, еженедельно)
+📋 • *Мои брони* — покажу список ваших броней с возможностью их *перенести* или *отменить* (по одной или всю серию)
 📅 • *Расписание* — покажу расписание переговорок на следующие 7 дней
+🟢 • */free* — свободные переговорки сейчас и свободное время на сегодня. */free 15:00 2* — кто свободен с 15:00 на 2 часа
+⏰ • */remind* — за сколько до начала встречи присылать напоминание (по умолчанию за 15 минут)
+👋 • */checkin* — отметиться о приходе. Если никто не отметился вскоре после начала встречи, остаток брони освобождается
 ℹ️ • *Помощь* — покажу это сообщение`
 )
 
 // тексты admin /help /start
 const (
-	TextAdminStartMessage SafeText = "🛠️ • *Создать комнату* / *Удалить комнату* — кнопки для управления комнатами"
+	TextAdminStartMessage SafeText = `🛠️ • *Создать комнату* / *Удалить комнату* — кнопки для управления комнатами
+🚫 • *Отменить чужую бронь* (/cancel_booking) — отмена любой брони с указанием причины
+👥 • */merge_clients* — объединение дублей доверителей в реестре клиентов
+👩‍⚖️ • */lawyers* — адвокаты: регистрация по Telegram ID, правка ФИО, отключение`
 
-	TextAdminHelpMessage SafeText = "🛠️ • *Создать комнату* / *Удалить комнату* — доступны и видны только администраторам чата Коллегии"
+	TextAdminHelpMessage SafeText = `🛠️ • *Создать комнату* / *Удалить комнату* — доступны и видны только администраторам чата Коллегии
+🚫 • *Отменить чужую бронь* (/cancel_booking) — владелец получит причину отмены в личные сообщения
+👥 • */merge_clients Ромашка* — найти похожих доверителей, */merge_clients 12 7* — перенести записи клиента №12 на №7
+👩‍⚖️ • */lawyers* — список адвокатов; */lawyer_add*, */lawyer_fio*, */lawyer_off*, */lawyer_on* — регистрация, правка ФИО, отключение`
 )
 
 // тексты /book
@@ -109,6 +434,12 @@ const (
 	TextBookAskTimeInput SafeText = `🕗 Введите начало брони:
 (в формате xx, xx:00 ИЛИ xx:30)`
 
+	TextBookAskTimePick SafeText = `🕗 Выберите свободное время начала брони
+или введите вручную (в формате xx, xx:00 ИЛИ xx:30):`
+
+	TextBookNoFreeSlots SafeText = `😢 На эту дату свободного времени нет.
+Выберите другую дату или переговорку.`
+
 	TextBookTimeInvalidInput SafeText = `❌ Неверный формат времени.  
 Попробуйте ещё раз в формате *чч*, *чч:00* или *чч:30*  
 Например: *12*, *12:00* или *12:30*`
@@ -121,9 +452,44 @@ const (
 🕗 Начало: *%s*
 ⏳ Продолжительность: *%s*`
 
+	TextBookAskRecurrence    SafeText = "🔁 Повторять бронь?"
+	TextBookAskRecurrenceEnd SafeText = "🔁 Сколько раз повторить? Или выберите дату окончания серии:"
+	TextBookUntilCalendar    SafeText = "📅 Выберите последнюю дату серии:"
+	TextBookUntilTooEarly             = "Дата окончания раньше начала серии"
+	TextBookRecurrenceLine            = "\n🔁 Повтор: *%s*"
+
+	TextBookAskPurpose   SafeText = "📝 Напишите цель встречи одним сообщением (необязательно):"
+	TextBookAskAttendees SafeText = `👥 Перечислите участников через запятую (необязательно):
+@ник коллеги или просто имя, например: @ivanov, Петров П.П.
+Коллеги с ником получат уведомление в личные сообщения.`
+	TextBookPurposeTooLong   SafeText = "⚠️ *Слишком длинная цель встречи.* Максимум %d символов."
+	TextBookAttendeesUnknown SafeText = "⚠️ *Не знаю, кто это:* %s\nБот находит коллег по нику, только если они уже писали ему. Упомяните коллегу через список упоминаний или напишите имя без @."
+	TextBookTooManyAttendees SafeText = "⚠️ *Слишком много участников.* Максимум %d, имя — до %d символов."
+	TextBookPurposeLine               = "\n📝 Цель: *%s*"
+	TextBookAttendeesLine             = "\n👥 Участники: %s"
+
+	TextBookSeriesCreated   SafeText = "🎉 Серия броней создана! Забронировано: *%d*"
+	TextBookSeriesConflicts SafeText = "\n\n⚠️ Эти даты уже заняты и пропущены:\n"
+	TextBookSeriesRejected  SafeText = "\n\n⚠️ Эти даты не подходят под правила бронирования и пропущены:\n"
+	TextBookInvalidSeries   SafeText = "⚠️ *Некорректное правило повтора.* Пожалуйста, попробуйте снова."
+	TextBookSeriesTooLong   SafeText = "⚠️ *Слишком длинная серия.* В серии может быть не больше %d повторений — выберите дату окончания поближе."
+
+	TextBookRescheduleHeader    SafeText = "🔄 *Перенос брони*\n"
+	TextBookRescheduled         SafeText = "🎉 Бронь успешно перенесена!"
+	TextBookRescheduleNo        SafeText = "❌ Перенос отменён. Бронь осталась без изменений."
+	TextBookRescheduleOverlap   SafeText = "⚠️ *В это время уже есть бронь.* Ваша бронь осталась без изменений."
+	TextBookRescheduleForbidden SafeText = "⚠️ *Перенести можно только свою бронь.*"
+
 	TextBookYes            SafeText = "🎉 Бронь успешно создана!"
 	TextBookNo             SafeText = "❌ Бронь отменена."
 	TextBookTooLateWaring  SafeText = "⚠️ *Нельзя создать бронь в прошлом.* Пожалуйста, выберите другое время."
+	TextBookOutsideHours   SafeText = "⚠️ *Бронь выходит за рабочие часы офиса.*\nРабочие часы в этот день: %s"
+	TextBookClosedDay      SafeText = "нерабочий день"
+	TextBookTooShort       SafeText = "⚠️ *Слишком короткая бронь.* Минимальная длительность: %s"
+	TextBookTooLong        SafeText = "⚠️ *Слишком длинная бронь.* Максимальная длительность: %s"
+	TextBookStepViolation  SafeText = "⚠️ *Время брони должно быть кратно %s.*"
+	TextBookTooFarAhead    SafeText = "⚠️ *Слишком далеко вперёд.* Бронировать можно не более чем на %d дн. вперёд."
+	TextBookTooManyActive  SafeText = "⚠️ *Слишком много активных броней.* Допустимо не более %d. Отмените ненужные в /my."
 	TextBookOverlapWarning SafeText = "⚠️ *В это время уже есть бронь.* Пожалуйста, попробуйте снова."
 	TextBookServerError    SafeText = "⚠️ *Ошибка при создании брони.* Тех. поддержка уведомлена. Попробуйте ещё раз."
 )
@@ -136,15 +502,123 @@ const (
 🕗 Начало: *%s*
 ⏳ Продолжительность: *%s*`
 
+	TextMyRescheduleIntro  SafeText = "🔄 *Перенос брони.* Выберите переговорку:"
 	TextMyBookingCancelled SafeText = "✅ Ваша бронь успешно отменена."
 	TextMyBookingCancelErr SafeText = "⚠️ *Не удалось отменить бронь.* Тех. поддержка уже уведомлена."
+	TextMyCancelForbidden  SafeText = "⚠️ *Отменить можно только свою бронь.*"
+	TextMySeriesCancelled  SafeText = "✅ Серия отменена. Отменено броней: %d"
+	TextMySeriesLine                = "\n🔁 Входит в серию: *%s*"
+)
+
+// уведомления участникам встречи
+const (
+	TextAttendeeInvited SafeText = `👥 *%s приглашает вас на встречу*
+🏢 Переговорка: *%s*
+📅 Дата: *%s*
+🕗 Время: *%s-%s*`
+	TextAttendeeCancelled SafeText = `❌ *Встреча отменена*
+🏢 Переговорка: *%s*
+📅 Дата: *%s*
+🕗 Время: *%s-%s*
+👤 Организатор: %s`
+	TextAttendeeSeriesCancelled = "\n🔁 Отменены все будущие повторения серии: %d"
+)
+
+// тексты /free
+const (
+	TextFreeRoomsNow    SafeText = "🟢 *Свободны %s %s–%s:*\n"
+	TextFreeNoRooms     SafeText = "🔴 В это время все переговорки заняты.\n"
+	TextFreeTodayHeader SafeText = "\n📅 *Свободное время сегодня:*\n"
+	TextFreeRoomSlots   SafeText = "*%s*: %s\n"
+	TextFreeRoomBusy             = "занята до конца дня"
+	TextFreeInvalidArgs SafeText = `❌ Не понял запрос.
+Примеры: */free* — свободные сейчас, */free 15:00* — на час с 15:00, */free 15:00 2* — на 2 часа`
+	TextFreeInPast SafeText = "⚠️ Это время уже прошло."
+)
+
+// тексты напоминаний и /remind
+const (
+	TextReminder SafeText = `⏰ *Скоро встреча — через %s*
+🏢 Переговорка: *%s*
+📅 Дата: *%s*
+🕗 Время: *%s-%s*`
+	TextReminderComing               = "✅ Отлично, ждём вас!"
+	TextReminderCheckinHint          = "\n\n👋 Нажмите «Приду» или отправьте /checkin, иначе через %s после начала бронь освободится."
+	TextReminderNotFound    SafeText = "⚠️ Бронь уже отменена или не найдена."
+	TextRemindSettings      SafeText = "⏰ *Напоминания о бронях*\nСейчас: *%s*\n\nЗа сколько до начала встречи напоминать?"
+	TextRemindSaved         SafeText = "✅ Напоминания: *%s*"
+	TextRemindErr           SafeText = "⚠️ *Не удалось сохранить настройку.* Тех. поддержка уже уведомлена."
+	TextRemindOff                    = "выключены"
+	TextRemindBefore                 = "за %s"
+)
+
+func BuildReminderStr(bk domain.Booking, left, checkinGrace time.Duration) SafeText {
+	str := fmt.Sprintf(string(TextReminder),
+		FormatDuration(left),
+		bk.RoomName,
+		bk.Range.Start.Format("02.01.2006"),
+		bk.Range.Start.Format("15:04"),
+		bk.Range.End.Format("15:04"),
+	) + scheduleDetails(bk)
+	if checkinGrace > 0 {
+		str += fmt.Sprintf(TextReminderCheckinHint, FormatDuration(checkinGrace))
+	}
+	return SafeText(str)
+}
+
+// тексты /checkin и освобождения неиспользованных броней
+const (
+	TextCheckinDisabled SafeText = "ℹ️ Отмечаться о приходе не нужно — брони не освобождаются автоматически."
+	TextCheckinNone     SafeText = "ℹ️ У вас нет идущих или скоро начинающихся броней без отметки."
+	TextCheckinPick     SafeText = "👋 *Отметьтесь о приходе.* Выберите бронь:"
+	TextCheckinDone     SafeText = `✅ *Отметка принята*
+🏢 Переговорка: *%s*
+🕗 Время: *%s-%s*`
+	TextCheckinEnded     SafeText = "⚠️ Эта бронь уже закончилась."
+	TextCheckinForbidden SafeText = "⚠️ *Отметиться можно только в своей брони.*"
+	TextCheckinNotFound  SafeText = "⚠️ Бронь уже отменена, освобождена или не найдена."
+	TextCheckinErr       SafeText = "⚠️ *Не удалось отметиться.* Тех. поддержка уже уведомлена."
+	TextNoShowReleased   SafeText = `🕳 *Бронь освобождена*
+Никто не отметился в течение %s после начала встречи.
+🏢 Переговорка: *%s*
+📅 Дата: *%s*
+🕗 Было: *%s-%s*, свободно с *%s*`
 )
 
+func BuildCheckinDoneStr(bk domain.Booking) SafeText {
+	return SafeText(fmt.Sprintf(string(TextCheckinDone),
+		bk.RoomName,
+		bk.Range.Start.Format("15:04"),
+		bk.Range.End.Format("15:04"),
+	))
+}
+
+func BuildNoShowReleasedStr(rb domain.ReleasedBooking, grace time.Duration) SafeText {
+	return SafeText(fmt.Sprintf(string(TextNoShowReleased),
+		FormatDuration(grace),
+		rb.RoomName,
+		rb.Range.Start.Format("02.01.2006"),
+		rb.Range.Start.Format("15:04"),
+		rb.OriginalEnd.Format("15:04"),
+		rb.Range.End.Format("15:04"),
+	))
+}
+
+// FormatRemindBefore — человекочитаемое значение настройки напоминаний.
+func FormatRemindBefore(d time.Duration) string {
+	if d <= 0 {
+		return TextRemindOff
+	}
+	return fmt.Sprintf(TextRemindBefore, FormatDuration(d))
+}
+
 // тексты /schedule
 const (
 	TextScheduleIntroduction SafeText = "📅 *Расписание на будущую неделю:*"
 	TextWeekScheduleBooking  SafeText = `⁃%s %s:%s-%s:%s  👤 %s`
 	TextTodayScheduleBooking SafeText = `⁃%s:%s-%s:%s  👤 %s`
+	TextScheduleNote                  = "  📝 %s"
+	TextScheduleAttendees             = "\n    👥 %s"
 	TextScheduleError        SafeText = "Ошибка при получении расписания, тех. поддержка уже уведомлена."
 	// - мм.дд 16:30-17:30 @leegeev
 
@@ -165,6 +639,51 @@ const (
 	TextRoomNameIsTooLong  SafeText = "*⚠️ Название комнаты слишком длинное.* Максимум 50 символов."
 )
 
+// тексты отмены брони администратором
+const (
+	TextAdminCancelIntro      SafeText = "🚫 *Отмена брони.* Выберите переговорку:"
+	TextAdminCancelBookings   SafeText = "🚫 *%s* — ближайшие брони. Выберите, какую отменить:"
+	TextAdminCancelNoBookings SafeText = "✅ В переговорке *%s* нет предстоящих броней."
+	TextAdminCancelAskReason  SafeText = `🚫 Отмена брони:
+🏢 Переговорка: *%s*
+📅 Дата: *%s*
+🕗 Время: *%s-%s*
+👤 Владелец: *%s*
+
+📝 Напишите причину отмены — её получит владелец брони:`
+	TextAdminCancelReasonShort SafeText = "⚠️ *Слишком короткая причина.* Минимум 3 символа."
+	TextAdminCancelReasonLong  SafeText = "⚠️ *Слишком длинная причина.* Максимум 500 символов."
+	TextAdminCancelDone        SafeText = "✅ Бронь отменена, владелец уведомлён."
+	TextAdminCancelDoneNoDM    SafeText = "✅ Бронь отменена, но уведомить владельца не удалось: он не начинал диалог с ботом."
+	TextAdminCancelNotFound    SafeText = "⚠️ Бронь уже отменена или не найдена."
+	TextAdminCancelErr         SafeText = "⚠️ *Не удалось отменить бронь.* Тех. поддержка уже уведомлена."
+	TextAdminCancelNotice      SafeText = `🚫 *Ваша бронь отменена администратором*
+🏢 Переговорка: *%s*
+📅 Дата: *%s*
+🕗 Время: *%s-%s*
+💬 Причина: %s`
+)
+
+func BuildAdminCancelAskReasonStr(bk domain.Booking) SafeText {
+	return SafeText(fmt.Sprintf(string(TextAdminCancelAskReason),
+		bk.RoomName,
+		bk.Range.Start.Format("02.01.2006"),
+		bk.Range.Start.Format("15:04"),
+		bk.Range.End.Format("15:04"),
+		bk.UserName,
+	))
+}
+
+func BuildAdminCancelNoticeStr(bk domain.Booking, reason string) SafeText {
+	return SafeText(fmt.Sprintf(string(TextAdminCancelNotice),
+		bk.RoomName,
+		bk.Range.Start.Format("02.01.2006"),
+		bk.Range.Start.Format("15:04"),
+		bk.Range.End.Format("15:04"),
+		StripMarkup(reason),
+	))
+}
+
 func BuildRoomDeleteConfirmationSrt(name string) SafeText {
 	return SafeText(fmt.Sprintf(string(TextRoomDeleteConfirmation), name))
 }
@@ -175,7 +694,7 @@ func BuildWeekBookingStr(bks []domain.Booking) SafeText {
 		if i == 0 {
 			b.WriteString(fmt.Sprintf("*%s*\n", bk.RoomName))
 		}
-		b.WriteString(fmt.Sprintf(string(TextWeekScheduleBooking)+"\n",
+		b.WriteString(fmt.Sprintf(string(TextWeekScheduleBooking),
 			bk.Range.Start.Format("02.01"),
 			bk.Range.Start.Format("15"),
 			bk.Range.Start.Format("04"),
@@ -183,6 +702,7 @@ func BuildWeekBookingStr(bks []domain.Booking) SafeText {
 			bk.Range.End.Format("04"),
 			bk.UserName,
 		))
+		b.WriteString(scheduleDetails(bk) + "\n")
 	}
 	return SafeText(b.String())
 }
@@ -193,21 +713,49 @@ func BuildTodayBookingStr(bks []domain.Booking) SafeText {
 		if i == 0 {
 			b.WriteString(fmt.Sprintf("*%s*\n", bk.RoomName))
 		}
-		b.WriteString(fmt.Sprintf(string(TextTodayScheduleBooking)+"\n",
+		b.WriteString(fmt.Sprintf(string(TextTodayScheduleBooking),
 			bk.Range.Start.Format("15"),
 			bk.Range.Start.Format("04"),
 			bk.Range.End.Format("15"),
 			bk.Range.End.Format("04"),
 			bk.UserName,
 		))
+		b.WriteString(scheduleDetails(bk) + "\n")
 	}
 	return SafeText(b.String())
 }
 
-func BuildConfirmationStr(sess *BookingSession) SafeText {
-	// Разложим duration
-	hours := int(sess.Duration.Hours())
-	minutes := int(sess.Duration.Minutes()) % 60
+// Цель и участники брони для строки расписания.
+func scheduleDetails(bk domain.Booking) string {
+	var str string
+	if bk.Note != "" {
+		str += fmt.Sprintf(TextScheduleNote, StripMarkup(bk.Note))
+	}
+	if len(bk.Attendees) > 0 {
+		str += fmt.Sprintf(TextScheduleAttendees, FormatAttendees(bk.Attendees))
+	}
+	return str
+}
+
+// FormatAttendees: «@ivanov, Петров П.П.»
+func FormatAttendees(list []domain.Attendee) string {
+	names := make([]string, 0, len(list))
+	for _, a := range list {
+		names = append(names, StripMarkup(a.Name))
+	}
+	return strings.Join(names, ", ")
+}
+
+// StripMarkup убирает из пользовательского текста * и ` — EscapeMarkdownV2
+// их не экранирует, и они ломали бы разметку сообщения.
+func StripMarkup(s string) string {
+	return strings.NewReplacer("*", "", "`", "").Replace(s)
+}
+
+// FormatDuration: 1ч 30мин
+func FormatDuration(d time.Duration) string {
+	hours := int(d.Hours())
+	minutes := int(d.Minutes()) % 60
 
 	var durationStr string
 	if hours > 0 {
@@ -219,24 +767,215 @@ func BuildConfirmationStr(sess *BookingSession) SafeText {
 		}
 		durationStr += fmt.Sprintf("%dмин", minutes)
 	}
+	return durationStr
+}
 
-	return SafeText(fmt.Sprintf(
+func BuildConfirmationStr(sess *BookingSession) SafeText {
+	str := fmt.Sprintf(
 		TextBookAskConfirmation.String(),
 		sess.RoomName,
 		sess.Date.Format("02.01.2006"),
 		sess.StartTime.Format("15:04"),
-		durationStr,
-	))
+		FormatDuration(sess.Duration),
+	)
+	if !sess.Recurrence.IsZero() {
+		str += fmt.Sprintf(TextBookRecurrenceLine, FormatRecurrence(sess.Recurrence))
+	}
+	if sess.Note != "" {
+		str += fmt.Sprintf(TextBookPurposeLine, StripMarkup(sess.Note))
+	}
+	if len(sess.Attendees) > 0 {
+		str += fmt.Sprintf(TextBookAttendeesLine, FormatAttendees(sess.Attendees))
+	}
+	if sess.RescheduleID != 0 {
+		str = string(TextBookRescheduleHeader) + str
+	}
+	return SafeText(str)
 }
 
-func BuildMyOperationStr(bk domain.Booking) SafeText {
-	return SafeText(fmt.Sprintf(
+func BuildMyOperationStr(bk domain.Booking, series *domain.BookingSeries) SafeText {
+	str := fmt.Sprintf(
 		TextMyOperations.String(),
 		bk.RoomName,
 		bk.Range.Start.Format("02.01.2006"),
 		bk.Range.Start.Format("15:04"),
 		bk.Range.End.Sub(bk.Range.Start).String(),
+	)
+	if series != nil {
+		str += fmt.Sprintf(TextMySeriesLine, FormatRecurrence(series.Rule))
+	}
+	if bk.Note != "" {
+		str += fmt.Sprintf(TextBookPurposeLine, StripMarkup(bk.Note))
+	}
+	if len(bk.Attendees) > 0 {
+		str += fmt.Sprintf(TextBookAttendeesLine, FormatAttendees(bk.Attendees))
+	}
+	return SafeText(str)
+}
+
+// FormatRecurrence — человекочитаемое правило: «еженедельно, 8 раз».
+func FormatRecurrence(rule domain.RecurrenceRule) string {
+	var freq string
+	switch rule.Freq {
+	case domain.RecurDaily:
+		freq = "ежедневно"
+	case domain.RecurWeekdays:
+		freq = "по будням"
+	case domain.RecurWeekly:
+		freq = "еженедельно"
+	case domain.RecurBiweekly:
+		freq = "раз в 2 недели"
+	default:
+		return "не повторять"
+	}
+
+	if rule.Count > 0 {
+		return fmt.Sprintf("%s, %d раз", freq, rule.Count)
+	}
+	return fmt.Sprintf("%s, до %s", freq, rule.Until.Format("02.01.2006"))
+}
+
+func BuildFreeRoomsStr(rooms []domain.Room, start time.Time, dur time.Duration) SafeText {
+	var b strings.Builder
+	if len(rooms) == 0 {
+		b.WriteString(string(TextFreeNoRooms))
+		return SafeText(b.String())
+	}
+	b.WriteString(fmt.Sprintf(string(TextFreeRoomsNow),
+		start.Format("02.01"),
+		start.Format("15:04"),
+		start.Add(dur).Format("15:04"),
 	))
+	for _, room := range rooms {
+		b.WriteString(fmt.Sprintf("⁃%s\n", room.Name))
+	}
+	return SafeText(b.String())
+}
+
+// Строка свободных промежутков одной переговорки: «Room: 10:00-12:00, 14:00-21:00».
+func BuildRoomFreeSlotsStr(room domain.Room, free []domain.TimeRange) SafeText {
+	if len(free) == 0 {
+		return SafeText(fmt.Sprintf(string(TextFreeRoomSlots), room.Name, TextFreeRoomBusy))
+	}
+	parts := make([]string, 0, len(free))
+	for _, tr := range free {
+		parts = append(parts, fmt.Sprintf("%s-%s", tr.Start.Format("15:04"), tr.End.Format("15:04")))
+	}
+	return SafeText(fmt.Sprintf(string(TextFreeRoomSlots), room.Name, strings.Join(parts, ", ")))
+}
+
+// Приглашение участнику. Для серии добавляется строка с правилом повтора.
+func BuildAttendeeInvitedStr(organizer, room string, start, end time.Time, note string, rule domain.RecurrenceRule) SafeText {
+	str := fmt.Sprintf(string(TextAttendeeInvited),
+		StripMarkup(organizer),
+		room,
+		start.Format("02.01.2006"),
+		start.Format("15:04"),
+		end.Format("15:04"),
+	)
+	if !rule.IsZero() {
+		str += fmt.Sprintf(TextBookRecurrenceLine, FormatRecurrence(rule))
+	}
+	if note != "" {
+		str += fmt.Sprintf(TextBookPurposeLine, StripMarkup(note))
+	}
+	return SafeText(str)
+}
+
+// Уведомление участнику об отмене. series > 0 — отменено столько повторений серии.
+func BuildAttendeeCancelledStr(bk domain.Booking, series int) SafeText {
+	str := fmt.Sprintf(string(TextAttendeeCancelled),
+		bk.RoomName,
+		bk.Range.Start.Format("02.01.2006"),
+		bk.Range.Start.Format("15:04"),
+		bk.Range.End.Format("15:04"),
+		StripMarkup(bk.UserName),
+	)
+	if bk.Note != "" {
+		str += fmt.Sprintf(TextBookPurposeLine, StripMarkup(bk.Note))
+	}
+	if series > 0 {
+		str += fmt.Sprintf(TextAttendeeSeriesCancelled, series)
+	}
+	return SafeText(str)
+}
+
+func BuildSeriesCreatedStr(created int, conflicts, rejected []domain.TimeRange) SafeText {
+	var b strings.Builder
+	b.WriteString(fmt.Sprintf(string(TextBookSeriesCreated), created))
+	writeRanges := func(header SafeText, list []domain.TimeRange) {
+		if len(list) == 0 {
+			return
+		}
+		b.WriteString(string(header))
+		for _, tr := range list {
+			b.WriteString(fmt.Sprintf("⁃%s %s-%s\n",
+				tr.Start.Format("02.01"),
+				tr.Start.Format("15:04"),
+				tr.End.Format("15:04"),
+			))
+		}
+	}
+	writeRanges(TextBookSeriesConflicts, conflicts)
+	writeRanges(TextBookSeriesRejected, rejected)
+	return SafeText(b.String())
+}
+
+// IsPolicyViolation сообщает, что ошибка — нарушение политики бронирования.
+func IsPolicyViolation(err error) bool {
+	for _, target := range []error{
+		domain.ErrPastTimeNotAllowed,
+		domain.ErrOutsideWorkingHours,
+		domain.ErrDurationTooShort,
+		domain.ErrDurationTooLong,
+		domain.ErrTimeStepViolation,
+		domain.ErrTooFarInAdvance,
+		domain.ErrTooManyActiveBookings,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildPolicyViolationStr объясняет пользователю, какое правило нарушено.
+func BuildPolicyViolationStr(err error, p domain.BookingPolicy) SafeText {
+	switch {
+	case errors.Is(err, domain.ErrOutsideWorkingHours):
+		return SafeText(fmt.Sprintf(string(TextBookOutsideHours), FormatWeekHours(p)))
+	case errors.Is(err, domain.ErrDurationTooShort):
+		return SafeText(fmt.Sprintf(string(TextBookTooShort), FormatDuration(p.MinDuration)))
+	case errors.Is(err, domain.ErrDurationTooLong):
+		return SafeText(fmt.Sprintf(string(TextBookTooLong), FormatDuration(p.MaxDuration)))
+	case errors.Is(err, domain.ErrTimeStepViolation):
+		return SafeText(fmt.Sprintf(string(TextBookStepViolation), FormatDuration(p.Step)))
+	case errors.Is(err, domain.ErrTooFarInAdvance):
+		return SafeText(fmt.Sprintf(string(TextBookTooFarAhead), p.MaxDaysAhead))
+	case errors.Is(err, domain.ErrTooManyActiveBookings):
+		return SafeText(fmt.Sprintf(string(TextBookTooManyActive), p.MaxActivePerUser))
+	default:
+		return TextBookTooLateWaring
+	}
+}
+
+// FormatWeekHours описывает рабочие часы по дням недели: «пн 08:00-21:00, ..., вс нерабочий день».
+func FormatWeekHours(p domain.BookingPolicy) string {
+	names := []string{"вс", "пн", "вт", "ср", "чт", "пт", "сб"}
+	parts := make([]string, 0, 7)
+	for i := 1; i <= 7; i++ {
+		wd := time.Weekday(i % 7)
+		wh, ok := p.WeekdayHours[wd]
+		switch {
+		case p.ClosedDays[wd]:
+			parts = append(parts, names[wd]+" "+string(TextBookClosedDay))
+			continue
+		case !ok:
+			wh = p.WorkingHours
+		}
+		parts = append(parts, names[wd]+" "+wh.String())
+	}
+	return strings.Join(parts, ", ")
 }
 
 func BuildLogConfirmationStr(sess *LogsSession) SafeText {
@@ -246,66 +985,55 @@ func BuildLogConfirmationStr(sess *LogsSession) SafeText {
 	} else {
 		textType = "Запрос"
 	}
-	return SafeText(fmt.Sprintf(
-		TextLogConfirm.String(),
+	str := fmt.Sprintf(
+		string(TextLogConfirm),
 		textType,
 		sess.Date.Format("02.01.2006"),
 		sess.UserName,
 		sess.Doveritel,
 		sess.Comment,
-	))
-}
-
-func BuildLogConfirmedStr(textType string, num int64) SafeText {
-	whatafak := ""
-	if textType == "sogl" {
-		whatafak = "ЭС"
-	} else {
-		whatafak = "ЭЗ"
+	)
+	if len(sess.Files) > 0 {
+		str += fmt.Sprintf(TextLogConfirmFiles, len(sess.Files))
 	}
-	return SafeText(fmt.Sprintf(
-		TextLogYes,
-		whatafak,
-		num,
-	))
+	return SafeText(str + TextLogConfirmFilesHint)
 }
 
-func BuildLogSoglListStr(logs []domain.Soglashenie, tz *time.Location) SafeText {
-	if len(logs) == 0 {
-		return SafeText("📄 Соглашений не найдено.")
-	}
-
-	var b strings.Builder
-	b.WriteString("*📑 Список соглашений:*\n\n")
-
-	for i, log := range logs {
-		b.WriteString(fmt.Sprintf("%d. *%s*\n", i+1, log.UserName))
-		b.WriteString(fmt.Sprintf("🆔 ID: `ЭС%d`\n", log.ID))
-		b.WriteString(fmt.Sprintf("📅 Дата: %s\n", log.Date.Format("02.01.2006")))
-		b.WriteString(fmt.Sprintf("👤 Доверитель: %s\n", log.Doveritel))
-		b.WriteString(fmt.Sprintf("💬 Комментарий: %s\n", log.Comment))
-		b.WriteString(fmt.Sprintf("⏰ Создано: %s\n", log.CreatedAt.In(tz).Format("02.01.2006 15:04")))
-		b.WriteString("\n")
+// saved из total — сколько присланных до подтверждения файлов удалось приложить.
+func BuildLogConfirmedStr(number string, saved, total int) SafeText {
+	str := fmt.Sprintf(TextLogYes, number)
+	if total > 0 {
+		str += fmt.Sprintf(TextLogYesFiles, saved, total)
 	}
-
-	return SafeText(b.String())
+	return SafeText(str)
 }
 
-func BuildLogZaprosiListStr(logs []domain.Zapros, tz *time.Location) SafeText {
-	if len(logs) == 0 {
+// Страница записей адвоката одного типа; нумерация сквозная по страницам.
+func BuildLogListStr(kind domain.LogKind, recs []domain.LogRecord, total, offset int, tz *time.Location) SafeText {
+	if total == 0 {
+		if kind == domain.LogKindSogl {
+			return SafeText("📄 Соглашений не найдено.")
+		}
 		return SafeText("📄 Запросов не найдено.")
 	}
 
 	var b strings.Builder
-	b.WriteString("*📊 Список запросов:*\n\n")
-
-	for i, log := range logs {
-		b.WriteString(fmt.Sprintf("%d. *%s*\n", i+1, log.UserName))
-		b.WriteString(fmt.Sprintf("🆔 ID: `ЭЗ%d`\n", log.ID))
-		b.WriteString(fmt.Sprintf("📅 Дата: %s\n", log.Date.Format("02.01.2006")))
-		b.WriteString(fmt.Sprintf("👤 Доверитель: %s\n", log.Doveritel))
-		b.WriteString(fmt.Sprintf("💬 Комментарий: %s\n", log.Comment))
-		b.WriteString(fmt.Sprintf("⏰ Создано: %s\n", log.CreatedAt.In(tz).Format("02.01.2006 15:04")))
+	if kind == domain.LogKindSogl {
+		b.WriteString(fmt.Sprintf("*📑 Список соглашений (%d):*\n\n", total))
+	} else {
+		b.WriteString(fmt.Sprintf("*📊 Список запросов (%d):*\n\n", total))
+	}
+
+	for i, rec := range recs {
+		b.WriteString(fmt.Sprintf("%d. *%s*\n", offset+i+1, rec.UserName))
+		b.WriteString(fmt.Sprintf("🆔 ID: `%s`\n", rec.Number()))
+		b.WriteString(fmt.Sprintf("📅 Дата: %s\n", rec.Date.Format("02.01.2006")))
+		b.WriteString(fmt.Sprintf("👤 Доверитель: %s\n", rec.Doveritel))
+		b.WriteString(fmt.Sprintf("💬 Комментарий: %s\n", rec.Comment))
+		b.WriteString(fmt.Sprintf("⏰ Создано: %s\n", rec.CreatedAt.In(tz).Format("02.01.2006 15:04")))
+		if !rec.VoidedAt.IsZero() {
+			b.WriteString("🚫 Аннулирована\n")
+		}
 		b.WriteString("\n")
 	}
 
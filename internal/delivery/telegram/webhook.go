@@ -0,0 +1,78 @@
+package telegram
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/leegeev/KomaevBookingBot/pkg/logger"
+)
+
+// Заголовок, в котором Telegram присылает secret_token из setWebhook.
+const WebhookSecretHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// Апдейт от Telegram — небольшой JSON, больше не принимаем.
+const maxWebhookBody = 1 << 20
+
+// Webhook принимает апдейты от Telegram по HTTP и отдаёт их в Serve.
+// Реализует http.Handler и UpdateSource.
+type Webhook struct {
+	secret    string
+	log       logger.Logger
+	updates   chan tgbotapi.Update
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewWebhook — приёмник апдейтов. Запросы без заголовка с secret
+// отклоняются.
+func NewWebhook(secret string, log logger.Logger) *Webhook {
+	return &Webhook{
+		secret:  secret,
+		log:     log,
+		updates: make(chan tgbotapi.Update),
+		done:    make(chan struct{}),
+	}
+}
+
+func (w *Webhook) Updates() <-chan tgbotapi.Update {
+	return w.updates
+}
+
+// Stop — новые апдейты получают 503, и Telegram пришлёт их повторно.
+func (w *Webhook) Stop() {
+	w.closeOnce.Do(func() { close(w.done) })
+}
+
+func (w *Webhook) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		rw.Header().Set("Allow", http.MethodPost)
+		http.Error(rw, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	got := r.Header.Get(WebhookSecretHeader)
+	if subtle.ConstantTimeCompare([]byte(got), []byte(w.secret)) != 1 {
+		w.log.Warn("Webhook request with wrong secret token", "remote", r.RemoteAddr)
+		http.Error(rw, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	var upd tgbotapi.Update
+	if err := json.NewDecoder(http.MaxBytesReader(rw, r.Body, maxWebhookBody)).Decode(&upd); err != nil {
+		w.log.Warn("Failed to decode webhook update", "err", err, "remote", r.RemoteAddr)
+		http.Error(rw, "bad update", http.StatusBadRequest)
+		return
+	}
+
+	// Ответ 200 — подтверждение для Telegram, поэтому отвечаем только
+	// после того, как апдейт принят в обработку
+	select {
+	case w.updates <- upd:
+		rw.WriteHeader(http.StatusOK)
+	case <-w.done:
+		http.Error(rw, "shutting down", http.StatusServiceUnavailable)
+	case <-r.Context().Done():
+	}
+}
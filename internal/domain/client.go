@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+type ClientID int64
+
+// Доверитель из реестра клиентов. Записи журналов хранят текст доверителя
+// как его ввели и ссылку на клиента, найденного по NormName.
+type Client struct {
+	ID        ClientID
+	Name      string // как ввели впервые
+	NormName  string
+	CreatedAt time.Time
+	Records   int // число записей журналов со ссылкой на клиента
+}
+
+// Сколько похожих клиентов предлагать при вводе доверителя.
+const MaxClientSuggestions = 5
+
+// Организационно-правовые формы не отличают клиентов друг от друга:
+// «ООО Ромашка», «Ромашка ООО» и «ромашка» — один клиент.
+var clientLegalForms = map[string]bool{
+	"ооо": true, "оао": true, "зао": true, "пао": true, "ао": true,
+	"ип": true, "нко": true, "ано": true, "кфх": true,
+	"llc": true, "ltd": true, "inc": true,
+}
+
+// NormalizeClientName — ключ поиска дублей: нижний регистр, ё → е, без
+// кавычек, знаков препинания и ОПФ, слова по алфавиту. Должен совпадать
+// с client_norm из scripts/012_clients.up.sql. Пустая строка — доверитель
+// не указан, в реестр он не попадает.
+func NormalizeClientName(name string) string {
+	name = strings.ReplaceAll(strings.ToLower(name), "ё", "е")
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'а' && r <= 'я')
+	})
+
+	out := make([]string, 0, len(words))
+	for _, w := range words {
+		if !clientLegalForms[w] {
+			out = append(out, w)
+		}
+	}
+	sort.Strings(out)
+	return strings.Join(out, " ")
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "testing"
+
+func TestNormalizeClientName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Ромашка", "ромашка"},
+		{`ООО "Ромашка"`, "ромашка"},
+		{"Ромашка, ООО", "ромашка"},
+		{"«Ромашка» ооо", "ромашка"},
+		{"Иванов Иван Иванович", "иван иванов иванович"},
+		{"Иванович Иван Иванов", "иван иванов иванович"},
+		{"Ёлкин Пётр", "елкин петр"},
+		{"ИП Сидоров", "сидоров"},
+		{"Horns & Hooves LLC", "hooves horns"},
+		{"Альфа-2000", "2000 альфа"},
+		{"ООО", ""},
+		{"  ", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := NormalizeClientName(tt.in); got != tt.want {
+				t.Fatalf("NormalizeClientName(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -8,6 +8,7 @@ type (
 	BookingID int64
 	ZaprosID  int64
 	SoglID    int64
+	SeriesID  int64
 )
 
 // Сущность комнаты для бронирования.
@@ -23,15 +24,108 @@ type TimeRange struct {
 	End   time.Time // UTC, > Start
 }
 
+// Роль пользователя в групповом чате.
+type Role string
+
+const (
+	RoleMember Role = "member"
+	RoleAdmin  Role = "admin" // администратор или создатель чата
+)
+
+// Действующее лицо операции: кто её выполняет и с какой ролью.
+type Actor struct {
+	UserID UserID
+	Role   Role
+}
+
+func (a Actor) IsAdmin() bool { return a.Role == RoleAdmin }
+
+// CanManage: изменять бронь может её владелец или администратор чата.
+func (a Actor) CanManage(owner UserID) bool {
+	return a.UserID == owner || a.IsAdmin()
+}
+
 // Сущность бронирования комнаты.
 type Booking struct {
-	ID       BookingID
+	ID        BookingID
+	RoomID    RoomID
+	RoomName  string // денормализуем для истории
+	UserID    UserID
+	UserName  string
+	Range     TimeRange // [start, end) UTC
+	Note      string    // цель встречи, необязательно
+	Attendees []Attendee
+	SeriesID  SeriesID // 0, если бронь не входит в серию
+
+	CheckedInAt time.Time // zero — отметки о приходе не было
+}
+
+// Бронь, остаток которой освобождён из-за отсутствия check-in.
+// Range уже обрезан до момента освобождения.
+type ReleasedBooking struct {
+	Booking
+	OriginalEnd time.Time
+}
+
+// Участник встречи: пользователь Telegram (UserID != 0) или просто имя.
+type Attendee struct {
+	UserID UserID
+	Name   string
+}
+
+// Персональные настройки пользователя.
+type UserSettings struct {
+	UserID       UserID
+	RemindBefore time.Duration // 0 — напоминания выключены
+}
+
+// Действие, записываемое в журнал аудита.
+type AuditAction string
+
+const (
+	AuditAdminCancelBooking AuditAction = "admin_cancel_booking"
+)
+
+// Запись журнала аудита административных действий.
+type AuditEntry struct {
+	ID        int64
+	ActorID   UserID
+	ActorName string
+	Action    AuditAction
+	BookingID BookingID
+	OwnerID   UserID // владелец затронутой брони
+	Reason    string
+	Details   string // снимок брони на момент действия
+	CreatedAt time.Time
+}
+
+// Частота повторения серии броней.
+type RecurrenceFreq string
+
+const (
+	RecurNone     RecurrenceFreq = ""
+	RecurDaily    RecurrenceFreq = "daily"
+	RecurWeekdays RecurrenceFreq = "weekdays" // пн-пт
+	RecurWeekly   RecurrenceFreq = "weekly"
+	RecurBiweekly RecurrenceFreq = "biweekly"
+)
+
+// Правило повторения: частота + условие окончания (Count ИЛИ Until).
+type RecurrenceRule struct {
+	Freq  RecurrenceFreq
+	Count int       // число повторений, включая первое
+	Until time.Time // последняя дата серии включительно (локаль офиса)
+}
+
+// Серия повторяющихся броней. Каждое повторение хранится отдельной бронью
+// со ссылкой на серию, чтобы работали ограничения на пересечения.
+type BookingSeries struct {
+	ID       SeriesID
 	RoomID   RoomID
-	RoomName string // денормализуем для истории
+	RoomName string
 	UserID   UserID
 	UserName string
-	Range    TimeRange // [start, end) UTC
-	Note     string
+	Rule     RecurrenceRule
 }
 
 type Soglashenie struct {
@@ -42,6 +136,12 @@ type Soglashenie struct {
 	Comment   string
 	Date      time.Time
 	CreatedAt time.Time
+	RegNumber string   // ЭС-2026/0001, см. LogNumberFormat
+	ClientID  ClientID // 0 — доверитель не указан
+
+	UpdatedAt  time.Time // zero — запись не правили
+	VoidedAt   time.Time // zero — запись действующая
+	VoidReason string
 }
 
 type Zapros struct {
@@ -52,10 +152,20 @@ type Zapros struct {
 	Comment   string
 	Date      time.Time
 	CreatedAt time.Time
+	RegNumber string   // ЭС-2026/0001, см. LogNumberFormat
+	ClientID  ClientID // 0 — доверитель не указан
+
+	UpdatedAt  time.Time // zero — запись не правили
+	VoidedAt   time.Time // zero — запись действующая
+	VoidReason string
 }
 
 type User struct {
 	ID        int64
 	FIO       string
+	Active    bool // отключённый адвокат не создаёт новых записей
 	CreatedAt time.Time
 }
+
+// Максимальная длина ФИО адвоката.
+const MaxFIOLen = 200
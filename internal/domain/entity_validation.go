@@ -17,6 +17,25 @@ func NewBooking(roomID RoomID, roomName string, createdBy UserID, UserName strin
 	}, nil
 }
 
+const (
+	MaxNoteLen         = 200
+	MaxAttendees       = 20
+	MaxAttendeeNameLen = 64
+)
+
+// ValidateDetails проверяет цель встречи и список участников.
+func (b Booking) ValidateDetails() error {
+	if len([]rune(b.Note)) > MaxNoteLen || len(b.Attendees) > MaxAttendees {
+		return ErrInvalidInputData
+	}
+	for _, a := range b.Attendees {
+		if a.Name == "" || len([]rune(a.Name)) > MaxAttendeeNameLen {
+			return ErrInvalidInputData
+		}
+	}
+	return nil
+}
+
 // В домене ВСЕ времена — в UTC. Конвертация в локальную TZ — на краях (UI/infra).
 func MustUTC(t time.Time) time.Time {
 	if t.Location() != time.UTC {
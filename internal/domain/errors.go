@@ -18,12 +18,33 @@ var (
 	ErrDurationTooLong       = errors.New("booking duration is too long")
 	ErrOutsideWorkingHours   = errors.New("booking outside working hours")
 	ErrTimeStepViolation     = errors.New("booking does not match required time step")
+	ErrTooFarInAdvance       = errors.New("booking is too far in advance")
+	ErrTooManyActiveBookings = errors.New("too many active bookings")
 	ErrOverlapsExisting      = errors.New("booking overlaps existing booking")
-	ErrForbiddenCancellation = errors.New("user cannot cancel this booking")
+	// Отмена, перенос и check-in чужой брони (см. Actor.CanManage)
+	ErrForbiddenCancellation = errors.New("user cannot change this booking")
 	ErrDBConnectionFailed    = errors.New("failed to connect to database")
 	ErrInvalidInputData      = errors.New("invalid input data")
 	ErrNotOwner              = errors.New("user does not own this booking")
+	ErrBookingEnded          = errors.New("booking has already ended")
+
+	ErrInvalidWorkingHours = errors.New("invalid working hours")
+
+	// series errors
+	ErrInvalidRecurrence = errors.New("invalid recurrence rule")
+	ErrSeriesNotFound    = errors.New("booking series not found")
+	// Серия дала бы больше MaxSeriesOccurrences повторений
+	ErrSeriesTooLong = errors.New("series has too many occurrences")
 
 	ErrRecordNotFound = errors.New("record not found")
-	ErrUserNotFound   = errors.New("user not found")
+	ErrRecordVoided   = errors.New("record is voided")
+	ErrEditWindowOver = errors.New("record edit window is over")
+
+	ErrAttachmentExists = errors.New("attachment already exists")
+	ErrAttachmentLimit  = errors.New("too many attachments")
+	ErrUserNotFound     = errors.New("user not found")
+	ErrUserExists       = errors.New("user already exists")
+	ErrUserInactive     = errors.New("user is deactivated")
+
+	ErrSessionNotFound = errors.New("session not found")
 )
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+type AttachmentType string
+
+const (
+	AttachmentDocument AttachmentType = "document"
+	AttachmentPhoto    AttachmentType = "photo"
+)
+
+// Скан документа, приложенный к записи журнала. Файл хранится в Telegram,
+// по FileID бот отправляет его повторно.
+type LogAttachment struct {
+	ID           int64
+	Kind         LogKind
+	RecordID     int64
+	Type         AttachmentType
+	FileID       string
+	FileUniqueID string // одинаков для одного файла, даже пересланного заново
+	FileName     string // у фото пусто
+	MimeType     string
+	FileSize     int64
+	UploadedBy   UserID
+	CreatedAt    time.Time
+}
+
+// Сколько файлов можно приложить к одной записи.
+const MaxLogAttachments = 10
+
+// CanAttach — может ли actor прикладывать файлы к записи: автор или
+// администратор, к действующей записи. Окно правки не действует:
+// подписанный документ часто появляется позже самой записи.
+func (r LogRecord) CanAttach(actor Actor) error {
+	if r.Voided() {
+		return ErrRecordVoided
+	}
+	if !actor.CanManage(r.UserID) {
+		return ErrNotOwner
+	}
+	return nil
+}
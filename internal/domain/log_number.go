@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Формат регистрационного номера записи журнала. В шаблоне подставляются:
+//
+//	{prefix} — ЭС / ЭЗ
+//	{year}   — год, 2026
+//	{yy}     — две последние цифры года, 26
+//	{seq}    — порядковый номер в году, дополненный нулями до Width
+//
+// Номер вычисляется один раз при создании записи и хранится вместе с ней,
+// поэтому смена формата не меняет уже выданные номера.
+type LogNumberFormat struct {
+	Pattern string
+	Width   int
+}
+
+const (
+	DefaultLogNumberPattern = "{prefix}-{year}/{seq}"
+	DefaultLogNumberWidth   = 4
+)
+
+// NewLogNumberFormat проверяет шаблон: в нём должны быть префикс, год и
+// порядковый номер, иначе номер нельзя разобрать обратно. Пустые значения
+// заменяются значениями по умолчанию.
+func NewLogNumberFormat(pattern string, width int) (LogNumberFormat, error) {
+	if pattern == "" {
+		pattern = DefaultLogNumberPattern
+	}
+	if width <= 0 {
+		width = DefaultLogNumberWidth
+	}
+	if strings.Count(pattern, "{prefix}") != 1 || strings.Count(pattern, "{seq}") != 1 ||
+		strings.Count(pattern, "{year}")+strings.Count(pattern, "{yy}") != 1 {
+		return LogNumberFormat{}, fmt.Errorf("log number pattern %q must contain {prefix}, {seq} and one of {year}/{yy}", pattern)
+	}
+	return LogNumberFormat{Pattern: pattern, Width: width}, nil
+}
+
+// Format — номер записи kind с порядковым номером seq в году year.
+func (f LogNumberFormat) Format(kind LogKind, year, seq int) string {
+	return strings.NewReplacer(
+		"{prefix}", kind.Prefix(),
+		"{year}", strconv.Itoa(year),
+		"{yy}", fmt.Sprintf("%02d", year%100),
+		"{seq}", fmt.Sprintf("%0*d", f.Width, seq),
+	).Replace(f.Pattern)
+}
+
+// RegYear — год нумерации записи: по дате записи, а не по дате внесения.
+func RegYear(date time.Time) int {
+	return date.Year()
+}
+
+// Parse разбирает номер, набранный пользователем: регистр, пробелы и
+// ведущие нули в порядковом номере не важны («эс-2026/1» == «ЭС-2026/0001»).
+func (f LogNumberFormat) Parse(s string) (LogKind, int, int, error) {
+	s = strings.ToUpper(strings.Join(strings.Fields(s), ""))
+
+	var (
+		expr   strings.Builder
+		groups []string
+	)
+	expr.WriteString("^")
+	rest := strings.Join(strings.Fields(f.Pattern), "")
+	for rest != "" {
+		i := strings.IndexByte(rest, '{')
+		j := strings.IndexByte(rest, '}')
+		if i < 0 || j < i {
+			expr.WriteString(regexp.QuoteMeta(strings.ToUpper(rest)))
+			break
+		}
+		expr.WriteString(regexp.QuoteMeta(strings.ToUpper(rest[:i])))
+		switch token := rest[i+1 : j]; token {
+		case "prefix":
+			expr.WriteString("(" + LogKindSogl.Prefix() + "|" + LogKindZapros.Prefix() + ")")
+		case "year":
+			expr.WriteString(`(\d{4})`)
+		case "yy":
+			expr.WriteString(`(\d{2})`)
+		case "seq":
+			expr.WriteString(`(\d+)`)
+		default:
+			expr.WriteString(regexp.QuoteMeta(strings.ToUpper(rest[i : j+1])))
+			rest = rest[j+1:]
+			continue
+		}
+		groups = append(groups, rest[i+1:j])
+		rest = rest[j+1:]
+	}
+	expr.WriteString("$")
+
+	re, err := regexp.Compile(expr.String())
+	if err != nil {
+		return "", 0, 0, ErrInvalidInputData
+	}
+	m := re.FindStringSubmatch(s)
+	if m == nil {
+		return "", 0, 0, ErrInvalidInputData
+	}
+
+	var (
+		kind      LogKind
+		year, seq int
+	)
+	for i, g := range groups {
+		v := m[i+1]
+		switch g {
+		case "prefix":
+			kind = LogKindZapros
+			if v == LogKindSogl.Prefix() {
+				kind = LogKindSogl
+			}
+		case "year":
+			year, _ = strconv.Atoi(v)
+		case "yy":
+			yy, _ := strconv.Atoi(v)
+			year = 2000 + yy
+		case "seq":
+			seq, _ = strconv.Atoi(v)
+		}
+	}
+	if seq <= 0 {
+		return "", 0, 0, ErrInvalidInputData
+	}
+	return kind, year, seq, nil
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewLogNumberFormat(t *testing.T) {
+	tests := []struct {
+		pattern string
+		width   int
+		want    LogNumberFormat
+		wantErr bool
+	}{
+		{pattern: "", width: 0, want: LogNumberFormat{Pattern: DefaultLogNumberPattern, Width: DefaultLogNumberWidth}},
+		{pattern: "{prefix}{yy}-{seq}", width: 3, want: LogNumberFormat{Pattern: "{prefix}{yy}-{seq}", Width: 3}},
+		{pattern: "{prefix}-{seq}", wantErr: true},
+		{pattern: "{year}/{seq}", wantErr: true},
+		{pattern: "{prefix}-{year}/{yy}/{seq}", wantErr: true},
+		{pattern: "{prefix}-{year}/{seq}-{seq}", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := NewLogNumberFormat(tt.pattern, tt.width)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("want error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewLogNumberFormat: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogNumberFormatRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		f    LogNumberFormat
+		kind LogKind
+		year int
+		seq  int
+		want string
+	}{
+		{"default", LogNumberFormat{Pattern: DefaultLogNumberPattern, Width: 4}, LogKindSogl, 2026, 12, "ЭС-2026/0012"},
+		{"short year", LogNumberFormat{Pattern: "{prefix}{yy}-{seq}", Width: 3}, LogKindZapros, 2026, 7, "ЭЗ26-007"},
+		{"seq wider than width", LogNumberFormat{Pattern: DefaultLogNumberPattern, Width: 2}, LogKindSogl, 2025, 1234, "ЭС-2025/1234"},
+		{"seq first", LogNumberFormat{Pattern: "№{seq} {prefix}/{year}", Width: 1}, LogKindZapros, 2024, 5, "№5 ЭЗ/2024"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.f.Format(tt.kind, tt.year, tt.seq)
+			if got != tt.want {
+				t.Fatalf("Format = %q, want %q", got, tt.want)
+			}
+			kind, year, seq, err := tt.f.Parse(got)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", got, err)
+			}
+			if kind != tt.kind || year != tt.year || seq != tt.seq {
+				t.Fatalf("Parse(%q) = %s %d %d, want %s %d %d", got, kind, year, seq, tt.kind, tt.year, tt.seq)
+			}
+		})
+	}
+}
+
+func TestLogNumberFormatParse(t *testing.T) {
+	f := LogNumberFormat{Pattern: DefaultLogNumberPattern, Width: 4}
+	tests := []struct {
+		in      string
+		kind    LogKind
+		year    int
+		seq     int
+		wantErr bool
+	}{
+		{in: "ЭС-2026/0012", kind: LogKindSogl, year: 2026, seq: 12},
+		{in: "эз-2026/7", kind: LogKindZapros, year: 2026, seq: 7},
+		{in: " ЭС - 2026 / 0001 ", kind: LogKindSogl, year: 2026, seq: 1},
+		{in: "ЭС-2026/0000", wantErr: true},
+		{in: "ЭС12", wantErr: true},
+		{in: "XX-2026/0001", wantErr: true},
+		{in: "ЭС-26/0001", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			kind, year, seq, err := f.Parse(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInputData) {
+					t.Fatalf("err = %v, want ErrInvalidInputData", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if kind != tt.kind || year != tt.year || seq != tt.seq {
+				t.Fatalf("got %s %d %d, want %s %d %d", kind, year, seq, tt.kind, tt.year, tt.seq)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Тип записи журнала. Значения совпадают с типом в сессии создания записи.
+type LogKind string
+
+const (
+	LogKindSogl   LogKind = "sogl"   // соглашение, номер ЭС-<год>/<n>
+	LogKindZapros LogKind = "zapros" // запрос, номер ЭЗ-<год>/<n>
+)
+
+// Prefix — префикс номера записи.
+func (k LogKind) Prefix() string {
+	if k == LogKindSogl {
+		return "ЭС"
+	}
+	return "ЭЗ"
+}
+
+// Запись журнала без привязки к типу — для карточки записи.
+type LogRecord struct {
+	Kind      LogKind
+	ID        int64
+	UserID    UserID
+	UserName  string // ФИО адвоката
+	Doveritel string
+	Comment   string
+	Date      time.Time
+	CreatedAt time.Time
+	RegNumber string
+	ClientID  ClientID
+
+	UpdatedAt  time.Time // zero — запись не правили
+	VoidedAt   time.Time // zero — запись действующая
+	VoidReason string
+
+	Hidden bool // содержимое скрыто: запись чужого адвоката в результатах поиска
+}
+
+// Ограничения на правку записей.
+const (
+	MaxLogTextLen    = 1000
+	MinVoidReasonLen = 3
+	MaxVoidReasonLen = 500
+)
+
+// Редактируемые поля записи.
+type LogField string
+
+const (
+	LogFieldDoveritel LogField = "doveritel"
+	LogFieldComment   LogField = "comment"
+)
+
+func (f LogField) Valid() bool {
+	return f == LogFieldDoveritel || f == LogFieldComment
+}
+
+func (r LogRecord) Voided() bool {
+	return !r.VoidedAt.IsZero()
+}
+
+// Value — текущее значение поля.
+func (r LogRecord) Value(f LogField) string {
+	if f == LogFieldDoveritel {
+		return r.Doveritel
+	}
+	return r.Comment
+}
+
+// CanEdit — можно ли actor править или аннулировать запись: администратор — всегда,
+// автор — в течение window после создания. Аннулированную запись не правит никто.
+func (r LogRecord) CanEdit(actor Actor, now time.Time, window time.Duration) error {
+	if r.Voided() {
+		return ErrRecordVoided
+	}
+	if actor.IsAdmin() {
+		return nil
+	}
+	if actor.UserID != r.UserID {
+		return ErrNotOwner
+	}
+	if now.Sub(r.CreatedAt) > window {
+		return ErrEditWindowOver
+	}
+	return nil
+}
+
+// Действие в истории изменений записи.
+type LogRevisionAction string
+
+const (
+	LogRevisionEdit LogRevisionAction = "edit"
+	LogRevisionVoid LogRevisionAction = "void"
+)
+
+// Строка неизменяемой истории изменений записи журнала.
+type LogRevision struct {
+	ID        int64
+	Kind      LogKind
+	RecordID  int64
+	ActorID   UserID
+	ActorName string
+	Action    LogRevisionAction
+	Field     LogField // пусто для аннулирования
+	OldValue  string
+	NewValue  string
+	Reason    string
+	CreatedAt time.Time
+}
+
+// Number — регистрационный номер записи. Для записи, у которой он не
+// загружен, — старый номер по id: ЭС<id> / ЭЗ<id>.
+func (r LogRecord) Number() string {
+	if r.RegNumber != "" {
+		return r.RegNumber
+	}
+	return fmt.Sprintf("%s%d", r.Kind.Prefix(), r.ID)
+}
+
+// Masked — запись без содержимого: номер, дата и автор. Так чужие записи
+// показываются в поиске, чтобы было видно, что с доверителем уже работали.
+func (r LogRecord) Masked() LogRecord {
+	return LogRecord{
+		Kind:      r.Kind,
+		ID:        r.ID,
+		UserID:    r.UserID,
+		UserName:  r.UserName,
+		Date:      r.Date,
+		RegNumber: r.RegNumber,
+		VoidedAt:  r.VoidedAt,
+		Hidden:    true,
+	}
+}
+
+func SoglashenieRecord(s Soglashenie) LogRecord {
+	return LogRecord{
+		Kind:      LogKindSogl,
+		ID:        int64(s.ID),
+		UserID:    s.UserID,
+		UserName:  s.UserName,
+		Doveritel: s.Doveritel,
+		Comment:   s.Comment,
+		Date:      s.Date,
+		CreatedAt: s.CreatedAt,
+		RegNumber: s.RegNumber,
+		ClientID:  s.ClientID,
+
+		UpdatedAt:  s.UpdatedAt,
+		VoidedAt:   s.VoidedAt,
+		VoidReason: s.VoidReason,
+	}
+}
+
+func ZaprosRecord(z Zapros) LogRecord {
+	return LogRecord{
+		Kind:      LogKindZapros,
+		ID:        int64(z.ID),
+		UserID:    z.UserID,
+		UserName:  z.UserName,
+		Doveritel: z.Doveritel,
+		Comment:   z.Comment,
+		Date:      z.Date,
+		CreatedAt: z.CreatedAt,
+		RegNumber: z.RegNumber,
+		ClientID:  z.ClientID,
+
+		UpdatedAt:  z.UpdatedAt,
+		VoidedAt:   z.VoidedAt,
+		VoidReason: z.VoidReason,
+	}
+}
+
+// Минимальная длина слова в поиске: триграммному индексу нужно хотя бы 3 символа.
+const MinSearchWordLen = 3
+
+// Фильтр поиска по журналам. Нулевые значения — без ограничения.
+type LogSearchFilter struct {
+	Query  string    // слова ищутся в доверителе и комментарии, все должны встретиться
+	Kind   LogKind   // "" — соглашения и запросы
+	From   time.Time // по дате записи, включительно
+	To     time.Time // по дате записи, включительно
+	UserID UserID    // автор записи
+	Limit  int
+	Offset int
+}
+
+// Words — слова запроса, по которым идёт поиск.
+func (f LogSearchFilter) Words() []string {
+	var out []string
+	for _, w := range strings.Fields(f.Query) {
+		if len([]rune(w)) >= MinSearchWordLen {
+			out = append(out, w)
+		}
+	}
+	return out
+}
+
+// ParseLogNumber разбирает старый номер записи по id: «ЭС123», «эз 45».
+// Такие номера выдавались до нумерации по годам. Латинские
+// двойники букв (например, набранные в английской раскладке) не принимаются.
+func ParseLogNumber(s string) (LogKind, int64, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+
+	var kind LogKind
+	switch {
+	case strings.HasPrefix(s, LogKindSogl.Prefix()):
+		kind = LogKindSogl
+	case strings.HasPrefix(s, LogKindZapros.Prefix()):
+		kind = LogKindZapros
+	default:
+		return "", 0, ErrInvalidInputData
+	}
+
+	digits := strings.TrimLeftFunc(strings.TrimPrefix(s, kind.Prefix()), unicode.IsSpace)
+	id, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil || id <= 0 {
+		return "", 0, ErrInvalidInputData
+	}
+	return kind, id, nil
+}
@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+// Параметры выгрузки журналов в Excel. Период — по дате записи, включительно.
+// Нулевые From, To, Kind и UserID — без фильтра.
+type ReportQuery struct {
+	From   time.Time
+	To     time.Time
+	Kind   LogKind
+	UserID UserID
+}
+
+// Includes — попадает ли тип записей в выгрузку.
+func (q ReportQuery) Includes(k LogKind) bool {
+	return q.Kind == "" || q.Kind == k
+}
+
+// Готовые периоды выгрузки.
+type ReportPeriod string
+
+const (
+	ReportPeriodMonth       ReportPeriod = "month"
+	ReportPeriodPrevMonth   ReportPeriod = "prev_month"
+	ReportPeriodQuarter     ReportPeriod = "quarter"
+	ReportPeriodPrevQuarter ReportPeriod = "prev_quarter"
+	ReportPeriodYear        ReportPeriod = "year"
+	ReportPeriodPrevYear    ReportPeriod = "prev_year"
+)
+
+// Range — первый и последний день периода относительно now (в его часовом поясе).
+func (p ReportPeriod) Range(now time.Time) (time.Time, time.Time, error) {
+	y, m, _ := now.Date()
+	loc := now.Location()
+	quarterStart := time.Month((int(m)-1)/3*3 + 1)
+
+	var from time.Time
+	var months int
+	switch p {
+	case ReportPeriodMonth:
+		from, months = time.Date(y, m, 1, 0, 0, 0, 0, loc), 1
+	case ReportPeriodPrevMonth:
+		from, months = time.Date(y, m-1, 1, 0, 0, 0, 0, loc), 1
+	case ReportPeriodQuarter:
+		from, months = time.Date(y, quarterStart, 1, 0, 0, 0, 0, loc), 3
+	case ReportPeriodPrevQuarter:
+		from, months = time.Date(y, quarterStart-3, 1, 0, 0, 0, 0, loc), 3
+	case ReportPeriodYear:
+		from, months = time.Date(y, 1, 1, 0, 0, 0, 0, loc), 12
+	case ReportPeriodPrevYear:
+		from, months = time.Date(y-1, 1, 1, 0, 0, 0, 0, loc), 12
+	default:
+		return time.Time{}, time.Time{}, ErrInvalidInputData
+	}
+	return from, from.AddDate(0, months, -1), nil
+}
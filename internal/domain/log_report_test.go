@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReportPeriodRange(t *testing.T) {
+	loc := mustLoc(t, "Europe/Moscow")
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, loc)
+	}
+	mid := time.Date(2026, 5, 15, 18, 30, 0, 0, loc)
+	january := time.Date(2026, 1, 31, 23, 59, 0, 0, loc)
+
+	tests := []struct {
+		name     string
+		period   ReportPeriod
+		now      time.Time
+		from, to time.Time
+	}{
+		{"month", ReportPeriodMonth, mid, day(2026, 5, 1), day(2026, 5, 31)},
+		{"prev month", ReportPeriodPrevMonth, mid, day(2026, 4, 1), day(2026, 4, 30)},
+		{"quarter", ReportPeriodQuarter, mid, day(2026, 4, 1), day(2026, 6, 30)},
+		{"prev quarter", ReportPeriodPrevQuarter, mid, day(2026, 1, 1), day(2026, 3, 31)},
+		{"year", ReportPeriodYear, mid, day(2026, 1, 1), day(2026, 12, 31)},
+		{"prev year", ReportPeriodPrevYear, mid, day(2025, 1, 1), day(2025, 12, 31)},
+		{"prev month in january", ReportPeriodPrevMonth, january, day(2025, 12, 1), day(2025, 12, 31)},
+		{"prev quarter in january", ReportPeriodPrevQuarter, january, day(2025, 10, 1), day(2025, 12, 31)},
+		{"month with 31th day", ReportPeriodMonth, january, day(2026, 1, 1), day(2026, 1, 31)},
+		{"february of leap year", ReportPeriodMonth, time.Date(2028, 2, 10, 12, 0, 0, 0, loc), day(2028, 2, 1), day(2028, 2, 29)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := tt.period.Range(tt.now)
+			if err != nil {
+				t.Fatalf("Range: %v", err)
+			}
+			if !from.Equal(tt.from) || !to.Equal(tt.to) {
+				t.Fatalf("got %s — %s, want %s — %s",
+					from.Format("02.01.2006"), to.Format("02.01.2006"),
+					tt.from.Format("02.01.2006"), tt.to.Format("02.01.2006"))
+			}
+		})
+	}
+}
+
+func TestReportPeriodRangeUnknown(t *testing.T) {
+	if _, _, err := ReportPeriod("week").Range(time.Now()); !errors.Is(err, ErrInvalidInputData) {
+		t.Fatalf("err = %v, want ErrInvalidInputData", err)
+	}
+}
@@ -0,0 +1,141 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Рабочие часы офиса — смещения от полуночи в TZ офиса, полуинтервал [From, To).
+type WorkingHours struct {
+	From time.Duration
+	To   time.Duration
+}
+
+// Политика бронирования. Загружается из config.yaml (секция booking).
+// Нулевые значения ограничений означают «без ограничения».
+type BookingPolicy struct {
+	WorkingHours     WorkingHours                  // рабочие часы по умолчанию
+	WeekdayHours     map[time.Weekday]WorkingHours // переопределения по дням недели
+	ClosedDays       map[time.Weekday]bool         // нерабочие дни
+	Step             time.Duration                 // сетка начала/длительности брони
+	MinDuration      time.Duration
+	MaxDuration      time.Duration
+	MaxDaysAhead     int // насколько дней вперёд можно бронировать
+	MaxActivePerUser int // сколько незакончившихся броней может быть у пользователя
+
+	// Сколько ждать check-in после начала брони, прежде чем освободить остаток.
+	// 0 — check-in не требуется.
+	CheckinGrace time.Duration
+}
+
+// HoursOn возвращает рабочее окно на дату day. false — день нерабочий.
+func (p BookingPolicy) HoursOn(day time.Time, loc *time.Location) (TimeRange, bool) {
+	wd := day.In(loc).Weekday()
+	if p.ClosedDays[wd] {
+		return TimeRange{}, false
+	}
+	if wh, ok := p.WeekdayHours[wd]; ok {
+		return wh.On(day, loc), true
+	}
+	return p.WorkingHours.On(day, loc), true
+}
+
+// Check проверяет интервал брони на соответствие политике (кроме лимита
+// активных броней — он требует обращения к репозиторию).
+func (p BookingPolicy) Check(tr TimeRange, now time.Time, loc *time.Location) error {
+	// Текущий, уже начавшийся слот сетки забронировать можно
+	if tr.Start.Before(now.Add(-p.Step)) || !tr.End.After(now) {
+		return ErrPastTimeNotAllowed
+	}
+
+	dur := tr.Duration()
+	if p.MinDuration > 0 && dur < p.MinDuration {
+		return ErrDurationTooShort
+	}
+	if p.MaxDuration > 0 && dur > p.MaxDuration {
+		return ErrDurationTooLong
+	}
+
+	if p.Step > 0 {
+		local := tr.Start.In(loc)
+		midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+		if local.Sub(midnight)%p.Step != 0 || dur%p.Step != 0 {
+			return ErrTimeStepViolation
+		}
+	}
+
+	window, open := p.HoursOn(tr.Start, loc)
+	if !open || tr.Start.Before(window.Start) || tr.End.After(window.End) {
+		return ErrOutsideWorkingHours
+	}
+
+	if p.MaxDaysAhead > 0 {
+		n := now.In(loc)
+		horizon := time.Date(n.Year(), n.Month(), n.Day()+p.MaxDaysAhead+1, 0, 0, 0, 0, loc)
+		if !tr.Start.Before(horizon) {
+			return ErrTooFarInAdvance
+		}
+	}
+	return nil
+}
+
+// ParseWorkingHours разбирает строку вида "09:00-21:00".
+func ParseWorkingHours(s string) (WorkingHours, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		return WorkingHours{}, fmt.Errorf("%w: %q", ErrInvalidWorkingHours, s)
+	}
+	f, err := parseClock(from)
+	if err != nil {
+		return WorkingHours{}, fmt.Errorf("%w: %q", ErrInvalidWorkingHours, s)
+	}
+	t, err := parseClock(to)
+	if err != nil {
+		return WorkingHours{}, fmt.Errorf("%w: %q", ErrInvalidWorkingHours, s)
+	}
+	if t <= f {
+		return WorkingHours{}, fmt.Errorf("%w: %q", ErrInvalidWorkingHours, s)
+	}
+	return WorkingHours{From: f, To: t}, nil
+}
+
+func parseClock(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+func (w WorkingHours) IsZero() bool { return w.From == 0 && w.To == 0 }
+
+// String: "09:00-21:00". Нулевые часы — круглые сутки.
+func (w WorkingHours) String() string {
+	to := w.To
+	if w.IsZero() {
+		to = 24 * time.Hour
+	}
+	clock := func(d time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(d/time.Hour), int(d%time.Hour/time.Minute))
+	}
+	return clock(w.From) + "-" + clock(to)
+}
+
+// On возвращает рабочее окно на дату day (берётся дата в TZ loc).
+func (w WorkingHours) On(day time.Time, loc *time.Location) TimeRange {
+	d := day.In(loc)
+	midnight := time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+	to := w.To
+	if w.IsZero() {
+		to = 24 * time.Hour
+	}
+	return TimeRange{
+		Start: MustUTC(midnight.Add(w.From)),
+		End:   MustUTC(midnight.Add(to)),
+	}
+}
@@ -0,0 +1,122 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBookingPolicyCheck(t *testing.T) {
+	loc := mustLoc(t, "Europe/Moscow")
+	at := func(d, h, min int) time.Time {
+		return time.Date(2026, 1, d, h, min, 0, 0, loc)
+	}
+	p := BookingPolicy{
+		WorkingHours: WorkingHours{From: 9 * time.Hour, To: 21 * time.Hour},
+		WeekdayHours: map[time.Weekday]WorkingHours{
+			time.Saturday: {From: 10 * time.Hour, To: 16 * time.Hour},
+		},
+		ClosedDays:   map[time.Weekday]bool{time.Sunday: true},
+		Step:         30 * time.Minute,
+		MinDuration:  30 * time.Minute,
+		MaxDuration:  4 * time.Hour,
+		MaxDaysAhead: 14,
+	}
+	// Среда, 14 января 2026, 12:10
+	now := at(14, 12, 10)
+
+	tests := []struct {
+		name       string
+		start, end time.Time
+		want       error
+	}{
+		{"aligned", at(14, 13, 0), at(14, 14, 0), nil},
+		{"current slot already started", at(14, 12, 0), at(14, 13, 0), nil},
+		{"ended slot", at(14, 11, 30), at(14, 12, 0), ErrPastTimeNotAllowed},
+		{"starts before current slot", at(14, 11, 30), at(14, 13, 0), ErrPastTimeNotAllowed},
+		{"start off the grid", at(14, 13, 15), at(14, 14, 15), ErrTimeStepViolation},
+		{"duration off the grid", at(14, 13, 0), at(14, 13, 45), ErrTimeStepViolation},
+		{"too short", at(14, 13, 0), at(14, 13, 20), ErrDurationTooShort},
+		{"too long", at(14, 13, 0), at(14, 17, 30), ErrDurationTooLong},
+		{"ends after hours", at(14, 20, 30), at(14, 21, 30), ErrOutsideWorkingHours},
+		{"starts before hours", at(15, 8, 30), at(15, 9, 30), ErrOutsideWorkingHours},
+		{"ends exactly at close", at(15, 20, 0), at(15, 21, 0), nil},
+		{"saturday override", at(17, 10, 0), at(17, 11, 0), nil},
+		{"saturday before override", at(17, 9, 0), at(17, 10, 0), ErrOutsideWorkingHours},
+		{"closed day", at(18, 12, 0), at(18, 13, 0), ErrOutsideWorkingHours},
+		{"last day of horizon", at(28, 12, 0), at(28, 13, 0), nil},
+		{"beyond horizon", at(29, 12, 0), at(29, 13, 0), ErrTooFarInAdvance},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := NewTimeRange(tt.start, tt.end)
+			if err != nil {
+				t.Fatalf("NewTimeRange: %v", err)
+			}
+			if err := p.Check(tr, now, loc); !errors.Is(err, tt.want) {
+				t.Fatalf("Check = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+// Сетка отсчитывается от полуночи в TZ офиса, поэтому в день перехода на
+// летнее время начало 10:00 по-прежнему на сетке.
+func TestBookingPolicyCheckStepOnDSTDay(t *testing.T) {
+	loc := mustLoc(t, "Europe/Berlin")
+	p := BookingPolicy{Step: 30 * time.Minute}
+	now := time.Date(2026, 3, 29, 8, 0, 0, 0, loc)
+
+	tests := []struct {
+		name  string
+		start time.Time
+		want  error
+	}{
+		{"on the grid", time.Date(2026, 3, 29, 10, 0, 0, 0, loc), nil},
+		{"half past", time.Date(2026, 3, 29, 10, 30, 0, 0, loc), nil},
+		{"off the grid", time.Date(2026, 3, 29, 10, 10, 0, 0, loc), ErrTimeStepViolation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr, err := NewTimeRange(tt.start, tt.start.Add(time.Hour))
+			if err != nil {
+				t.Fatalf("NewTimeRange: %v", err)
+			}
+			if err := p.Check(tr, now, loc); !errors.Is(err, tt.want) {
+				t.Fatalf("Check = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseWorkingHours(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    WorkingHours
+		wantErr bool
+	}{
+		{in: "09:00-21:00", want: WorkingHours{From: 9 * time.Hour, To: 21 * time.Hour}},
+		{in: " 08:30 - 24:00 ", want: WorkingHours{From: 8*time.Hour + 30*time.Minute, To: 24 * time.Hour}},
+		{in: "21:00-09:00", wantErr: true},
+		{in: "09:00", wantErr: true},
+		{in: "9-21", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseWorkingHours(tt.in)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidWorkingHours) {
+					t.Fatalf("err = %v, want ErrInvalidWorkingHours", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWorkingHours: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
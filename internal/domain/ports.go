@@ -22,7 +22,13 @@ type BookingRepository interface {
 	// CRUD операции.
 	Create(ctx context.Context, b Booking) error
 	Delete(ctx context.Context, id BookingID) error
+	// Удаляет бронь и пишет запись аудита в одной транзакции: без записи
+	// в аудите бронь не удаляется.
+	DeleteWithAudit(ctx context.Context, id BookingID, e AuditEntry) error
 	GetByID(ctx context.Context, id BookingID) (Booking, error)
+	// Атомарно меняет интервал (и переговорку) брони. При пересечении
+	// возвращает ErrOverlapsExisting, а бронь остаётся без изменений.
+	UpdateRange(ctx context.Context, id BookingID, roomID RoomID, roomName string, tr TimeRange) error
 
 	// Для отображения и проверок
 	ListByRoomAndInterval(ctx context.Context, roomID RoomID, fromUTC, toUTC time.Time) ([]Booking, error)
@@ -30,23 +36,142 @@ type BookingRepository interface {
 
 	AnyOverlap(ctx context.Context, roomID RoomID, tr TimeRange) (bool, error)
 
+	// Серии повторяющихся броней.
+	// Создаёт серию и её повторения в одной транзакции. Повторение,
+	// пересекающееся с существующей бронью, пропускается, его индекс
+	// возвращается в conflicts. Если пересекаются все, серия не создаётся
+	// и возвращается ErrOverlapsExisting.
+	CreateSeries(ctx context.Context, s BookingSeries, occurrences []Booking) (id SeriesID, conflicts []int, err error)
+	GetSeries(ctx context.Context, id SeriesID) (BookingSeries, error)
+	// Удаляет повторения серии, которые начинаются после fromUTC.
+	DeleteSeries(ctx context.Context, id SeriesID, fromUTC time.Time) (int64, error)
+
+	// Атомарно помечает и возвращает брони, о которых пора напомнить:
+	// начало в (now, now+lead], где lead — настройка владельца или defaultLead.
+	// Каждая бронь возвращается не более одного раза.
+	ClaimDueReminders(ctx context.Context, nowUTC time.Time, defaultLead time.Duration) ([]Booking, error)
+	// Снимает отметку, чтобы напоминание отправилось на следующем тике.
+	ReleaseReminder(ctx context.Context, id BookingID) error
+
+	// Отмечает приход на встречу. Возвращает ErrBookingNotFound, если бронь
+	// не найдена или уже закончилась.
+	CheckIn(ctx context.Context, id BookingID, atUTC time.Time) error
+	// Обрезает до nowUTC брони, начавшиеся не позже nowUTC-grace без check-in.
+	ReleaseNoShows(ctx context.Context, nowUTC time.Time, grace time.Duration) ([]ReleasedBooking, error)
+
 	// Санитарная очистка старых записей.
 	DeleteEndedBefore(ctx context.Context, cutoffUTC time.Time) (int64, error)
 }
 
+// Состояние бота: зарегистрированные группы и сообщения с расписанием.
+type BotStateRepository interface {
+	RegisterGroupChat(ctx context.Context, chatID int64, by UserID) error
+	// Группы в порядке регистрации, последняя — основная.
+	ListGroupChats(ctx context.Context) ([]int64, error)
+
+	// day — дата в TZ офиса (время отбрасывается).
+	SaveScheduleMessage(ctx context.Context, chatID int64, day time.Time, messageID int) error
+	// Возвращает ErrRecordNotFound, если на этот день сообщения нет.
+	GetScheduleMessage(ctx context.Context, chatID int64, day time.Time) (int, error)
+}
+
+// Незавершённые диалоги пользователей (бронирование, журналы).
+type SessionRepository interface {
+	// Возвращает и истёкшую сессию, если её ещё не удалили;
+	// ErrSessionNotFound, если сессии нет.
+	GetSession(ctx context.Context, kind SessionKind, userID int64) (Session, error)
+	SaveSession(ctx context.Context, s Session) error
+	DeleteSession(ctx context.Context, kind SessionKind, userID int64) error
+	// Удаляет сессии, истёкшие до before.
+	DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Актуальные @ники пользователей Telegram. Ник передаётся без @ в
+// нижнем регистре.
+type UsernameRepository interface {
+	// Запоминает ник пользователя; ник, ранее принадлежавший другому
+	// пользователю, и прежний ник этого пользователя забываются.
+	// Пустой ник — у пользователя ника больше нет.
+	SaveUsername(ctx context.Context, userID UserID, username string) error
+	// ErrUserNotFound, если ник не встречался.
+	FindUserIDByUsername(ctx context.Context, username string) (UserID, error)
+}
+
+// Персональные настройки пользователей.
+type UserSettingsRepository interface {
+	// Возвращает ErrUserNotFound, если пользователь ничего не настраивал.
+	Get(ctx context.Context, userID UserID) (UserSettings, error)
+	Save(ctx context.Context, s UserSettings) error
+}
+
+// Журнал аудита административных действий.
+type AuditRepository interface {
+	Record(ctx context.Context, e AuditEntry) error
+}
+
 type LogRepository interface {
-	CreateSoglashenie(ctx context.Context, s Soglashenie) (int64, error)
-	CreateZapros(ctx context.Context, z Zapros) (int64, error)
+	// Создание записи. Порядковый номер в году выделяется в той же транзакции,
+	// номер форматируется по f. Возвращают сохранённую запись с ID и RegNumber.
+	CreateSoglashenie(ctx context.Context, s Soglashenie, f LogNumberFormat) (Soglashenie, error)
+	CreateZapros(ctx context.Context, z Zapros, f LogNumberFormat) (Zapros, error)
 
-	GetSoglasheniyaByUserID(ctx context.Context, userID UserID) ([]Soglashenie, error)
-	GetZaprosiByUserID(ctx context.Context, userID UserID) ([]Zapros, error)
+	// Страница записей пользователя (новые сначала) и общее число его записей.
+	GetSoglasheniyaByUserID(ctx context.Context, userID UserID, limit, offset int) ([]Soglashenie, int, error)
+	GetZaprosiByUserID(ctx context.Context, userID UserID, limit, offset int) ([]Zapros, int, error)
 
 	GetSoglashenieByID(ctx context.Context, id int64) (Soglashenie, error)
 	GetZaprosByID(ctx context.Context, id int64) (Zapros, error)
 
-	GetSoglasheniyaAfterDate(ctx context.Context, date time.Time) ([]Soglashenie, error)
-	GetZaprosiAfterDate(ctx context.Context, date time.Time) ([]Zapros, error)
+	// Запись по году и порядковому номеру. ErrRecordNotFound, если нет.
+	GetLogByRegNumber(ctx context.Context, kind LogKind, year, seq int) (LogRecord, error)
+
+	// Проставить номер по f записям, у которых есть год и порядковый номер,
+	// но нет самого номера. Возвращает число пронумерованных записей.
+	BackfillRegNumbers(ctx context.Context, f LogNumberFormat) (int, error)
+
+	// Записи для выгрузки: период по дате записи, фильтр по автору (q.Kind не учитывается).
+	// Нулевые q.From / q.To — без ограничения с этой стороны.
+	GetSoglasheniyaForReport(ctx context.Context, q ReportQuery) ([]Soglashenie, error)
+	GetZaprosiForReport(ctx context.Context, q ReportQuery) ([]Zapros, error)
 
 	GetUser(ctx context.Context, id int64) (User, error)
+	ListUsers(ctx context.Context) ([]User, error)
 	CreateUser(ctx context.Context, id int64, FIO string) error
+	// Правка ФИО и отключение адвоката. ErrUserNotFound, если его нет.
+	UpdateUserFIO(ctx context.Context, id int64, fio string) error
+	SetUserActive(ctx context.Context, id int64, active bool) error
+
+	// Поиск по доверителю и комментарию. Возвращает страницу результатов
+	// (новые записи сначала) и общее число найденных.
+	Search(ctx context.Context, f LogSearchFilter) ([]LogRecord, int, error)
+
+	// Правка поля и аннулирование — в одной транзакции с записью в историю.
+	// rev.Kind и rev.RecordID указывают на запись. Для уже аннулированной
+	// записи возвращают ErrRecordVoided.
+	UpdateLogField(ctx context.Context, rev LogRevision) error
+	VoidLog(ctx context.Context, rev LogRevision) error
+	ListRevisions(ctx context.Context, kind LogKind, recordID int64) ([]LogRevision, error)
+
+	// Вложения записей. Тот же файл (FileUniqueID) к записи повторно не
+	// прикладывается — ErrAttachmentExists.
+	AddLogAttachment(ctx context.Context, a LogAttachment) (LogAttachment, error)
+	ListLogAttachments(ctx context.Context, kind LogKind, recordID int64) ([]LogAttachment, error)
+	// Число вложений у записей ids; записей без вложений в ответе нет.
+	CountLogAttachments(ctx context.Context, kind LogKind, ids []int64) (map[int64]int, error)
+}
+
+// Реестр доверителей.
+type ClientRepository interface {
+	// Клиент с таким же NormName или новый клиент с именем name.
+	EnsureClient(ctx context.Context, name, normName string) (Client, error)
+	// ErrRecordNotFound, если клиента нет.
+	GetClient(ctx context.Context, id ClientID) (Client, error)
+	// Похожие по NormName клиенты, самые похожие сначала, с числом записей.
+	SuggestClients(ctx context.Context, normName string, limit int) ([]Client, error)
+
+	// Перепривязывает запись журнала к клиенту (0 — отвязать).
+	LinkLog(ctx context.Context, kind LogKind, recordID int64, id ClientID) error
+	// Переносит записи клиента from на клиента to и удаляет from в одной
+	// транзакции. Возвращает число перенесённых записей.
+	MergeClients(ctx context.Context, from, to ClientID) (int64, error)
 }
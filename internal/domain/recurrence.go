@@ -0,0 +1,100 @@
+package domain
+
+import "time"
+
+// Максимальное число повторений в одной серии (защита от «бесконечных» серий).
+const MaxSeriesOccurrences = 100
+
+func (f RecurrenceFreq) Valid() bool {
+	switch f {
+	case RecurDaily, RecurWeekdays, RecurWeekly, RecurBiweekly:
+		return true
+	}
+	return false
+}
+
+func (r RecurrenceRule) IsZero() bool { return r.Freq == RecurNone }
+
+// Validate проверяет, что правило задано корректно: известная частота
+// и ровно одно из условий окончания (Count или Until).
+func (r RecurrenceRule) Validate() error {
+	if !r.Freq.Valid() {
+		return ErrInvalidRecurrence
+	}
+	hasCount := r.Count > 0
+	hasUntil := !r.Until.IsZero()
+	if hasCount == hasUntil {
+		return ErrInvalidRecurrence
+	}
+	if r.Count > MaxSeriesOccurrences {
+		return ErrSeriesTooLong
+	}
+	return nil
+}
+
+// Occurrences разворачивает правило в список интервалов, начиная с first.
+// Шаг считается в локальной TZ офиса, чтобы переход на летнее/зимнее время
+// не сдвигал начало встречи. Возвращаемые интервалы — в UTC. Правило,
+// которое дало бы больше MaxSeriesOccurrences повторений (в том числе по
+// Until), не обрезается молча, а отклоняется с ErrSeriesTooLong.
+func (r RecurrenceRule) Occurrences(first TimeRange, loc *time.Location) ([]TimeRange, error) {
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	dur := first.Duration()
+	start := first.Start.In(loc)
+
+	var until time.Time
+	if !r.Until.IsZero() {
+		u := r.Until.In(loc)
+		// Until — дата включительно
+		until = time.Date(u.Year(), u.Month(), u.Day(), 0, 0, 0, 0, loc).AddDate(0, 0, 1)
+		if !until.After(start) {
+			return nil, ErrInvalidRecurrence
+		}
+	}
+
+	out := make([]TimeRange, 0, 8)
+	// Validate гарантирует Count или Until, поэтому цикл конечен
+	for day := 0; ; day++ {
+		s := time.Date(start.Year(), start.Month(), start.Day()+day, start.Hour(), start.Minute(), 0, 0, loc)
+		if !until.IsZero() && !s.Before(until) {
+			break
+		}
+		if !r.matches(day, s) {
+			continue
+		}
+		if len(out) == MaxSeriesOccurrences {
+			return nil, ErrSeriesTooLong
+		}
+		tr, err := NewTimeRange(s, s.Add(dur))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, tr)
+		if r.Count > 0 && len(out) == r.Count {
+			break
+		}
+	}
+	return out, nil
+}
+
+// matches — попадает ли день со смещением offset от первого в правило.
+func (r RecurrenceRule) matches(offset int, day time.Time) bool {
+	switch r.Freq {
+	case RecurDaily:
+		return true
+	case RecurWeekdays:
+		wd := day.Weekday()
+		return wd != time.Saturday && wd != time.Sunday
+	case RecurWeekly:
+		return offset%7 == 0
+	case RecurBiweekly:
+		return offset%14 == 0
+	}
+	return false
+}
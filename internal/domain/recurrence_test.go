@@ -0,0 +1,144 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func mustLoc(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Fatalf("load location %s: %v", name, err)
+	}
+	return loc
+}
+
+func TestRecurrenceOccurrences(t *testing.T) {
+	berlin := mustLoc(t, "Europe/Berlin")
+	local := func(y int, m time.Month, d, h, min int) time.Time {
+		return time.Date(y, m, d, h, min, 0, 0, berlin)
+	}
+	// Пятница 10:00–11:00 по Берлину
+	friday := TimeRange{Start: MustUTC(local(2026, 1, 9, 10, 0)), End: MustUTC(local(2026, 1, 9, 11, 0))}
+
+	tests := []struct {
+		name  string
+		rule  RecurrenceRule
+		first TimeRange
+		want  []time.Time // начала повторений в локальном времени
+	}{
+		{
+			name:  "daily count",
+			rule:  RecurrenceRule{Freq: RecurDaily, Count: 3},
+			first: friday,
+			want:  []time.Time{local(2026, 1, 9, 10, 0), local(2026, 1, 10, 10, 0), local(2026, 1, 11, 10, 0)},
+		},
+		{
+			name:  "weekdays skip weekend",
+			rule:  RecurrenceRule{Freq: RecurWeekdays, Count: 3},
+			first: friday,
+			want:  []time.Time{local(2026, 1, 9, 10, 0), local(2026, 1, 12, 10, 0), local(2026, 1, 13, 10, 0)},
+		},
+		{
+			name:  "weekly until is inclusive",
+			rule:  RecurrenceRule{Freq: RecurWeekly, Until: local(2026, 1, 23, 0, 0)},
+			first: friday,
+			want:  []time.Time{local(2026, 1, 9, 10, 0), local(2026, 1, 16, 10, 0), local(2026, 1, 23, 10, 0)},
+		},
+		{
+			name:  "biweekly until",
+			rule:  RecurrenceRule{Freq: RecurBiweekly, Until: local(2026, 2, 5, 0, 0)},
+			first: friday,
+			want:  []time.Time{local(2026, 1, 9, 10, 0), local(2026, 1, 23, 10, 0)},
+		},
+		{
+			// 29 марта Берлин переходит на летнее время: встреча остаётся в 10:00
+			name: "weekly across spring DST",
+			rule: RecurrenceRule{Freq: RecurWeekly, Count: 2},
+			first: TimeRange{
+				Start: MustUTC(local(2026, 3, 23, 10, 0)),
+				End:   MustUTC(local(2026, 3, 23, 11, 0)),
+			},
+			want: []time.Time{local(2026, 3, 23, 10, 0), local(2026, 3, 30, 10, 0)},
+		},
+		{
+			// 25 октября — обратный переход
+			name: "daily across autumn DST",
+			rule: RecurrenceRule{Freq: RecurDaily, Count: 3},
+			first: TimeRange{
+				Start: MustUTC(local(2026, 10, 24, 9, 30)),
+				End:   MustUTC(local(2026, 10, 24, 10, 30)),
+			},
+			want: []time.Time{local(2026, 10, 24, 9, 30), local(2026, 10, 25, 9, 30), local(2026, 10, 26, 9, 30)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rule.Occurrences(tt.first, berlin)
+			if err != nil {
+				t.Fatalf("Occurrences: %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d occurrences, want %d: %v", len(got), len(tt.want), got)
+			}
+			for i, tr := range got {
+				if !tr.Start.Equal(tt.want[i]) {
+					t.Errorf("occurrence %d starts at %v, want %v", i, tr.Start.In(berlin), tt.want[i])
+				}
+				if tr.Duration() != tt.first.Duration() {
+					t.Errorf("occurrence %d lasts %v, want %v", i, tr.Duration(), tt.first.Duration())
+				}
+				if tr.Start.Location() != time.UTC {
+					t.Errorf("occurrence %d is not in UTC: %v", i, tr.Start.Location())
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrenceOccurrencesErrors(t *testing.T) {
+	loc := mustLoc(t, "Europe/Moscow")
+	start := time.Date(2026, 1, 9, 10, 0, 0, 0, loc)
+	first := TimeRange{Start: MustUTC(start), End: MustUTC(start.Add(time.Hour))}
+
+	tests := []struct {
+		name string
+		rule RecurrenceRule
+		want error
+	}{
+		{"unknown freq", RecurrenceRule{Freq: "monthly", Count: 2}, ErrInvalidRecurrence},
+		{"no end", RecurrenceRule{Freq: RecurDaily}, ErrInvalidRecurrence},
+		{"count and until", RecurrenceRule{Freq: RecurDaily, Count: 2, Until: start.AddDate(0, 0, 5)}, ErrInvalidRecurrence},
+		{"until before start", RecurrenceRule{Freq: RecurDaily, Until: start.AddDate(0, 0, -1)}, ErrInvalidRecurrence},
+		{"count over limit", RecurrenceRule{Freq: RecurDaily, Count: MaxSeriesOccurrences + 1}, ErrSeriesTooLong},
+		{"until over limit", RecurrenceRule{Freq: RecurDaily, Until: start.AddDate(0, 0, MaxSeriesOccurrences)}, ErrSeriesTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.rule.Occurrences(first, loc)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, want %v (got %d occurrences)", err, tt.want, len(got))
+			}
+		})
+	}
+}
+
+func TestRecurrenceOccurrencesAtLimit(t *testing.T) {
+	loc := mustLoc(t, "Europe/Moscow")
+	start := time.Date(2026, 1, 9, 10, 0, 0, 0, loc)
+	first := TimeRange{Start: MustUTC(start), End: MustUTC(start.Add(time.Hour))}
+
+	// Until, дающий ровно MaxSeriesOccurrences повторений, ещё допустим
+	rule := RecurrenceRule{Freq: RecurDaily, Until: start.AddDate(0, 0, MaxSeriesOccurrences-1)}
+	got, err := rule.Occurrences(first, loc)
+	if err != nil {
+		t.Fatalf("Occurrences: %v", err)
+	}
+	if len(got) != MaxSeriesOccurrences {
+		t.Fatalf("got %d occurrences, want %d", len(got), MaxSeriesOccurrences)
+	}
+}
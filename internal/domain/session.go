@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// Вид диалога с ботом. У пользователя не больше одной сессии каждого вида.
+type SessionKind string
+
+const (
+	SessionBooking SessionKind = "booking"
+	SessionLogs    SessionKind = "logs"
+	SessionFlow    SessionKind = "flow" // текущий диалог пользователя и его шаг
+)
+
+// Незавершённый диалог пользователя. Data — состояние диалога в JSON,
+// его формат знает только слой доставки.
+type Session struct {
+	Kind      SessionKind
+	UserID    int64
+	Data      []byte
+	UpdatedAt time.Time
+	ExpiresAt time.Time
+}
+
+func (s Session) Expired(now time.Time) bool {
+	return !s.ExpiresAt.After(now)
+}
@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// FreeRanges вычитает занятые интервалы из окна window и выравнивает
+// оставшиеся промежутки по сетке step, отсчитываемой от window.Start
+// (начало округляется вверх, конец — вниз). Промежутки короче step отбрасываются.
+func FreeRanges(window TimeRange, busy []TimeRange, step time.Duration) []TimeRange {
+	if step <= 0 {
+		step = time.Minute
+	}
+
+	sorted := make([]TimeRange, len(busy))
+	copy(sorted, busy)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start.Before(sorted[j].Start) })
+
+	out := make([]TimeRange, 0, len(sorted)+1)
+	cursor := window.Start
+	appendGap := func(from, to time.Time) {
+		s := alignUp(from, window.Start, step)
+		e := alignDown(to, window.Start, step)
+		if e.Sub(s) >= step {
+			out = append(out, TimeRange{Start: s, End: e})
+		}
+	}
+
+	for _, b := range sorted {
+		if !b.End.After(cursor) {
+			continue
+		}
+		if !b.Start.Before(window.End) {
+			break
+		}
+		if b.Start.After(cursor) {
+			appendGap(cursor, b.Start)
+		}
+		cursor = b.End
+	}
+	if cursor.Before(window.End) {
+		appendGap(cursor, window.End)
+	}
+	return out
+}
+
+// SlotStarts — все возможные начала брони длительностью dur с шагом step
+// внутри свободных промежутков free.
+func SlotStarts(free []TimeRange, dur, step time.Duration) []time.Time {
+	if step <= 0 {
+		return nil
+	}
+	out := make([]time.Time, 0)
+	for _, tr := range free {
+		for s := tr.Start; !s.Add(dur).After(tr.End); s = s.Add(step) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func alignUp(t, origin time.Time, step time.Duration) time.Time {
+	if !t.After(origin) {
+		return origin
+	}
+	n := (t.Sub(origin) + step - 1) / step
+	return origin.Add(n * step)
+}
+
+func alignDown(t, origin time.Time, step time.Duration) time.Time {
+	if !t.After(origin) {
+		return origin
+	}
+	n := t.Sub(origin) / step
+	return origin.Add(n * step)
+}
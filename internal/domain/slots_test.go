@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFreeRanges(t *testing.T) {
+	day := time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC)
+	at := func(h, min int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(min)*time.Minute)
+	}
+	tr := func(h1, m1, h2, m2 int) TimeRange { return TimeRange{Start: at(h1, m1), End: at(h2, m2)} }
+	window := tr(9, 0, 18, 0)
+	step := 30 * time.Minute
+
+	tests := []struct {
+		name string
+		busy []TimeRange
+		want []TimeRange
+	}{
+		{"empty day", nil, []TimeRange{window}},
+		{"one booking", []TimeRange{tr(11, 0, 12, 0)}, []TimeRange{tr(9, 0, 11, 0), tr(12, 0, 18, 0)}},
+		{"unsorted", []TimeRange{tr(15, 0, 16, 0), tr(10, 0, 11, 0)}, []TimeRange{tr(9, 0, 10, 0), tr(11, 0, 15, 0), tr(16, 0, 18, 0)}},
+		{"adjacent bookings", []TimeRange{tr(10, 0, 11, 0), tr(11, 0, 12, 0)}, []TimeRange{tr(9, 0, 10, 0), tr(12, 0, 18, 0)}},
+		{"overlapping bookings", []TimeRange{tr(10, 0, 12, 0), tr(11, 0, 11, 30)}, []TimeRange{tr(9, 0, 10, 0), tr(12, 0, 18, 0)}},
+		{"off-grid edges are aligned", []TimeRange{tr(10, 10, 11, 40)}, []TimeRange{tr(9, 0, 10, 0), tr(12, 0, 18, 0)}},
+		{"gap shorter than step dropped", []TimeRange{tr(9, 0, 10, 10), tr(10, 30, 18, 0)}, []TimeRange{}},
+		{"bookings outside window", []TimeRange{tr(7, 0, 9, 0), tr(18, 0, 20, 0)}, []TimeRange{window}},
+		{"booking across window start", []TimeRange{tr(8, 0, 9, 30)}, []TimeRange{tr(9, 30, 18, 0)}},
+		{"fully busy", []TimeRange{tr(8, 0, 19, 0)}, []TimeRange{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FreeRanges(window, tt.busy, step)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSlotStarts(t *testing.T) {
+	day := time.Date(2026, 1, 14, 0, 0, 0, 0, time.UTC)
+	at := func(h, min int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(min)*time.Minute)
+	}
+	free := []TimeRange{{Start: at(9, 0), End: at(10, 30)}, {Start: at(12, 0), End: at(12, 30)}}
+
+	got := SlotStarts(free, time.Hour, 30*time.Minute)
+	want := []time.Time{at(9, 0), at(9, 30)}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,362 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/leegeev/KomaevBookingBot/pkg/logger"
+)
+
+// Версионные миграции схемы. Каждая миграция применяется в своей
+// транзакции вместе с записью в schema_migrations, поэтому схема не
+// остаётся в промежуточном состоянии. Advisory lock не даёт двум
+// экземплярам бота мигрировать одну базу одновременно.
+//
+// Базы, созданные раньше через docker-entrypoint-initdb.d, размечаются
+// при первом запуске (adoptLegacy): если schema_migrations пуста, а таблицы
+// исходных скриптов 001 и 002 уже есть, эти версии считаются применёнными.
+// Остальные миграции на такой базе применяются как обычно.
+
+// Ключ pg_advisory_lock для миграций (произвольная константа).
+const migrationLockKey = 7344061
+
+var migrationFileRe = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+const (
+	qCreateSchemaMigrations = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version     INT PRIMARY KEY,
+    name        TEXT NOT NULL,
+    applied_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);`
+	qSelectSchemaMigrations = `SELECT version, applied_at FROM schema_migrations ORDER BY version;`
+	qInsertSchemaMigration  = `INSERT INTO schema_migrations (version, name) VALUES ($1, $2);`
+	qDeleteSchemaMigration  = `DELETE FROM schema_migrations WHERE version = $1;`
+	qCountSchemaMigrations  = `SELECT count(*) FROM schema_migrations;`
+	qTableExists            = `SELECT to_regclass($1) IS NOT NULL;`
+	qMigrationLock          = `SELECT pg_advisory_lock($1);`
+	qMigrationUnlock        = `SELECT pg_advisory_unlock($1);`
+)
+
+// Миграции, которые раньше выполнялись через docker-entrypoint-initdb.d,
+// и таблица, по которой видно, что скрипт уже выполнен.
+var legacyMigrations = []struct {
+	version int
+	table   string
+}{
+	{1, "bookings"},
+	{2, "soglasheniya"},
+}
+
+var (
+	ErrMigrationsPending = errors.New("schema has pending migrations")
+	ErrSchemaTooNew      = errors.New("schema is newer than this binary")
+)
+
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Состояние миграции в базе. AppliedAt нулевой — миграция не применена.
+type MigrationStatus struct {
+	Migration
+	AppliedAt time.Time
+}
+
+func (s MigrationStatus) Applied() bool {
+	return !s.AppliedAt.IsZero()
+}
+
+type Migrator struct {
+	db         *sqlx.DB
+	logger     logger.Logger
+	migrations []Migration // по возрастанию версии
+}
+
+// NewMigrator читает миграции из корня fsys. У каждой версии должны быть
+// и up, и down.
+func NewMigrator(db *sqlx.DB, fsys fs.FS, logger logger.Logger) (*Migrator, error) {
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, logger: logger, migrations: migrations}, nil
+}
+
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations: %w", err)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, e := range entries {
+		m := migrationFileRe.FindStringSubmatch(e.Name())
+		if e.IsDir() || m == nil {
+			continue
+		}
+		version, _ := strconv.Atoi(m[1])
+		body, err := fs.ReadFile(fsys, path.Join(".", e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s: %w", e.Name(), err)
+		}
+
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: m[2]}
+			byVersion[version] = mig
+		} else if mig.Name != m[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, mig.Name, m[2])
+		}
+		if m[3] == "up" {
+			mig.Up = string(body)
+		} else {
+			mig.Down = string(body)
+		}
+	}
+
+	out := make([]Migration, 0, len(byVersion))
+	for _, mig := range byVersion {
+		if mig.Up == "" || mig.Down == "" {
+			return nil, fmt.Errorf("migration %03d_%s must have both up and down files", mig.Version, mig.Name)
+		}
+		out = append(out, *mig)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out, nil
+}
+
+// Latest — последняя версия схемы, известная бинарю.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status — все известные миграции и время их применения. Версии из базы,
+// которых нет в бинаре, возвращаются с пустыми Up/Down.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var out []MigrationStatus
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		out = m.status(applied)
+		return nil
+	})
+	return out, err
+}
+
+// Check проверяет, что схема базы совпадает с бинарём: нет ни
+// неприменённых миграций, ни версий новее известных.
+func (m *Migrator) Check(ctx context.Context) error {
+	list, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	for _, s := range list {
+		if s.Up == "" {
+			return fmt.Errorf("%w: version %d", ErrSchemaTooNew, s.Version)
+		}
+		if !s.Applied() {
+			return fmt.Errorf("%w: version %d", ErrMigrationsPending, s.Version)
+		}
+	}
+	return nil
+}
+
+// Up применяет все неприменённые миграции по порядку. Возвращает их число.
+func (m *Migrator) Up(ctx context.Context) (int, error) {
+	n := 0
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, s := range m.status(applied) {
+			if s.Up == "" {
+				return fmt.Errorf("%w: version %d", ErrSchemaTooNew, s.Version)
+			}
+		}
+
+		for _, mig := range m.migrations {
+			if _, ok := applied[mig.Version]; ok {
+				continue
+			}
+			m.logger.Info("Applying migration", "version", mig.Version, "name", mig.Name)
+			if err := m.apply(ctx, conn, mig.Up, func(tx *sqlx.Tx) error {
+				_, err := tx.ExecContext(ctx, qInsertSchemaMigration, mig.Version, mig.Name)
+				return err
+			}); err != nil {
+				return fmt.Errorf("migration %03d_%s up: %w", mig.Version, mig.Name, err)
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// DownTo откатывает применённые миграции с версией больше target, начиная
+// с последней. DownTo(0) откатывает всё. Возвращает число откаченных.
+func (m *Migrator) DownTo(ctx context.Context, target int) (int, error) {
+	if target < 0 {
+		return 0, fmt.Errorf("invalid target version %d", target)
+	}
+
+	n := 0
+	err := m.withLock(ctx, func(conn *sqlx.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		list := m.status(applied)
+		for i := len(list) - 1; i >= 0; i-- {
+			s := list[i]
+			if s.Version <= target || !s.Applied() {
+				continue
+			}
+			if s.Down == "" {
+				return fmt.Errorf("%w: version %d has no down migration here", ErrSchemaTooNew, s.Version)
+			}
+			m.logger.Info("Reverting migration", "version", s.Version, "name", s.Name)
+			if err := m.apply(ctx, conn, s.Down, func(tx *sqlx.Tx) error {
+				_, err := tx.ExecContext(ctx, qDeleteSchemaMigration, s.Version)
+				return err
+			}); err != nil {
+				return fmt.Errorf("migration %03d_%s down: %w", s.Version, s.Name, err)
+			}
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
+
+// withLock выполняет fn на отдельном соединении под advisory lock:
+// блокировка сессионная, поэтому все запросы идут через одно соединение.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sqlx.Conn) error) error {
+	conn, err := m.db.Connx(ctx)
+	if err != nil {
+		return fmt.Errorf("get connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, qMigrationLock, migrationLockKey); err != nil {
+		return fmt.Errorf("acquire migration lock: %w", err)
+	}
+	defer func() {
+		// Контекст может быть уже отменён, а блокировку надо снять в любом случае
+		if _, err := conn.ExecContext(context.Background(), qMigrationUnlock, migrationLockKey); err != nil {
+			m.logger.Error("Failed to release migration lock", "err", err)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, qCreateSchemaMigrations); err != nil {
+		return fmt.Errorf("create schema_migrations: %w", err)
+	}
+	if err := m.adoptLegacy(ctx, conn); err != nil {
+		return fmt.Errorf("adopt legacy schema: %w", err)
+	}
+	return fn(conn)
+}
+
+// adoptLegacy отмечает применёнными миграции, которые база получила ещё
+// через docker-entrypoint-initdb.d. Срабатывает только на пустой
+// schema_migrations, так что размеченную базу не трогает.
+func (m *Migrator) adoptLegacy(ctx context.Context, conn *sqlx.Conn) error {
+	var count int
+	if err := conn.GetContext(ctx, &count, qCountSchemaMigrations); err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+
+	names := make(map[int]string, len(m.migrations))
+	for _, mig := range m.migrations {
+		names[mig.Version] = mig.Name
+	}
+	for _, l := range legacyMigrations {
+		name, ok := names[l.version]
+		if !ok {
+			continue
+		}
+		var exists bool
+		if err := conn.GetContext(ctx, &exists, qTableExists, l.table); err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if _, err := conn.ExecContext(ctx, qInsertSchemaMigration, l.version, name); err != nil {
+			return err
+		}
+		m.logger.Info("Adopted existing schema as migrated", "version", l.version, "name", name, "table", l.table)
+	}
+	return nil
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *sqlx.Conn) (map[int]time.Time, error) {
+	var rows []struct {
+		Version   int       `db:"version"`
+		AppliedAt time.Time `db:"applied_at"`
+	}
+	if err := conn.SelectContext(ctx, &rows, qSelectSchemaMigrations); err != nil {
+		return nil, fmt.Errorf("select schema_migrations: %w", err)
+	}
+	out := make(map[int]time.Time, len(rows))
+	for _, r := range rows {
+		out[r.Version] = r.AppliedAt
+	}
+	return out, nil
+}
+
+func (m *Migrator) status(applied map[int]time.Time) []MigrationStatus {
+	out := make([]MigrationStatus, 0, len(m.migrations))
+	known := make(map[int]bool, len(m.migrations))
+	for _, mig := range m.migrations {
+		known[mig.Version] = true
+		out = append(out, MigrationStatus{Migration: mig, AppliedAt: applied[mig.Version]})
+	}
+	for v, at := range applied {
+		if !known[v] {
+			out = append(out, MigrationStatus{Migration: Migration{Version: v}, AppliedAt: at})
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Version < out[j].Version })
+	return out
+}
+
+// apply выполняет SQL миграции и отметку в schema_migrations одной транзакцией.
+// Скрипт передаётся без параметров, поэтому pgx отправляет его простым
+// протоколом и в нём может быть несколько команд.
+func (m *Migrator) apply(ctx context.Context, conn *sqlx.Conn, script string, mark func(tx *sqlx.Tx) error) error {
+	tx, err := conn.BeginTxx(ctx, &sql.TxOptions{})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if err := mark(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
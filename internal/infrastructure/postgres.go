@@ -20,7 +20,7 @@ func ConnectDBWithRetry(ctx context.Context, cfg config.DB, logger logger.Logger
 	var db *sqlx.DB
 	var err error
 	dsn := cfg.DSN()
-	logger.Info("DSN loaded", "dsn", dsn)
+	logger.Info("DSN loaded", "host", cfg.Host, "port", cfg.Port, "dbname", cfg.DBName, "user", cfg.User)
 	for attempt := 1; attempt <= cfg.RetryCount; attempt++ {
 		db, err = sqlx.Open("pgx", dsn)
 		if err != nil {
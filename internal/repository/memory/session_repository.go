@@ -0,0 +1,68 @@
+// Package memory — реализации репозиториев в памяти процесса: для тестов
+// и локального запуска без базы.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+type sessionKey struct {
+	kind   domain.SessionKind
+	userID int64
+}
+
+type SessionRepository struct {
+	mu   sync.Mutex
+	data map[sessionKey]domain.Session
+}
+
+func NewSessionRepository() *SessionRepository {
+	return &SessionRepository{data: make(map[sessionKey]domain.Session)}
+}
+
+func (r *SessionRepository) GetSession(ctx context.Context, kind domain.SessionKind, userID int64) (domain.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.data[sessionKey{kind, userID}]
+	if !ok {
+		return domain.Session{}, domain.ErrSessionNotFound
+	}
+	s.Data = append([]byte(nil), s.Data...)
+	return s, nil
+}
+
+func (r *SessionRepository) SaveSession(ctx context.Context, s domain.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s.Data = append([]byte(nil), s.Data...)
+	r.data[sessionKey{s.Kind, s.UserID}] = s
+	return nil
+}
+
+func (r *SessionRepository) DeleteSession(ctx context.Context, kind domain.SessionKind, userID int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.data, sessionKey{kind, userID})
+	return nil
+}
+
+func (r *SessionRepository) DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var n int64
+	for k, s := range r.data {
+		if s.ExpiresAt.Before(before) {
+			delete(r.data, k)
+			n++
+		}
+	}
+	return n, nil
+}
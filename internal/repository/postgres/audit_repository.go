@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/pkg/logger"
+)
+
+type auditRepositoryPG struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+func NewAuditRepositoryPG(db *sqlx.DB, l logger.Logger) *auditRepositoryPG {
+	return &auditRepositoryPG{db: db, log: l}
+}
+
+func (r *auditRepositoryPG) Record(ctx context.Context, e domain.AuditEntry) error {
+	r.log.Debug("Recording audit entry", "action", e.Action, "actor", e.ActorID, "booking", e.BookingID)
+	var newID int64
+	err := r.db.QueryRowxContext(ctx, qInsertAudit,
+		int64(e.ActorID),
+		e.ActorName,
+		string(e.Action),
+		int64(e.BookingID),
+		int64(e.OwnerID),
+		e.Reason,
+		e.Details,
+	).Scan(&newID)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return nil
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"time"
@@ -18,41 +20,84 @@ type bookingRepositoryPG struct {
 }
 
 type bookingRow struct {
-	ID        int64     `db:"id"`
-	RoomID    int64     `db:"room_id"`
-	RoomName  string    `db:"room_name"`
-	UserID    int64     `db:"user_id"`
-	UserName  string    `db:"user_name"`
-	StartUTC  time.Time `db:"start_utc"`
-	EndUTC    time.Time `db:"end_utc"`
-	CreatedAt time.Time `db:"created_at"`
+	ID        int64         `db:"id"`
+	RoomID    int64         `db:"room_id"`
+	RoomName  string        `db:"room_name"`
+	UserID    int64         `db:"user_id"`
+	UserName  string        `db:"user_name"`
+	StartUTC  time.Time     `db:"start_utc"`
+	EndUTC    time.Time     `db:"end_utc"`
+	SeriesID  sql.NullInt64 `db:"series_id"`
+	Note      string        `db:"note"`
+	Attendees string        `db:"attendees"` // json-массив booking_attendees
+	CheckedIn sql.NullTime  `db:"checked_in_at"`
+	CreatedAt time.Time     `db:"created_at"`
+}
+
+type releasedRow struct {
+	bookingRow
+	OriginalEnd time.Time `db:"original_end"`
+}
+
+type attendeeJSON struct {
+	UserID *int64 `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+type seriesRow struct {
+	ID       int64        `db:"id"`
+	RoomID   int64        `db:"room_id"`
+	RoomName string       `db:"room_name"`
+	UserID   int64        `db:"user_id"`
+	UserName string       `db:"user_name"`
+	Freq     string       `db:"freq"`
+	Count    int          `db:"count"`
+	Until    sql.NullTime `db:"until"`
 }
 
 func NewBookingRepositoryPG(db *sqlx.DB, logger logger.Logger) *bookingRepositoryPG {
 	return &bookingRepositoryPG{db: db, logger: logger}
 }
 
+// Create сохраняет бронь вместе с участниками в одной транзакции.
 func (r *bookingRepositoryPG) Create(ctx context.Context, b domain.Booking) error {
-	start := b.Range.Start
-	end := b.Range.End
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
 
-	var newID int64
+	if err := insertBooking(ctx, tx, b); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
 
-	err := r.db.QueryRowxContext(
+// insertBooking вставляет бронь с участниками в транзакции tx.
+func insertBooking(ctx context.Context, tx *sqlx.Tx, b domain.Booking) error {
+	var newID int64
+	err := tx.QueryRowxContext(
 		ctx,
 		qInsertBooking,
 		b.RoomID,
 		b.RoomName,
 		b.UserID,
 		b.UserName,
-		start,
-		end,
+		b.Range.Start,
+		b.Range.End,
+		nullSeriesID(b.SeriesID),
+		b.Note,
 	).Scan(&newID)
 	if err != nil {
 		return mapPgOverlapErr(err)
 	}
 
-	// b.ID = domain.BookingID(newID)
+	for _, a := range b.Attendees {
+		userID := sql.NullInt64{Int64: int64(a.UserID), Valid: a.UserID != 0}
+		if _, err := tx.ExecContext(ctx, qInsertAttendee, newID, userID, a.Name); err != nil {
+			return fmt.Errorf("failed to insert attendee: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -69,14 +114,68 @@ func (r *bookingRepositoryPG) Delete(ctx context.Context, id domain.BookingID) e
 	return nil
 }
 
+func (r *bookingRepositoryPG) DeleteWithAudit(ctx context.Context, id domain.BookingID, e domain.AuditEntry) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, qDeleteByID, int64(id))
+	if err != nil {
+		return err
+	}
+	if aff, _ := res.RowsAffected(); aff == 0 {
+		return domain.ErrBookingNotFound
+	}
+
+	var auditID int64
+	err = tx.QueryRowxContext(ctx, qInsertAudit,
+		int64(e.ActorID),
+		e.ActorName,
+		string(e.Action),
+		int64(e.BookingID),
+		int64(e.OwnerID),
+		e.Reason,
+		e.Details,
+	).Scan(&auditID)
+	if err != nil {
+		return fmt.Errorf("failed to record audit entry: %w", err)
+	}
+	return tx.Commit()
+}
+
 func (r *bookingRepositoryPG) GetByID(ctx context.Context, id domain.BookingID) (domain.Booking, error) {
 	var br bookingRow
 	if err := r.db.GetContext(ctx, &br, qSelectByID, int64(id)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Booking{}, domain.ErrBookingNotFound
+		}
 		return domain.Booking{}, err
 	}
 	return bookingRowToDomain(br)
 }
 
+func (r *bookingRepositoryPG) UpdateRange(ctx context.Context, id domain.BookingID, roomID domain.RoomID, roomName string, tr domain.TimeRange) error {
+	var updatedID int64
+	err := r.db.QueryRowxContext(
+		ctx,
+		qUpdateBookingRange,
+		int64(id),
+		int64(roomID),
+		roomName,
+		tr.Start,
+		tr.End,
+	).Scan(&updatedID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrBookingNotFound
+	}
+	if err != nil {
+		return mapPgOverlapErr(err)
+	}
+	return nil
+}
+
 func (r *bookingRepositoryPG) ListByRoomAndInterval(ctx context.Context, roomID domain.RoomID, fromUTC, toUTC time.Time) ([]domain.Booking, error) {
 	var rows []bookingRow
 	if err := r.db.SelectContext(ctx, &rows, qListByRoomAndInterval, int64(roomID), fromUTC, toUTC); err != nil {
@@ -117,6 +216,58 @@ func (r *bookingRepositoryPG) AnyOverlap(ctx context.Context, roomID domain.Room
 	return has, nil
 }
 
+func (r *bookingRepositoryPG) ClaimDueReminders(ctx context.Context, nowUTC time.Time, defaultLead time.Duration) ([]domain.Booking, error) {
+	var rows []bookingRow
+	if err := r.db.SelectContext(ctx, &rows, qClaimDueReminders, nowUTC, int(defaultLead.Minutes())); err != nil {
+		return nil, err
+	}
+	out := make([]domain.Booking, 0, len(rows))
+	for _, br := range rows {
+		b, err := bookingRowToDomain(br)
+		if err != nil {
+			return nil, fmt.Errorf("bookingRowToDomain: %w", err)
+		}
+		out = append(out, b)
+	}
+	return out, nil
+}
+
+func (r *bookingRepositoryPG) ReleaseReminder(ctx context.Context, id domain.BookingID) error {
+	_, err := r.db.ExecContext(ctx, qReleaseReminder, int64(id))
+	return err
+}
+
+func (r *bookingRepositoryPG) CheckIn(ctx context.Context, id domain.BookingID, atUTC time.Time) error {
+	res, err := r.db.ExecContext(ctx, qCheckIn, int64(id), atUTC)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrBookingNotFound
+	}
+	return nil
+}
+
+func (r *bookingRepositoryPG) ReleaseNoShows(ctx context.Context, nowUTC time.Time, grace time.Duration) ([]domain.ReleasedBooking, error) {
+	var rows []releasedRow
+	if err := r.db.SelectContext(ctx, &rows, qReleaseNoShows, nowUTC, grace.Seconds()); err != nil {
+		return nil, err
+	}
+	out := make([]domain.ReleasedBooking, 0, len(rows))
+	for _, rr := range rows {
+		b, err := bookingRowToDomain(rr.bookingRow)
+		if err != nil {
+			return nil, fmt.Errorf("bookingRowToDomain: %w", err)
+		}
+		out = append(out, domain.ReleasedBooking{Booking: b, OriginalEnd: rr.OriginalEnd})
+	}
+	return out, nil
+}
+
 func (r *bookingRepositoryPG) DeleteEndedBefore(ctx context.Context, cutoffUTC time.Time) (int64, error) {
 	res, err := r.db.ExecContext(ctx, qDeleteEndedBefore, cutoffUTC)
 	if err != nil {
@@ -126,6 +277,88 @@ func (r *bookingRepositoryPG) DeleteEndedBefore(ctx context.Context, cutoffUTC t
 	return aff, nil
 }
 
+// CreateSeries вставляет серию и повторения одной транзакцией. Каждое
+// повторение — под своим savepoint: bookings_no_overlap (23P01) откатывает
+// только конфликтующую дату, а не всю серию.
+func (r *bookingRepositoryPG) CreateSeries(ctx context.Context, s domain.BookingSeries, occurrences []domain.Booking) (domain.SeriesID, []int, error) {
+	var until sql.NullTime
+	if !s.Rule.Until.IsZero() {
+		until = sql.NullTime{Time: s.Rule.Until, Valid: true}
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var newID int64
+	err = tx.QueryRowxContext(
+		ctx,
+		qInsertSeries,
+		int64(s.RoomID),
+		s.RoomName,
+		int64(s.UserID),
+		s.UserName,
+		string(s.Rule.Freq),
+		s.Rule.Count,
+		until,
+	).Scan(&newID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to create series: %w", err)
+	}
+
+	var conflicts []int
+	for i, b := range occurrences {
+		b.SeriesID = domain.SeriesID(newID)
+		if _, err := tx.ExecContext(ctx, qSavepointOccurrence); err != nil {
+			return 0, nil, fmt.Errorf("savepoint: %w", err)
+		}
+		err := insertBooking(ctx, tx, b)
+		if errors.Is(err, domain.ErrOverlapsExisting) {
+			if _, err := tx.ExecContext(ctx, qRollbackToOccurrence); err != nil {
+				return 0, nil, fmt.Errorf("rollback to savepoint: %w", err)
+			}
+			conflicts = append(conflicts, i)
+			continue
+		}
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to create series occurrence %s: %w", b.Range.Start, err)
+		}
+		if _, err := tx.ExecContext(ctx, qReleaseOccurrence); err != nil {
+			return 0, nil, fmt.Errorf("release savepoint: %w", err)
+		}
+	}
+	if len(conflicts) == len(occurrences) {
+		return 0, conflicts, domain.ErrOverlapsExisting
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, nil, err
+	}
+	return domain.SeriesID(newID), conflicts, nil
+}
+
+func (r *bookingRepositoryPG) GetSeries(ctx context.Context, id domain.SeriesID) (domain.BookingSeries, error) {
+	var sr seriesRow
+	if err := r.db.GetContext(ctx, &sr, qSelectSeriesByID, int64(id)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.BookingSeries{}, domain.ErrSeriesNotFound
+		}
+		return domain.BookingSeries{}, err
+	}
+	return seriesRowToDomain(sr), nil
+}
+
+func (r *bookingRepositoryPG) DeleteSeries(ctx context.Context, id domain.SeriesID, fromUTC time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, qDeleteSeriesFrom, int64(id), fromUTC)
+	if err != nil {
+		return 0, err
+	}
+	aff, _ := res.RowsAffected()
+	return aff, nil
+}
+
 // helper functions
 func bookingRowToDomain(br bookingRow) (domain.Booking, error) {
 	tr, err := domain.NewTimeRange(br.StartUTC, br.EndUTC)
@@ -133,17 +366,58 @@ func bookingRowToDomain(br bookingRow) (domain.Booking, error) {
 		return domain.Booking{}, err
 	}
 
+	var raw []attendeeJSON
+	if br.Attendees != "" {
+		if err := json.Unmarshal([]byte(br.Attendees), &raw); err != nil {
+			return domain.Booking{}, fmt.Errorf("unmarshal attendees: %w", err)
+		}
+	}
+	var attendees []domain.Attendee
+	for _, a := range raw {
+		att := domain.Attendee{Name: a.Name}
+		if a.UserID != nil {
+			att.UserID = domain.UserID(*a.UserID)
+		}
+		attendees = append(attendees, att)
+	}
+
 	return domain.Booking{
-		ID:       domain.BookingID(br.ID),
-		RoomID:   domain.RoomID(br.RoomID),
-		RoomName: br.RoomName,
-		UserID:   domain.UserID(br.UserID),
-		UserName: br.UserName,
-		Range:    tr,
+		ID:          domain.BookingID(br.ID),
+		RoomID:      domain.RoomID(br.RoomID),
+		RoomName:    br.RoomName,
+		UserID:      domain.UserID(br.UserID),
+		UserName:    br.UserName,
+		Range:       tr,
+		Note:        br.Note,
+		Attendees:   attendees,
+		SeriesID:    domain.SeriesID(br.SeriesID.Int64),
+		CheckedInAt: br.CheckedIn.Time,
 		// CreatedAt: br.CreatedAt.UTC(),
 	}, nil
 }
 
+func seriesRowToDomain(sr seriesRow) domain.BookingSeries {
+	rule := domain.RecurrenceRule{
+		Freq:  domain.RecurrenceFreq(sr.Freq),
+		Count: sr.Count,
+	}
+	if sr.Until.Valid {
+		rule.Until = sr.Until.Time
+	}
+	return domain.BookingSeries{
+		ID:       domain.SeriesID(sr.ID),
+		RoomID:   domain.RoomID(sr.RoomID),
+		RoomName: sr.RoomName,
+		UserID:   domain.UserID(sr.UserID),
+		UserName: sr.UserName,
+		Rule:     rule,
+	}
+}
+
+func nullSeriesID(id domain.SeriesID) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
+
 func mapPgOverlapErr(err error) error {
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) && pgErr.Code == "23P01" { // exclusion_violation
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/internal/repository/postgres/pgtest"
+)
+
+func createTestRoom(t *testing.T, conn *sqlx.DB) domain.Room {
+	t.Helper()
+	rooms := NewRoomRepositoryPG(conn, pgtest.Logger())
+	if err := rooms.Create(context.Background(), domain.Room{Name: "Переговорка 1", IsActive: true}); err != nil {
+		t.Fatalf("create room: %v", err)
+	}
+	room, err := rooms.GetByName(context.Background(), "Переговорка 1")
+	if err != nil {
+		t.Fatalf("get room: %v", err)
+	}
+	return room
+}
+
+func testOccurrence(t *testing.T, room domain.Room, userID domain.UserID, start time.Time) domain.Booking {
+	t.Helper()
+	tr, err := domain.NewTimeRange(start, start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("NewTimeRange: %v", err)
+	}
+	b, err := domain.NewBooking(room.ID, room.Name, userID, "user", tr)
+	if err != nil {
+		t.Fatalf("NewBooking: %v", err)
+	}
+	return b
+}
+
+func countSeries(t *testing.T, conn *sqlx.DB) int {
+	t.Helper()
+	var n int
+	if err := conn.Get(&n, "SELECT count(*) FROM booking_series"); err != nil {
+		t.Fatalf("count series: %v", err)
+	}
+	return n
+}
+
+func TestCreateSeriesConflicts(t *testing.T) {
+	ctx := context.Background()
+	conn := pgtest.Open(t)
+	repo := NewBookingRepositoryPG(conn, pgtest.Logger())
+	room := createTestRoom(t, conn)
+
+	day := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1).Add(10 * time.Hour)
+	if err := repo.Create(ctx, testOccurrence(t, room, 1, day.AddDate(0, 0, 1))); err != nil {
+		t.Fatalf("create existing booking: %v", err)
+	}
+	series := domain.BookingSeries{RoomID: room.ID, RoomName: room.Name, UserID: 2, UserName: "user",
+		Rule: domain.RecurrenceRule{Freq: domain.RecurDaily, Count: 3}}
+
+	// Второе повторение пересекается с чужой бронью — остальные создаются
+	id, conflicts, err := repo.CreateSeries(ctx, series, []domain.Booking{
+		testOccurrence(t, room, 2, day),
+		testOccurrence(t, room, 2, day.AddDate(0, 0, 1)),
+		testOccurrence(t, room, 2, day.AddDate(0, 0, 2)),
+	})
+	if err != nil {
+		t.Fatalf("CreateSeries: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0] != 1 {
+		t.Fatalf("conflicts = %v, want [1]", conflicts)
+	}
+	created, err := repo.ListByUser(ctx, 2, time.Now())
+	if err != nil {
+		t.Fatalf("ListByUser: %v", err)
+	}
+	if len(created) != 2 {
+		t.Fatalf("created %d occurrences, want 2", len(created))
+	}
+	for _, b := range created {
+		if b.SeriesID != id {
+			t.Errorf("booking %d has series %d, want %d", b.ID, b.SeriesID, id)
+		}
+	}
+
+	// Все повторения пересекаются — серии в БД не остаётся
+	_, conflicts, err = repo.CreateSeries(ctx, series, []domain.Booking{testOccurrence(t, room, 3, day.AddDate(0, 0, 1))})
+	if !errors.Is(err, domain.ErrOverlapsExisting) {
+		t.Fatalf("err = %v, want ErrOverlapsExisting", err)
+	}
+	if len(conflicts) != 1 {
+		t.Fatalf("conflicts = %v, want [0]", conflicts)
+	}
+	if n := countSeries(t, conn); n != 1 {
+		t.Fatalf("%d series rows, want 1", n)
+	}
+}
+
+// DeleteSeries не трогает уже начавшееся повторение.
+func TestDeleteSeriesKeepsOccurrenceInProgress(t *testing.T) {
+	ctx := context.Background()
+	conn := pgtest.Open(t)
+	repo := NewBookingRepositoryPG(conn, pgtest.Logger())
+	room := createTestRoom(t, conn)
+
+	now := time.Now().UTC().Truncate(time.Minute)
+	series := domain.BookingSeries{RoomID: room.ID, RoomName: room.Name, UserID: 2, UserName: "user",
+		Rule: domain.RecurrenceRule{Freq: domain.RecurDaily, Count: 3}}
+	id, _, err := repo.CreateSeries(ctx, series, []domain.Booking{
+		testOccurrence(t, room, 2, now.Add(-30*time.Minute)),
+		testOccurrence(t, room, 2, now.Add(24*time.Hour)),
+		testOccurrence(t, room, 2, now.Add(48*time.Hour)),
+	})
+	if err != nil {
+		t.Fatalf("CreateSeries: %v", err)
+	}
+
+	n, err := repo.DeleteSeries(ctx, id, now)
+	if err != nil {
+		t.Fatalf("DeleteSeries: %v", err)
+	}
+	if n != 2 {
+		t.Fatalf("deleted %d occurrences, want 2", n)
+	}
+	left, err := repo.ListByUser(ctx, 2, now)
+	if err != nil {
+		t.Fatalf("ListByUser: %v", err)
+	}
+	if len(left) != 1 || !left[0].Range.Start.Equal(now.Add(-30*time.Minute)) {
+		t.Fatalf("left = %v, want the occurrence in progress", left)
+	}
+}
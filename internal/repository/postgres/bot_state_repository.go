@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/pkg/logger"
+)
+
+type botStateRepositoryPG struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+func NewBotStateRepositoryPG(db *sqlx.DB, l logger.Logger) *botStateRepositoryPG {
+	return &botStateRepositoryPG{db: db, log: l}
+}
+
+func (r *botStateRepositoryPG) RegisterGroupChat(ctx context.Context, chatID int64, by domain.UserID) error {
+	r.log.Debug("Registering group chat", "chat_id", chatID, "by", by)
+	if _, err := r.db.ExecContext(ctx, qRegisterGroupChat, chatID, int64(by)); err != nil {
+		return fmt.Errorf("failed to register group chat: %w", err)
+	}
+	return nil
+}
+
+func (r *botStateRepositoryPG) ListGroupChats(ctx context.Context) ([]int64, error) {
+	var ids []int64
+	if err := r.db.SelectContext(ctx, &ids, qListGroupChats); err != nil {
+		return nil, fmt.Errorf("failed to list group chats: %w", err)
+	}
+	return ids, nil
+}
+
+func (r *botStateRepositoryPG) SaveScheduleMessage(ctx context.Context, chatID int64, day time.Time, messageID int) error {
+	if _, err := r.db.ExecContext(ctx, qUpsertScheduleMessage, chatID, day.Format(time.DateOnly), int64(messageID)); err != nil {
+		return fmt.Errorf("failed to save schedule message: %w", err)
+	}
+	return nil
+}
+
+func (r *botStateRepositoryPG) GetScheduleMessage(ctx context.Context, chatID int64, day time.Time) (int, error) {
+	var id int64
+	if err := r.db.GetContext(ctx, &id, qSelectScheduleMessage, chatID, day.Format(time.DateOnly)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, domain.ErrRecordNotFound
+		}
+		return 0, err
+	}
+	return int(id), nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/pkg/logger"
+)
+
+type clientRepositoryPG struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+func NewClientRepositoryPG(db *sqlx.DB, l logger.Logger) *clientRepositoryPG {
+	return &clientRepositoryPG{db: db, log: l}
+}
+
+type clientRow struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	NormName  string    `db:"norm_name"`
+	CreatedAt time.Time `db:"created_at"`
+	Records   int       `db:"records"`
+}
+
+func (r clientRow) toDomain() domain.Client {
+	return domain.Client{
+		ID:        domain.ClientID(r.ID),
+		Name:      r.Name,
+		NormName:  r.NormName,
+		CreatedAt: r.CreatedAt,
+		Records:   r.Records,
+	}
+}
+
+// nullClientID — NULL в client_id для записи без доверителя.
+func nullClientID(id domain.ClientID) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(id), Valid: id != 0}
+}
+
+func (r *clientRepositoryPG) EnsureClient(ctx context.Context, name, normName string) (domain.Client, error) {
+	var row clientRow
+	if err := r.db.GetContext(ctx, &row, qEnsureClient, name, normName); err != nil {
+		return domain.Client{}, fmt.Errorf("failed to ensure client: %w", err)
+	}
+	return row.toDomain(), nil
+}
+
+func (r *clientRepositoryPG) GetClient(ctx context.Context, id domain.ClientID) (domain.Client, error) {
+	var row clientRow
+	if err := r.db.GetContext(ctx, &row, qSelectClientByID, int64(id)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Client{}, domain.ErrRecordNotFound
+		}
+		return domain.Client{}, err
+	}
+	return row.toDomain(), nil
+}
+
+func (r *clientRepositoryPG) SuggestClients(ctx context.Context, normName string, limit int) ([]domain.Client, error) {
+	var rows []clientRow
+	if err := r.db.SelectContext(ctx, &rows, qSuggestClients, normName, limit); err != nil {
+		return nil, fmt.Errorf("failed to suggest clients: %w", err)
+	}
+	out := make([]domain.Client, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, row.toDomain())
+	}
+	return out, nil
+}
+
+func (r *clientRepositoryPG) LinkLog(ctx context.Context, kind domain.LogKind, recordID int64, id domain.ClientID) error {
+	query := qLinkSoglashenieClient
+	if kind == domain.LogKindZapros {
+		query = qLinkZaprosClient
+	}
+	if _, err := r.db.ExecContext(ctx, query, recordID, nullClientID(id)); err != nil {
+		return fmt.Errorf("failed to link log client: %w", err)
+	}
+	return nil
+}
+
+func (r *clientRepositoryPG) MergeClients(ctx context.Context, from, to domain.ClientID) (int64, error) {
+	r.log.Debug("Merging clients", "from", from, "to", to)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	var moved int64
+	for _, query := range []string{qMoveSoglasheniyaClient, qMoveZaprosyClient} {
+		res, err := tx.ExecContext(ctx, query, int64(from), int64(to))
+		if err != nil {
+			return 0, fmt.Errorf("failed to move client records: %w", mapPgErr(err))
+		}
+		n, _ := res.RowsAffected()
+		moved += n
+	}
+
+	res, err := tx.ExecContext(ctx, qDeleteClient, int64(from))
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete merged client: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return 0, domain.ErrRecordNotFound
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return moved, nil
+}
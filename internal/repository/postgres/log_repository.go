@@ -9,6 +9,7 @@ import (
 	"github.com/jmoiron/sqlx"
 	"github.com/leegeev/KomaevBookingBot/internal/domain"
 	"github.com/leegeev/KomaevBookingBot/pkg/logger"
+	"strings"
 	"time"
 )
 
@@ -32,6 +33,12 @@ type soglashenieRow struct {
 	Doveritel string    `db:"doveritel"`
 	Comment   string    `db:"comment"`
 	CreatedAt time.Time `db:"created_at"`
+
+	UpdatedAt  sql.NullTime `db:"updated_at"`
+	VoidedAt   sql.NullTime `db:"voided_at"`
+	VoidReason string       `db:"void_reason"`
+	RegNumber  string       `db:"reg_number"`
+	ClientID   int64        `db:"client_id"`
 }
 
 type zaprosRow struct {
@@ -42,14 +49,25 @@ type zaprosRow struct {
 	Doveritel string    `db:"doveritel"`
 	Comment   string    `db:"comment"`
 	CreatedAt time.Time `db:"created_at"`
+
+	UpdatedAt  sql.NullTime `db:"updated_at"`
+	VoidedAt   sql.NullTime `db:"voided_at"`
+	VoidReason string       `db:"void_reason"`
+	RegNumber  string       `db:"reg_number"`
+	ClientID   int64        `db:"client_id"`
 }
 
 type userRow struct {
 	ID        int64     `db:"id"`
 	FIO       string    `db:"fio"`
+	Active    bool      `db:"active"`
 	CreatedAt time.Time `db:"created_at"`
 }
 
+func (u userRow) toDomain() domain.User {
+	return domain.User{ID: u.ID, FIO: u.FIO, Active: u.Active, CreatedAt: u.CreatedAt}
+}
+
 // Получить пользователя по ID
 func (r *logRepositoryPG) GetUser(ctx context.Context, id int64) (domain.User, error) {
 	var u userRow
@@ -59,11 +77,20 @@ func (r *logRepositoryPG) GetUser(ctx context.Context, id int64) (domain.User, e
 		}
 		return domain.User{}, err
 	}
-	return domain.User{
-		ID:        u.ID,
-		FIO:       u.FIO,
-		CreatedAt: u.CreatedAt,
-	}, nil
+	return u.toDomain(), nil
+}
+
+// Все адвокаты, сохранившие ФИО, по алфавиту
+func (r *logRepositoryPG) ListUsers(ctx context.Context) ([]domain.User, error) {
+	var rows []userRow
+	if err := r.db.SelectContext(ctx, &rows, qSelectUsers); err != nil {
+		return nil, fmt.Errorf("failed to select users: %w", err)
+	}
+	out := make([]domain.User, 0, len(rows))
+	for _, u := range rows {
+		out = append(out, u.toDomain())
+	}
+	return out, nil
 }
 
 // Создать (или обновить) пользователя
@@ -75,62 +102,120 @@ func (r *logRepositoryPG) CreateUser(ctx context.Context, id int64, fio string)
 	return nil
 }
 
+// Исправить ФИО адвоката. Записи хранят ФИО на момент создания и не меняются
+func (r *logRepositoryPG) UpdateUserFIO(ctx context.Context, id int64, fio string) error {
+	return r.updateUser(ctx, qUpdateUserFIO, id, fio)
+}
+
+// Отключить или включить адвоката
+func (r *logRepositoryPG) SetUserActive(ctx context.Context, id int64, active bool) error {
+	return r.updateUser(ctx, qSetUserActive, id, active)
+}
+
+func (r *logRepositoryPG) updateUser(ctx context.Context, query string, id int64, value any) error {
+	res, err := r.db.ExecContext(ctx, query, id, value)
+	if err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
 // ────────────────────────────────
 //         Create
 // ────────────────────────────────
 
-func (r *logRepositoryPG) CreateSoglashenie(ctx context.Context, s domain.Soglashenie) (int64, error) {
-	var newID int64
-	err := r.db.QueryRowxContext(ctx, qInsertSoglashenie,
-		int64(s.UserID), s.UserName, s.Date, s.Doveritel, s.Comment, s.CreatedAt,
-	).Scan(&newID)
-
+func (r *logRepositoryPG) CreateSoglashenie(ctx context.Context, s domain.Soglashenie, f domain.LogNumberFormat) (domain.Soglashenie, error) {
+	id, number, err := r.insertNumbered(ctx, domain.LogKindSogl, s.Date, f, qInsertSoglashenie,
+		int64(s.UserID), s.UserName, s.Date, s.Doveritel, s.Comment, s.CreatedAt, nullClientID(s.ClientID),
+	)
 	if err != nil {
-		return 0, mapPgErr(err)
+		return domain.Soglashenie{}, err
 	}
-	return newID, nil
+	s.ID = domain.SoglID(id)
+	s.RegNumber = number
+	return s, nil
 }
 
-func (r *logRepositoryPG) CreateZapros(ctx context.Context, z domain.Zapros) (int64, error) {
-	var newID int64
-	err := r.db.QueryRowxContext(ctx, qInsertZapros,
-		int64(z.UserID), z.UserName, z.Date, z.Doveritel, z.Comment, z.CreatedAt,
-	).Scan(&newID)
+func (r *logRepositoryPG) CreateZapros(ctx context.Context, z domain.Zapros, f domain.LogNumberFormat) (domain.Zapros, error) {
+	id, number, err := r.insertNumbered(ctx, domain.LogKindZapros, z.Date, f, qInsertZapros,
+		int64(z.UserID), z.UserName, z.Date, z.Doveritel, z.Comment, z.CreatedAt, nullClientID(z.ClientID),
+	)
+	if err != nil {
+		return domain.Zapros{}, err
+	}
+	z.ID = domain.ZaprosID(id)
+	z.RegNumber = number
+	return z, nil
+}
 
+// insertNumbered выделяет порядковый номер в году и вставляет запись в одной
+// транзакции: при ошибке вставки счётчик откатывается, и номер не теряется.
+// args — поля записи, номер добавляется к ним последними параметрами запроса.
+func (r *logRepositoryPG) insertNumbered(ctx context.Context, kind domain.LogKind, date time.Time, f domain.LogNumberFormat, query string, args ...any) (int64, string, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
-		return 0, mapPgErr(err)
+		return 0, "", err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	year := domain.RegYear(date)
+	var seq int
+	if err := tx.QueryRowxContext(ctx, qAllocateLogNumber, string(kind), year).Scan(&seq); err != nil {
+		return 0, "", fmt.Errorf("failed to allocate log number: %w", err)
+	}
+	number := f.Format(kind, year, seq)
+
+	var id int64
+	if err := tx.QueryRowxContext(ctx, query, append(args, year, seq, number)...).Scan(&id); err != nil {
+		return 0, "", mapPgErr(err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, "", err
 	}
-	return newID, nil
+	return id, number, nil
 }
 
 // ────────────────────────────────
 //         Get by UserID
 // ────────────────────────────────
 
-func (r *logRepositoryPG) GetSoglasheniyaByUserID(ctx context.Context, userID domain.UserID) ([]domain.Soglashenie, error) {
-	var rows []soglashenieRow
-	if err := r.db.SelectContext(ctx, &rows, qSelectSoglasheniyaByUser, int64(userID)); err != nil {
-		return nil, err
+func (r *logRepositoryPG) GetSoglasheniyaByUserID(ctx context.Context, userID domain.UserID, limit, offset int) ([]domain.Soglashenie, int, error) {
+	var rows []struct {
+		soglashenieRow
+		Total int `db:"total"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, qSelectSoglasheniyaByUser, int64(userID), limit, offset); err != nil {
+		return nil, 0, err
 	}
 
+	total := 0
 	out := make([]domain.Soglashenie, 0, len(rows))
 	for _, row := range rows {
-		out = append(out, soglashenieRowToDomain(row))
+		total = row.Total
+		out = append(out, soglashenieRowToDomain(row.soglashenieRow))
 	}
-	return out, nil
+	return out, total, nil
 }
 
-func (r *logRepositoryPG) GetZaprosiByUserID(ctx context.Context, userID domain.UserID) ([]domain.Zapros, error) {
-	var rows []zaprosRow
-	if err := r.db.SelectContext(ctx, &rows, qSelectZaprosyByUser, int64(userID)); err != nil {
-		return nil, err
+func (r *logRepositoryPG) GetZaprosiByUserID(ctx context.Context, userID domain.UserID, limit, offset int) ([]domain.Zapros, int, error) {
+	var rows []struct {
+		zaprosRow
+		Total int `db:"total"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, qSelectZaprosyByUser, int64(userID), limit, offset); err != nil {
+		return nil, 0, err
 	}
 
+	total := 0
 	out := make([]domain.Zapros, 0, len(rows))
 	for _, row := range rows {
-		out = append(out, zaprosRowToDomain(row))
+		total = row.Total
+		out = append(out, zaprosRowToDomain(row.zaprosRow))
 	}
-	return out, nil
+	return out, total, nil
 }
 
 // ────────────────────────────────
@@ -159,6 +244,70 @@ func (r *logRepositoryPG) GetZaprosByID(ctx context.Context, id int64) (domain.Z
 	return zaprosRowToDomain(row), nil
 }
 
+// GetLogByRegNumber ищет запись по году и порядковому номеру.
+func (r *logRepositoryPG) GetLogByRegNumber(ctx context.Context, kind domain.LogKind, year, seq int) (domain.LogRecord, error) {
+	switch kind {
+	case domain.LogKindSogl:
+		var row soglashenieRow
+		if err := r.db.GetContext(ctx, &row, qSelectSoglashenieByRegNumber, year, seq); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.LogRecord{}, domain.ErrRecordNotFound
+			}
+			return domain.LogRecord{}, err
+		}
+		return domain.SoglashenieRecord(soglashenieRowToDomain(row)), nil
+	case domain.LogKindZapros:
+		var row zaprosRow
+		if err := r.db.GetContext(ctx, &row, qSelectZaprosByRegNumber, year, seq); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return domain.LogRecord{}, domain.ErrRecordNotFound
+			}
+			return domain.LogRecord{}, err
+		}
+		return domain.ZaprosRecord(zaprosRowToDomain(row)), nil
+	default:
+		return domain.LogRecord{}, domain.ErrInvalidInputData
+	}
+}
+
+// BackfillRegNumbers проставляет reg_number записям, которым миграция выдала
+// только год и порядковый номер. Обе таблицы — в одной транзакции.
+func (r *logRepositoryPG) BackfillRegNumbers(ctx context.Context, f domain.LogNumberFormat) (int, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	total := 0
+	for _, t := range []struct {
+		kind             domain.LogKind
+		selectQ, updateQ string
+	}{
+		{domain.LogKindSogl, qSelectUnnumberedSoglasheniya, qSetSoglashenieRegNumber},
+		{domain.LogKindZapros, qSelectUnnumberedZaprosy, qSetZaprosRegNumber},
+	} {
+		var rows []struct {
+			ID   int64 `db:"id"`
+			Year int   `db:"reg_year"`
+			Seq  int   `db:"reg_seq"`
+		}
+		if err := tx.SelectContext(ctx, &rows, t.selectQ); err != nil {
+			return 0, fmt.Errorf("failed to select unnumbered %s: %w", t.kind, err)
+		}
+		for _, row := range rows {
+			if _, err := tx.ExecContext(ctx, t.updateQ, row.ID, f.Format(t.kind, row.Year, row.Seq)); err != nil {
+				return 0, fmt.Errorf("failed to set reg number for %s %d: %w", t.kind, row.ID, err)
+			}
+		}
+		total += len(rows)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
 // ────────────────────────────────
 //         Helpers
 // ────────────────────────────────
@@ -172,6 +321,12 @@ func soglashenieRowToDomain(r soglashenieRow) domain.Soglashenie {
 		Doveritel: r.Doveritel,
 		Comment:   r.Comment,
 		CreatedAt: r.CreatedAt,
+		RegNumber: r.RegNumber,
+		ClientID:  domain.ClientID(r.ClientID),
+
+		UpdatedAt:  r.UpdatedAt.Time,
+		VoidedAt:   r.VoidedAt.Time,
+		VoidReason: r.VoidReason,
 	}
 }
 
@@ -184,6 +339,12 @@ func zaprosRowToDomain(r zaprosRow) domain.Zapros {
 		Doveritel: r.Doveritel,
 		Comment:   r.Comment,
 		CreatedAt: r.CreatedAt,
+		RegNumber: r.RegNumber,
+		ClientID:  domain.ClientID(r.ClientID),
+
+		UpdatedAt:  r.UpdatedAt.Time,
+		VoidedAt:   r.VoidedAt.Time,
+		VoidReason: r.VoidReason,
 	}
 }
 
@@ -200,11 +361,21 @@ func mapPgErr(err error) error {
 	return err
 }
 
-// GetSoglasheniyaAfterDate возвращает соглашения, созданные с заданной даты до текущего момента.
-func (r *logRepositoryPG) GetSoglasheniyaAfterDate(ctx context.Context, date time.Time) ([]domain.Soglashenie, error) {
+// reportBound — граница периода выгрузки; нулевая дата — без ограничения.
+func reportBound(t time.Time, open string) string {
+	if t.IsZero() {
+		return open
+	}
+	return t.Format(time.DateOnly)
+}
+
+// GetSoglasheniyaForReport возвращает соглашения за период по дате записи.
+func (r *logRepositoryPG) GetSoglasheniyaForReport(ctx context.Context, q domain.ReportQuery) ([]domain.Soglashenie, error) {
 	var rows []soglashenieRow
-	if err := r.db.SelectContext(ctx, &rows, qSelectSoglasheniyaAfterDate, date); err != nil {
-		return nil, fmt.Errorf("failed to select soglasheniya after date: %w", err)
+	err := r.db.SelectContext(ctx, &rows, qSelectSoglasheniyaForReport,
+		reportBound(q.From, "-infinity"), reportBound(q.To, "infinity"), int64(q.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to select soglasheniya for report: %w", err)
 	}
 
 	out := make([]domain.Soglashenie, 0, len(rows))
@@ -214,11 +385,13 @@ func (r *logRepositoryPG) GetSoglasheniyaAfterDate(ctx context.Context, date tim
 	return out, nil
 }
 
-// GetZaprosiAfterDate возвращает запросы, созданные с заданной даты до текущего момента.
-func (r *logRepositoryPG) GetZaprosiAfterDate(ctx context.Context, date time.Time) ([]domain.Zapros, error) {
+// GetZaprosiForReport возвращает запросы за период по дате записи.
+func (r *logRepositoryPG) GetZaprosiForReport(ctx context.Context, q domain.ReportQuery) ([]domain.Zapros, error) {
 	var rows []zaprosRow
-	if err := r.db.SelectContext(ctx, &rows, qSelectZaprosyAfterDate, date); err != nil {
-		return nil, fmt.Errorf("failed to select zaprosy after date: %w", err)
+	err := r.db.SelectContext(ctx, &rows, qSelectZaprosyForReport,
+		reportBound(q.From, "-infinity"), reportBound(q.To, "infinity"), int64(q.UserID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to select zaprosy for report: %w", err)
 	}
 
 	out := make([]domain.Zapros, 0, len(rows))
@@ -227,3 +400,278 @@ func (r *logRepositoryPG) GetZaprosiAfterDate(ctx context.Context, date time.Tim
 	}
 	return out, nil
 }
+
+// ────────────────────────────────
+//         Поиск
+// ────────────────────────────────
+
+type logSearchRow struct {
+	Kind      string    `db:"kind"`
+	ID        int64     `db:"id"`
+	UserID    int64     `db:"user_id"`
+	UserName  string    `db:"user_name"`
+	Date      time.Time `db:"date"`
+	Doveritel string    `db:"doveritel"`
+	Comment   string    `db:"comment"`
+	CreatedAt time.Time `db:"created_at"`
+
+	UpdatedAt  sql.NullTime `db:"updated_at"`
+	VoidedAt   sql.NullTime `db:"voided_at"`
+	VoidReason string       `db:"void_reason"`
+	RegNumber  string       `db:"reg_number"`
+	ClientID   int64        `db:"client_id"`
+
+	Total int `db:"total"`
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// Search ищет записи по словам в доверителе и комментарии. Условия
+// одинаковы для обеих таблиц, поэтому параметры общие.
+func (r *logRepositoryPG) Search(ctx context.Context, f domain.LogSearchFilter) ([]domain.LogRecord, int, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	for _, w := range f.Words() {
+		conds = append(conds, fmt.Sprintf(qSearchText, arg("%"+likeEscaper.Replace(w)+"%")))
+	}
+	if !f.From.IsZero() {
+		conds = append(conds, "date >= "+arg(f.From.Format(time.DateOnly)))
+	}
+	if !f.To.IsZero() {
+		conds = append(conds, "date <= "+arg(f.To.Format(time.DateOnly)))
+	}
+	if f.UserID != 0 {
+		conds = append(conds, "user_id = "+arg(int64(f.UserID)))
+	}
+	if len(conds) == 0 {
+		conds = append(conds, "TRUE")
+	}
+	where := strings.Join(conds, " AND ")
+
+	var parts []string
+	if f.Kind == "" || f.Kind == domain.LogKindSogl {
+		parts = append(parts, fmt.Sprintf(qSearchSoglasheniya, where))
+	}
+	if f.Kind == "" || f.Kind == domain.LogKindZapros {
+		parts = append(parts, fmt.Sprintf(qSearchZaprosy, where))
+	}
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	query := fmt.Sprintf(qSearchLogs, strings.Join(parts, "\n\t\tUNION ALL"), limit, max(f.Offset, 0))
+
+	var rows []logSearchRow
+	if err := r.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, 0, fmt.Errorf("search logs: %w", err)
+	}
+
+	total := 0
+	out := make([]domain.LogRecord, 0, len(rows))
+	for _, row := range rows {
+		total = row.Total
+		out = append(out, domain.LogRecord{
+			Kind:      domain.LogKind(row.Kind),
+			ID:        row.ID,
+			UserID:    domain.UserID(row.UserID),
+			UserName:  row.UserName,
+			Doveritel: row.Doveritel,
+			Comment:   row.Comment,
+			Date:      row.Date,
+			RegNumber: row.RegNumber,
+			ClientID:  domain.ClientID(row.ClientID),
+			CreatedAt: row.CreatedAt,
+
+			UpdatedAt:  row.UpdatedAt.Time,
+			VoidedAt:   row.VoidedAt.Time,
+			VoidReason: row.VoidReason,
+		})
+	}
+	return out, total, nil
+}
+
+// ────────────────────────────────
+//         Правка и аннулирование
+// ────────────────────────────────
+
+type logRevisionRow struct {
+	ID        int64     `db:"id"`
+	Kind      string    `db:"kind"`
+	RecordID  int64     `db:"record_id"`
+	ActorID   int64     `db:"actor_id"`
+	ActorName string    `db:"actor_name"`
+	Action    string    `db:"action"`
+	Field     string    `db:"field"`
+	OldValue  string    `db:"old_value"`
+	NewValue  string    `db:"new_value"`
+	Reason    string    `db:"reason"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (r *logRepositoryPG) UpdateLogField(ctx context.Context, rev domain.LogRevision) error {
+	var query string
+	switch {
+	case rev.Kind == domain.LogKindSogl && rev.Field == domain.LogFieldDoveritel:
+		query = qUpdateSoglashenieDoveritel
+	case rev.Kind == domain.LogKindSogl && rev.Field == domain.LogFieldComment:
+		query = qUpdateSoglashenieComment
+	case rev.Kind == domain.LogKindZapros && rev.Field == domain.LogFieldDoveritel:
+		query = qUpdateZaprosDoveritel
+	case rev.Kind == domain.LogKindZapros && rev.Field == domain.LogFieldComment:
+		query = qUpdateZaprosComment
+	default:
+		return domain.ErrInvalidInputData
+	}
+	return r.applyRevision(ctx, query, rev.NewValue, rev)
+}
+
+func (r *logRepositoryPG) VoidLog(ctx context.Context, rev domain.LogRevision) error {
+	query := qVoidZapros
+	if rev.Kind == domain.LogKindSogl {
+		query = qVoidSoglashenie
+	}
+	return r.applyRevision(ctx, query, rev.Reason, rev)
+}
+
+// applyRevision меняет запись и добавляет строку истории в одной транзакции.
+func (r *logRepositoryPG) applyRevision(ctx context.Context, query, value string, rev domain.LogRevision) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, query, rev.RecordID, value)
+	if err != nil {
+		return fmt.Errorf("update log record: %w", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return domain.ErrRecordVoided
+	}
+
+	if _, err := tx.ExecContext(ctx, qInsertLogRevision,
+		string(rev.Kind),
+		rev.RecordID,
+		int64(rev.ActorID),
+		rev.ActorName,
+		string(rev.Action),
+		string(rev.Field),
+		rev.OldValue,
+		rev.NewValue,
+		rev.Reason,
+	); err != nil {
+		return fmt.Errorf("insert log revision: %w", err)
+	}
+	return tx.Commit()
+}
+
+func (r *logRepositoryPG) ListRevisions(ctx context.Context, kind domain.LogKind, recordID int64) ([]domain.LogRevision, error) {
+	var rows []logRevisionRow
+	if err := r.db.SelectContext(ctx, &rows, qSelectLogRevisions, string(kind), recordID); err != nil {
+		return nil, err
+	}
+	out := make([]domain.LogRevision, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, domain.LogRevision{
+			ID:        row.ID,
+			Kind:      domain.LogKind(row.Kind),
+			RecordID:  row.RecordID,
+			ActorID:   domain.UserID(row.ActorID),
+			ActorName: row.ActorName,
+			Action:    domain.LogRevisionAction(row.Action),
+			Field:     domain.LogField(row.Field),
+			OldValue:  row.OldValue,
+			NewValue:  row.NewValue,
+			Reason:    row.Reason,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+// ────────────────────────────────
+//         Вложения
+// ────────────────────────────────
+
+type logAttachmentRow struct {
+	ID           int64     `db:"id"`
+	Kind         string    `db:"kind"`
+	RecordID     int64     `db:"record_id"`
+	FileType     string    `db:"file_type"`
+	FileID       string    `db:"file_id"`
+	FileUniqueID string    `db:"file_unique_id"`
+	FileName     string    `db:"file_name"`
+	MimeType     string    `db:"mime_type"`
+	FileSize     int64     `db:"file_size"`
+	UploadedBy   int64     `db:"uploaded_by"`
+	CreatedAt    time.Time `db:"created_at"`
+}
+
+func (r *logRepositoryPG) AddLogAttachment(ctx context.Context, a domain.LogAttachment) (domain.LogAttachment, error) {
+	err := r.db.QueryRowxContext(ctx, qInsertLogAttachment,
+		string(a.Kind), a.RecordID, string(a.Type), a.FileID, a.FileUniqueID,
+		a.FileName, a.MimeType, a.FileSize, int64(a.UploadedBy),
+	).Scan(&a.ID, &a.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.LogAttachment{}, domain.ErrAttachmentExists
+	}
+	if err != nil {
+		return domain.LogAttachment{}, fmt.Errorf("failed to insert log attachment: %w", err)
+	}
+	return a, nil
+}
+
+func (r *logRepositoryPG) ListLogAttachments(ctx context.Context, kind domain.LogKind, recordID int64) ([]domain.LogAttachment, error) {
+	var rows []logAttachmentRow
+	if err := r.db.SelectContext(ctx, &rows, qSelectLogAttachments, string(kind), recordID); err != nil {
+		return nil, fmt.Errorf("failed to select log attachments: %w", err)
+	}
+	out := make([]domain.LogAttachment, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, domain.LogAttachment{
+			ID:           row.ID,
+			Kind:         domain.LogKind(row.Kind),
+			RecordID:     row.RecordID,
+			Type:         domain.AttachmentType(row.FileType),
+			FileID:       row.FileID,
+			FileUniqueID: row.FileUniqueID,
+			FileName:     row.FileName,
+			MimeType:     row.MimeType,
+			FileSize:     row.FileSize,
+			UploadedBy:   domain.UserID(row.UploadedBy),
+			CreatedAt:    row.CreatedAt,
+		})
+	}
+	return out, nil
+}
+
+func (r *logRepositoryPG) CountLogAttachments(ctx context.Context, kind domain.LogKind, ids []int64) (map[int64]int, error) {
+	out := make(map[int64]int)
+	if len(ids) == 0 {
+		return out, nil
+	}
+
+	var rows []struct {
+		RecordID int64 `db:"record_id"`
+		Count    int   `db:"count"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, qCountLogAttachments, string(kind), ids); err != nil {
+		return nil, fmt.Errorf("failed to count log attachments: %w", err)
+	}
+	for _, row := range rows {
+		out[row.RecordID] = row.Count
+	}
+	return out, nil
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/internal/repository/postgres/pgtest"
+)
+
+// Telegram ID новых аккаунтов не помещаются в int4.
+const bigUserID = 7_123_456_789
+
+func TestLogReportFilterByBigUserID(t *testing.T) {
+	ctx := context.Background()
+	repo := NewLogRepositoryPG(pgtest.Open(t), pgtest.Logger())
+	numFmt := domain.LogNumberFormat{Pattern: domain.DefaultLogNumberPattern, Width: domain.DefaultLogNumberWidth}
+
+	date := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	for _, u := range []struct {
+		id  int64
+		fio string
+	}{{bigUserID, "Иванов Иван"}, {42, "Петров Пётр"}} {
+		if err := repo.CreateUser(ctx, u.id, u.fio); err != nil {
+			t.Fatalf("CreateUser(%d): %v", u.id, err)
+		}
+		if _, err := repo.CreateSoglashenie(ctx, domain.Soglashenie{
+			UserID: domain.UserID(u.id), UserName: u.fio, Date: date, Doveritel: "Ромашка", CreatedAt: time.Now(),
+		}, numFmt); err != nil {
+			t.Fatalf("CreateSoglashenie(%d): %v", u.id, err)
+		}
+		if _, err := repo.CreateZapros(ctx, domain.Zapros{
+			UserID: domain.UserID(u.id), UserName: u.fio, Date: date, Doveritel: "Ромашка", CreatedAt: time.Now(),
+		}, numFmt); err != nil {
+			t.Fatalf("CreateZapros(%d): %v", u.id, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		userID domain.UserID
+		want   int
+	}{
+		{"64-bit author", bigUserID, 1},
+		{"small author", 42, 1},
+		{"all authors", 0, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := domain.ReportQuery{UserID: tt.userID}
+
+			sogl, err := repo.GetSoglasheniyaForReport(ctx, q)
+			if err != nil {
+				t.Fatalf("GetSoglasheniyaForReport: %v", err)
+			}
+			zap, err := repo.GetZaprosiForReport(ctx, q)
+			if err != nil {
+				t.Fatalf("GetZaprosiForReport: %v", err)
+			}
+			if len(sogl) != tt.want || len(zap) != tt.want {
+				t.Fatalf("got %d soglasheniya and %d zaprosy, want %d each", len(sogl), len(zap), tt.want)
+			}
+			for _, s := range sogl {
+				if tt.userID != 0 && s.UserID != tt.userID {
+					t.Errorf("soglashenie %d of user %d in report for %d", s.ID, s.UserID, tt.userID)
+				}
+			}
+		})
+	}
+}
@@ -18,8 +18,26 @@ const qSchedule = `
 // BOOKING REPOSITORY QUERIES
 
 const qInsertBooking = `
-INSERT INTO bookings (room_id, room_name, user_id, user_name, time_range)
-VALUES ($1, $2, $3, $4, tstzrange($5, $6, '[)'))
+INSERT INTO bookings (room_id, room_name, user_id, user_name, time_range, series_id, note)
+VALUES ($1, $2, $3, $4, tstzrange($5, $6, '[)'), $7, $8)
+RETURNING id;
+`
+
+const qInsertAttendee = `
+INSERT INTO booking_attendees (booking_id, user_id, name)
+VALUES ($1, $2, $3);
+`
+
+// Один UPDATE — одна транзакция: при срабатывании bookings_no_overlap
+// старый интервал остаётся на месте.
+const qUpdateBookingRange = `
+UPDATE bookings
+SET room_id = $2,
+    room_name = $3,
+    time_range = tstzrange($4, $5, '[)'),
+    reminded_at = NULL,
+    checked_in_at = NULL
+WHERE id = $1
 RETURNING id;
 `
 
@@ -37,6 +55,14 @@ SELECT
   user_name,
   lower(time_range) AS start_utc,
   upper(time_range) AS end_utc,
+  series_id,
+  note,
+  COALESCE((
+    SELECT json_agg(json_build_object('user_id', a.user_id, 'name', a.name) ORDER BY a.id)
+    FROM booking_attendees a
+    WHERE a.booking_id = bookings.id
+  ), '[]')::text AS attendees,
+  checked_in_at,
   created_at
 FROM bookings
 WHERE id = $1;
@@ -51,6 +77,14 @@ SELECT
   user_name,
   lower(time_range) AS start_utc,
   upper(time_range) AS end_utc,
+  series_id,
+  note,
+  COALESCE((
+    SELECT json_agg(json_build_object('user_id', a.user_id, 'name', a.name) ORDER BY a.id)
+    FROM booking_attendees a
+    WHERE a.booking_id = bookings.id
+  ), '[]')::text AS attendees,
+  checked_in_at,
   created_at
 FROM bookings
 WHERE room_id = $1
@@ -68,6 +102,14 @@ SELECT
   user_name,
   lower(time_range) AS start_utc,
   upper(time_range) AS end_utc,
+  series_id,
+  note,
+  COALESCE((
+    SELECT json_agg(json_build_object('user_id', a.user_id, 'name', a.name) ORDER BY a.id)
+    FROM booking_attendees a
+    WHERE a.booking_id = bookings.id
+  ), '[]')::text AS attendees,
+  checked_in_at,
   created_at
 FROM bookings
 WHERE user_id = $1
@@ -89,6 +131,123 @@ DELETE FROM bookings
 WHERE upper(time_range) < $1;
 `
 
+// REMINDERS
+
+// Напоминание «забирается» UPDATE-ом до отправки: SKIP LOCKED и отметка
+// reminded_at гарантируют, что бронь не вернётся повторно даже после рестарта.
+// Брони, созданные уже внутри окна напоминания, пропускаем.
+const qClaimDueReminders = `
+WITH due AS (
+  SELECT b.id
+  FROM bookings b
+  LEFT JOIN user_settings us ON us.user_id = b.user_id
+  WHERE b.reminded_at IS NULL
+    AND lower(b.time_range) > $1
+    AND COALESCE(us.remind_before_minutes, $2) > 0
+    AND lower(b.time_range) <= $1 + make_interval(mins => COALESCE(us.remind_before_minutes, $2))
+    AND b.created_at < lower(b.time_range) - make_interval(mins => COALESCE(us.remind_before_minutes, $2))
+  FOR UPDATE OF b SKIP LOCKED
+)
+UPDATE bookings
+SET reminded_at = $1
+FROM due
+WHERE bookings.id = due.id
+RETURNING
+  bookings.id,
+  bookings.room_id,
+  bookings.room_name,
+  bookings.user_id,
+  bookings.user_name,
+  lower(bookings.time_range) AS start_utc,
+  upper(bookings.time_range) AS end_utc,
+  bookings.series_id,
+  bookings.note,
+  COALESCE((
+    SELECT json_agg(json_build_object('user_id', a.user_id, 'name', a.name) ORDER BY a.id)
+    FROM booking_attendees a
+    WHERE a.booking_id = bookings.id
+  ), '[]')::text AS attendees,
+  bookings.created_at;
+`
+
+const qReleaseReminder = `
+UPDATE bookings
+SET reminded_at = NULL
+WHERE id = $1;
+`
+
+// CHECK-IN
+
+const qCheckIn = `
+UPDATE bookings
+SET checked_in_at = COALESCE(checked_in_at, $2)
+WHERE id = $1
+  AND upper(time_range) > $2;
+`
+
+// Отсчёт grace идёт от начала брони, а для броней, созданных уже после
+// начала, — от момента создания. SKIP LOCKED не даёт двум тикам
+// освободить одну бронь дважды.
+const qReleaseNoShows = `
+WITH due AS (
+  SELECT id, upper(time_range) AS original_end
+  FROM bookings
+  WHERE checked_in_at IS NULL
+    AND upper(time_range) > $1
+    AND lower(time_range) <= $1 - make_interval(secs => $2)
+    AND created_at <= $1 - make_interval(secs => $2)
+  FOR UPDATE SKIP LOCKED
+)
+UPDATE bookings
+SET time_range = tstzrange(lower(bookings.time_range), $1, '[)')
+FROM due
+WHERE bookings.id = due.id
+RETURNING
+  bookings.id,
+  bookings.room_id,
+  bookings.room_name,
+  bookings.user_id,
+  bookings.user_name,
+  lower(bookings.time_range) AS start_utc,
+  upper(bookings.time_range) AS end_utc,
+  bookings.series_id,
+  bookings.note,
+  COALESCE((
+    SELECT json_agg(json_build_object('user_id', a.user_id, 'name', a.name) ORDER BY a.id)
+    FROM booking_attendees a
+    WHERE a.booking_id = bookings.id
+  ), '[]')::text AS attendees,
+  bookings.created_at,
+  due.original_end;
+`
+
+// SERIES
+
+const qInsertSeries = `
+INSERT INTO booking_series (room_id, room_name, user_id, user_name, freq, count, until)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id;
+`
+
+const (
+	qSavepointOccurrence  = `SAVEPOINT occurrence;`
+	qRollbackToOccurrence = `ROLLBACK TO SAVEPOINT occurrence;`
+	qReleaseOccurrence    = `RELEASE SAVEPOINT occurrence;`
+)
+
+const qSelectSeriesByID = `
+SELECT id, room_id, room_name, user_id, user_name, freq, count, until
+FROM booking_series
+WHERE id = $1;
+`
+
+// Прошедшие и уже идущие повторения оставляем, удаляем только будущие.
+const qDeleteSeriesFrom = `
+DELETE FROM bookings
+WHERE series_id = $1
+  AND lower(time_range) > $2;
+`
+
 // ROOM REPOSITORY QUERIES
 
 const qInsertRoom = `
@@ -131,45 +290,94 @@ WHERE id = $1;
 // LOGS
 const (
 	qInsertSoglashenie = `
-		INSERT INTO soglasheniya (user_id, user_name, date, doveritel, comment, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO soglasheniya (user_id, user_name, date, doveritel, comment, created_at,
+		                          client_id, reg_year, reg_seq, reg_number)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id;
 	`
 
 	qInsertZapros = `
-		INSERT INTO zaprosy (user_id, user_name, date, doveritel, comment, created_at)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO zaprosy (user_id, user_name, date, doveritel, comment, created_at,
+		                     client_id, reg_year, reg_seq, reg_number)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 		RETURNING id;
 	`
 
+	// Страница записей пользователя, новые сначала; total — всего записей.
 	qSelectSoglasheniyaByUser = `
-		SELECT id, user_id, user_name, date, doveritel, comment, created_at
+		SELECT id, user_id, user_name, date, doveritel, comment, created_at,
+		       updated_at, voided_at, coalesce(void_reason, '') AS void_reason, reg_number,
+		       coalesce(client_id, 0) AS client_id,
+		       count(*) OVER() AS total
 		FROM soglasheniya
 		WHERE user_id = $1
 		ORDER BY id DESC
-    LIMIT 5;
+		LIMIT $2 OFFSET $3;
 	`
 
+	// Страница записей пользователя, новые сначала; total — всего записей.
 	qSelectZaprosyByUser = `
-		SELECT id, user_id, user_name, date, doveritel, comment, created_at
+		SELECT id, user_id, user_name, date, doveritel, comment, created_at,
+		       updated_at, voided_at, coalesce(void_reason, '') AS void_reason, reg_number,
+		       coalesce(client_id, 0) AS client_id,
+		       count(*) OVER() AS total
 		FROM zaprosy
 		WHERE user_id = $1
 		ORDER BY id DESC
-    LIMIT 5;
+		LIMIT $2 OFFSET $3;
 	`
 
 	qSelectSoglashenieByID = `
-		SELECT id, user_id, user_name, date, doveritel, comment, created_at
+		SELECT id, user_id, user_name, date, doveritel, comment, created_at,
+		       updated_at, voided_at, coalesce(void_reason, '') AS void_reason, reg_number,
+		       coalesce(client_id, 0) AS client_id
 		FROM soglasheniya
 		WHERE id = $1;
 	`
 
 	qSelectZaprosByID = `
-		SELECT id, user_id, user_name, date, doveritel, comment, created_at
+		SELECT id, user_id, user_name, date, doveritel, comment, created_at,
+		       updated_at, voided_at, coalesce(void_reason, '') AS void_reason, reg_number,
+		       coalesce(client_id, 0) AS client_id
 		FROM zaprosy
 		WHERE id = $1;
 	`
 
+	// Следующий порядковый номер в году. Строка счётчика блокируется до конца
+	// транзакции, так что параллельные вставки получают номера по очереди.
+	qAllocateLogNumber = `
+		INSERT INTO log_counters (kind, year, last_seq)
+		VALUES ($1, $2, 1)
+		ON CONFLICT (kind, year) DO UPDATE SET last_seq = log_counters.last_seq + 1
+		RETURNING last_seq;
+	`
+
+	// Записи без номера (пронумерованы миграцией 011) — номер собирается в Go
+	qSelectUnnumberedSoglasheniya = `
+		SELECT id, reg_year, reg_seq FROM soglasheniya WHERE reg_number IS NULL FOR UPDATE;
+	`
+	qSelectUnnumberedZaprosy = `
+		SELECT id, reg_year, reg_seq FROM zaprosy WHERE reg_number IS NULL FOR UPDATE;
+	`
+	qSetSoglashenieRegNumber = `UPDATE soglasheniya SET reg_number = $2 WHERE id = $1;`
+	qSetZaprosRegNumber      = `UPDATE zaprosy SET reg_number = $2 WHERE id = $1;`
+
+	qSelectSoglashenieByRegNumber = `
+		SELECT id, user_id, user_name, date, doveritel, comment, created_at,
+		       updated_at, voided_at, coalesce(void_reason, '') AS void_reason, reg_number,
+		       coalesce(client_id, 0) AS client_id
+		FROM soglasheniya
+		WHERE reg_year = $1 AND reg_seq = $2;
+	`
+
+	qSelectZaprosByRegNumber = `
+		SELECT id, user_id, user_name, date, doveritel, comment, created_at,
+		       updated_at, voided_at, coalesce(void_reason, '') AS void_reason, reg_number,
+		       coalesce(client_id, 0) AS client_id
+		FROM zaprosy
+		WHERE reg_year = $1 AND reg_seq = $2;
+	`
+
 	qInsertUser = `
 		INSERT INTO users (id, fio)
 		VALUES ($1, $2)
@@ -177,23 +385,285 @@ const (
 	`
 
 	qSelectUserByID = `
-		SELECT id, fio, created_at
+		SELECT id, fio, active, created_at
 		FROM users
 		WHERE id = $1;
 	`
-	qSelectSoglasheniyaAfterDate = `
-		SELECT id, user_id, user_name, date, doveritel, comment, created_at
+
+	qUpdateUserFIO = `
+		UPDATE users SET fio = $2, updated_at = now()
+		WHERE id = $1;
+	`
+
+	qSetUserActive = `
+		UPDATE users SET active = $2, updated_at = now()
+		WHERE id = $1;
+	`
+	// Выгрузка за период по дате записи; $3 = 0 — все авторы. Приведение к
+	// bigint обязательно: иначе $3 выводится как int4 из-за сравнения с 0, и
+	// Telegram ID больше 2^31 не передаётся.
+	qSelectSoglasheniyaForReport = `
+		SELECT id, user_id, user_name, date, doveritel, comment, created_at,
+		       updated_at, voided_at, coalesce(void_reason, '') AS void_reason, reg_number,
+		       coalesce(client_id, 0) AS client_id
 		FROM soglasheniya
-		WHERE created_at >= $1 AND created_at <= NOW()
-		ORDER BY id DESC;
+		WHERE date BETWEEN $1 AND $2 AND ($3::bigint = 0 OR user_id = $3::bigint)
+		ORDER BY reg_year, reg_seq;
 	`
 
-	qSelectZaprosyAfterDate = `
-		SELECT id, user_id, user_name, date, doveritel, comment, created_at
+	qSelectZaprosyForReport = `
+		SELECT id, user_id, user_name, date, doveritel, comment, created_at,
+		       updated_at, voided_at, coalesce(void_reason, '') AS void_reason, reg_number,
+		       coalesce(client_id, 0) AS client_id
 		FROM zaprosy
-		WHERE created_at >= $1 AND created_at <= NOW()
-		ORDER BY id DESC;
+		WHERE date BETWEEN $1 AND $2 AND ($3::bigint = 0 OR user_id = $3::bigint)
+		ORDER BY reg_year, reg_seq;
+	`
+
+	qSelectUsers = `
+		SELECT id, fio, active, created_at
+		FROM users
+		ORDER BY fio;
+	`
+)
+
+// Правка и аннулирование записей. Поле выбирается в репозитории из
+// фиксированного набора, поэтому у каждой таблицы свои запросы.
+const (
+	qUpdateSoglashenieDoveritel = `
+		UPDATE soglasheniya SET doveritel = $2, updated_at = now()
+		WHERE id = $1 AND voided_at IS NULL;
+	`
+	qUpdateSoglashenieComment = `
+		UPDATE soglasheniya SET comment = $2, updated_at = now()
+		WHERE id = $1 AND voided_at IS NULL;
+	`
+	qUpdateZaprosDoveritel = `
+		UPDATE zaprosy SET doveritel = $2, updated_at = now()
+		WHERE id = $1 AND voided_at IS NULL;
+	`
+	qUpdateZaprosComment = `
+		UPDATE zaprosy SET comment = $2, updated_at = now()
+		WHERE id = $1 AND voided_at IS NULL;
+	`
+	qVoidSoglashenie = `
+		UPDATE soglasheniya SET voided_at = now(), void_reason = $2
+		WHERE id = $1 AND voided_at IS NULL;
+	`
+	qVoidZapros = `
+		UPDATE zaprosy SET voided_at = now(), void_reason = $2
+		WHERE id = $1 AND voided_at IS NULL;
+	`
+
+	qInsertLogRevision = `
+		INSERT INTO log_revisions (kind, record_id, actor_id, actor_name, action, field, old_value, new_value, reason)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);
+	`
+
+	qSelectLogRevisions = `
+		SELECT id, kind, record_id, actor_id, actor_name, action, field, old_value, new_value, reason, created_at
+		FROM log_revisions
+		WHERE kind = $1 AND record_id = $2
+		ORDER BY id ASC;
+	`
+
+	// Повторно тот же файл не вставляется: пустой RETURNING — ErrAttachmentExists.
+	qInsertLogAttachment = `
+		INSERT INTO log_attachments (kind, record_id, file_type, file_id, file_unique_id,
+		                             file_name, mime_type, file_size, uploaded_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (kind, record_id, file_unique_id) DO NOTHING
+		RETURNING id, created_at;
+	`
+
+	qSelectLogAttachments = `
+		SELECT id, kind, record_id, file_type, file_id, file_unique_id,
+		       file_name, mime_type, file_size, uploaded_by, created_at
+		FROM log_attachments
+		WHERE kind = $1 AND record_id = $2
+		ORDER BY id ASC;
+	`
+
+	qCountLogAttachments = `
+		SELECT record_id, count(*) AS count
+		FROM log_attachments
+		WHERE kind = $1 AND record_id = ANY($2)
+		GROUP BY record_id;
 	`
 )
 
-// docker exec -it db psql -U user -d bookingbot-db -f /tmp/002_logs.up.sql
+// Поиск по журналам собирается в logRepositoryPG.Search: условия WHERE
+// зависят от фильтра, поэтому здесь только общие части.
+const (
+	qSearchSoglasheniya = `
+		SELECT 'sogl' AS kind, id, user_id, user_name, date,
+		       coalesce(doveritel, '') AS doveritel, coalesce(comment, '') AS comment, created_at,
+		       updated_at, voided_at, coalesce(void_reason, '') AS void_reason, reg_number,
+		       coalesce(client_id, 0) AS client_id
+		FROM soglasheniya
+		WHERE %s`
+
+	qSearchZaprosy = `
+		SELECT 'zapros' AS kind, id, user_id, user_name, date,
+		       coalesce(doveritel, '') AS doveritel, coalesce(comment, '') AS comment, created_at,
+		       updated_at, voided_at, coalesce(void_reason, '') AS void_reason, reg_number,
+		       coalesce(client_id, 0) AS client_id
+		FROM zaprosy
+		WHERE %s`
+
+	qSearchLogs = `
+		SELECT r.*, count(*) OVER () AS total
+		FROM (%s) r
+		ORDER BY r.date DESC, r.created_at DESC, r.id DESC
+		LIMIT %d OFFSET %d;`
+
+	// Выражение должно совпадать с индексами idx_*_search_trgm
+	qSearchText = `(coalesce(doveritel, '') || ' ' || coalesce(comment, '')) ILIKE %s`
+)
+
+// BOT STATE QUERIES
+
+const qRegisterGroupChat = `
+INSERT INTO bot_group_chats (chat_id, registered_by)
+VALUES ($1, $2)
+ON CONFLICT (chat_id) DO UPDATE
+SET registered_by = EXCLUDED.registered_by,
+    registered_at = now();
+`
+
+const qListGroupChats = `
+SELECT chat_id
+FROM bot_group_chats
+ORDER BY registered_at ASC, chat_id ASC;
+`
+
+const qUpsertScheduleMessage = `
+INSERT INTO bot_schedule_messages (chat_id, day, message_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (chat_id, day) DO UPDATE
+SET message_id = EXCLUDED.message_id,
+    created_at = now();
+`
+
+const qSelectScheduleMessage = `
+SELECT message_id
+FROM bot_schedule_messages
+WHERE chat_id = $1 AND day = $2;
+`
+
+// USER SETTINGS QUERIES
+
+const qSelectUserSettings = `
+SELECT user_id, remind_before_minutes
+FROM user_settings
+WHERE user_id = $1;
+`
+
+const qUpsertUserSettings = `
+INSERT INTO user_settings (user_id, remind_before_minutes)
+VALUES ($1, $2)
+ON CONFLICT (user_id) DO UPDATE
+SET remind_before_minutes = EXCLUDED.remind_before_minutes,
+    updated_at = now();
+`
+
+// AUDIT REPOSITORY QUERIES
+
+const qInsertAudit = `
+INSERT INTO audit_log (actor_id, actor_name, action, booking_id, owner_id, reason, details)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id;
+`
+
+// CLIENT REPOSITORY QUERIES
+
+// DO UPDATE вместо DO NOTHING, чтобы RETURNING вернул и уже существующего клиента.
+const qEnsureClient = `
+INSERT INTO clients (name, norm_name)
+VALUES ($1, $2)
+ON CONFLICT (norm_name) DO UPDATE SET norm_name = EXCLUDED.norm_name
+RETURNING id, name, norm_name, created_at;
+`
+
+const qSelectClientByID = `
+SELECT c.id, c.name, c.norm_name, c.created_at,
+       (SELECT count(*) FROM soglasheniya s WHERE s.client_id = c.id)
+     + (SELECT count(*) FROM zaprosy z WHERE z.client_id = c.id) AS records
+FROM clients c
+WHERE c.id = $1;
+`
+
+// Похожие клиенты: триграммное сходство или вхождение одного ключа в другой.
+// В ключе только буквы, цифры и пробелы, поэтому экранировать LIKE не нужно.
+const qSuggestClients = `
+SELECT c.id, c.name, c.norm_name, c.created_at,
+       (SELECT count(*) FROM soglasheniya s WHERE s.client_id = c.id)
+     + (SELECT count(*) FROM zaprosy z WHERE z.client_id = c.id) AS records
+FROM clients c
+WHERE c.norm_name % $1
+   OR c.norm_name LIKE '%' || $1 || '%'
+   OR $1 LIKE '%' || c.norm_name || '%'
+ORDER BY c.norm_name = $1 DESC, similarity(c.norm_name, $1) DESC, c.id
+LIMIT $2;
+`
+
+const qLinkSoglashenieClient = `
+UPDATE soglasheniya SET client_id = $2 WHERE id = $1;
+`
+
+const qLinkZaprosClient = `
+UPDATE zaprosy SET client_id = $2 WHERE id = $1;
+`
+
+const qMoveSoglasheniyaClient = `
+UPDATE soglasheniya SET client_id = $2 WHERE client_id = $1;
+`
+
+const qMoveZaprosyClient = `
+UPDATE zaprosy SET client_id = $2 WHERE client_id = $1;
+`
+
+const qDeleteClient = `
+DELETE FROM clients WHERE id = $1;
+`
+
+// SESSION QUERIES
+
+const qSelectSession = `
+SELECT kind, user_id, data, updated_at, expires_at
+FROM bot_sessions
+WHERE kind = $1 AND user_id = $2;
+`
+
+const qUpsertSession = `
+INSERT INTO bot_sessions (kind, user_id, data, updated_at, expires_at)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (kind, user_id) DO UPDATE
+SET data = EXCLUDED.data, updated_at = EXCLUDED.updated_at, expires_at = EXCLUDED.expires_at;
+`
+
+const qDeleteSession = `
+DELETE FROM bot_sessions WHERE kind = $1 AND user_id = $2;
+`
+
+const qDeleteExpiredSessions = `
+DELETE FROM bot_sessions WHERE expires_at < $1;
+`
+
+// USERNAME REPOSITORY QUERIES
+
+const qDeleteUsername = `
+DELETE FROM tg_usernames
+WHERE user_id = $1 OR username = $2;
+`
+
+const qInsertUsername = `
+INSERT INTO tg_usernames (username, user_id)
+VALUES ($1, $2);
+`
+
+const qSelectUserIDByUsername = `
+SELECT user_id
+FROM tg_usernames
+WHERE username = $1;
+`
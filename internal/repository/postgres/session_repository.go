@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/pkg/logger"
+)
+
+type sessionRepositoryPG struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+func NewSessionRepositoryPG(db *sqlx.DB, l logger.Logger) *sessionRepositoryPG {
+	return &sessionRepositoryPG{db: db, log: l}
+}
+
+type sessionRow struct {
+	Kind      string    `db:"kind"`
+	UserID    int64     `db:"user_id"`
+	Data      []byte    `db:"data"`
+	UpdatedAt time.Time `db:"updated_at"`
+	ExpiresAt time.Time `db:"expires_at"`
+}
+
+func (r *sessionRepositoryPG) GetSession(ctx context.Context, kind domain.SessionKind, userID int64) (domain.Session, error) {
+	var row sessionRow
+	if err := r.db.GetContext(ctx, &row, qSelectSession, string(kind), userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.Session{}, domain.ErrSessionNotFound
+		}
+		return domain.Session{}, fmt.Errorf("failed to get session: %w", err)
+	}
+	return domain.Session{
+		Kind:      domain.SessionKind(row.Kind),
+		UserID:    row.UserID,
+		Data:      row.Data,
+		UpdatedAt: row.UpdatedAt,
+		ExpiresAt: row.ExpiresAt,
+	}, nil
+}
+
+func (r *sessionRepositoryPG) SaveSession(ctx context.Context, s domain.Session) error {
+	// Строкой: jsonb принимает текст, а []byte pgx передал бы как bytea
+	if _, err := r.db.ExecContext(ctx, qUpsertSession,
+		string(s.Kind), s.UserID, string(s.Data), s.UpdatedAt, s.ExpiresAt); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepositoryPG) DeleteSession(ctx context.Context, kind domain.SessionKind, userID int64) error {
+	if _, err := r.db.ExecContext(ctx, qDeleteSession, string(kind), userID); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+func (r *sessionRepositoryPG) DeleteExpiredSessions(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.db.ExecContext(ctx, qDeleteExpiredSessions, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete expired sessions: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	r.log.Debug("Expired sessions deleted", "count", n)
+	return n, nil
+}
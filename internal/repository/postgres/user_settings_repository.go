@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/pkg/logger"
+)
+
+type userSettingsRepositoryPG struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+func NewUserSettingsRepositoryPG(db *sqlx.DB, l logger.Logger) *userSettingsRepositoryPG {
+	return &userSettingsRepositoryPG{db: db, log: l}
+}
+
+type userSettingsRow struct {
+	UserID              int64 `db:"user_id"`
+	RemindBeforeMinutes int   `db:"remind_before_minutes"`
+}
+
+func (r *userSettingsRepositoryPG) Get(ctx context.Context, userID domain.UserID) (domain.UserSettings, error) {
+	var row userSettingsRow
+	if err := r.db.GetContext(ctx, &row, qSelectUserSettings, int64(userID)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return domain.UserSettings{}, domain.ErrUserNotFound
+		}
+		return domain.UserSettings{}, err
+	}
+	return domain.UserSettings{
+		UserID:       domain.UserID(row.UserID),
+		RemindBefore: time.Duration(row.RemindBeforeMinutes) * time.Minute,
+	}, nil
+}
+
+func (r *userSettingsRepositoryPG) Save(ctx context.Context, s domain.UserSettings) error {
+	r.log.Debug("Saving user settings", "user", s.UserID, "remind_before", s.RemindBefore)
+	if _, err := r.db.ExecContext(ctx, qUpsertUserSettings, int64(s.UserID), int(s.RemindBefore.Minutes())); err != nil {
+		return fmt.Errorf("failed to save user settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/pkg/logger"
+)
+
+type usernameRepositoryPG struct {
+	db  *sqlx.DB
+	log logger.Logger
+}
+
+func NewUsernameRepositoryPG(db *sqlx.DB, l logger.Logger) *usernameRepositoryPG {
+	return &usernameRepositoryPG{db: db, log: l}
+}
+
+// SaveUsername удаляет прежние привязки ника и пользователя и пишет новую
+// в одной транзакции.
+func (r *usernameRepositoryPG) SaveUsername(ctx context.Context, userID domain.UserID, username string) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, qDeleteUsername, int64(userID), username); err != nil {
+		return fmt.Errorf("failed to delete username: %w", err)
+	}
+	if username != "" {
+		if _, err := tx.ExecContext(ctx, qInsertUsername, username, int64(userID)); err != nil {
+			return fmt.Errorf("failed to insert username: %w", err)
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *usernameRepositoryPG) FindUserIDByUsername(ctx context.Context, username string) (domain.UserID, error) {
+	var id int64
+	if err := r.db.GetContext(ctx, &id, qSelectUserIDByUsername, username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, domain.ErrUserNotFound
+		}
+		return 0, err
+	}
+	return domain.UserID(id), nil
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/pkg/config"
+	"github.com/leegeev/KomaevBookingBot/pkg/logger"
+)
+
+// BotStateService хранит состояние бота, которое должно переживать рестарт:
+// зарегистрированные группы и id сообщений с расписанием на день.
+type BotStateService struct {
+	repo   domain.BotStateRepository
+	logger logger.Logger
+	cfg    config.Telegram
+}
+
+func NewBotStateService(repo domain.BotStateRepository, logger logger.Logger, cfg config.Telegram) *BotStateService {
+	return &BotStateService{
+		repo:   repo,
+		logger: logger,
+		cfg:    cfg,
+	}
+}
+
+func (s *BotStateService) RegisterGroupChat(ctx context.Context, chatID int64, by domain.UserID) error {
+	s.logger.Info("Registering group chat", "chat_id", chatID, "by", by)
+	if chatID == 0 {
+		return domain.ErrInvalidInputData
+	}
+	if err := s.repo.RegisterGroupChat(ctx, chatID, by); err != nil {
+		s.logger.Error("Failed to register group chat", "chat_id", chatID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// GroupChats возвращает зарегистрированные группы; последняя — основная.
+// Если через /register ничего не регистрировали — группу из конфига.
+func (s *BotStateService) GroupChats(ctx context.Context) ([]int64, error) {
+	ids, err := s.repo.ListGroupChats(ctx)
+	if err != nil {
+		s.logger.Error("Failed to list group chats", "error", err)
+		return nil, err
+	}
+	if len(ids) == 0 && s.cfg.GroupChatID != 0 {
+		ids = []int64{s.cfg.GroupChatID}
+	}
+	return ids, nil
+}
+
+func (s *BotStateService) SaveScheduleMessage(ctx context.Context, chatID int64, day time.Time, messageID int) error {
+	if err := s.repo.SaveScheduleMessage(ctx, chatID, day.In(s.cfg.OfficeTZ), messageID); err != nil {
+		s.logger.Error("Failed to save schedule message", "chat_id", chatID, "message_id", messageID, "error", err)
+		return err
+	}
+	return nil
+}
+
+// ScheduleMessage возвращает id сообщения с расписанием на day; 0 — сообщения нет.
+func (s *BotStateService) ScheduleMessage(ctx context.Context, chatID int64, day time.Time) (int, error) {
+	id, err := s.repo.GetScheduleMessage(ctx, chatID, day.In(s.cfg.OfficeTZ))
+	if errors.Is(err, domain.ErrRecordNotFound) {
+		return 0, nil
+	} else if err != nil {
+		s.logger.Error("Failed to get schedule message", "chat_id", chatID, "error", err)
+		return 0, err
+	}
+	return id, nil
+}
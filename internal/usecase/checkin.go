@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+// Check-in: отметка о приходе на встречу и освобождение неиспользованных броней.
+
+// За сколько до начала бронь уже попадает в /checkin.
+const checkinWindow = 30 * time.Minute
+
+// CheckinGrace — сколько ждать отметки после начала брони. 0 — check-in выключен.
+func (s *BookingService) CheckinGrace() time.Duration {
+	return s.policy.CheckinGrace
+}
+
+// CheckIn отмечает приход на встречу. Отметиться может владелец брони или админ.
+func (s *BookingService) CheckIn(ctx context.Context, bookingID int64, actor domain.Actor) (domain.Booking, error) {
+	s.logger.Info("Checking in", "bookingID", bookingID, "userID", actor.UserID)
+
+	booking, err := s.bookingRepo.GetByID(ctx, domain.BookingID(bookingID))
+	if err != nil {
+		s.logger.Error("Failed to get booking", "error", err)
+		return domain.Booking{}, err
+	}
+	if !actor.CanManage(booking.UserID) {
+		s.logger.Warn("User cannot check in to this booking", "userID", actor.UserID, "bookingID", bookingID)
+		return domain.Booking{}, domain.ErrForbiddenCancellation
+	}
+
+	now := time.Now().UTC()
+	if !booking.Range.End.After(now) {
+		return domain.Booking{}, domain.ErrBookingEnded
+	}
+	if err := s.bookingRepo.CheckIn(ctx, booking.ID, now); err != nil {
+		s.logger.Error("Failed to check in", "bookingID", bookingID, "error", err)
+		return domain.Booking{}, err
+	}
+	if booking.CheckedInAt.IsZero() {
+		booking.CheckedInAt = now
+	}
+	return s.toLocal(booking), nil
+}
+
+// PendingCheckIns — брони пользователя, которые уже идут или скоро начнутся
+// и ещё не отмечены.
+func (s *BookingService) PendingCheckIns(ctx context.Context, userID domain.UserID) ([]domain.Booking, error) {
+	now := time.Now().UTC()
+	bookings, err := s.bookingRepo.ListByUser(ctx, userID, now)
+	if err != nil {
+		s.logger.Error("Failed to list user bookings", "userID", userID, "error", err)
+		return nil, err
+	}
+
+	var out []domain.Booking
+	for _, b := range bookings {
+		if b.CheckedInAt.IsZero() && !b.Range.Start.After(now.Add(checkinWindow)) {
+			out = append(out, b)
+		}
+	}
+	return s.toLocalSlice(out), nil
+}
+
+// ReleaseNoShows освобождает остаток броней, по которым не было check-in
+// в течение CheckinGrace после начала.
+func (s *BookingService) ReleaseNoShows(ctx context.Context) ([]domain.ReleasedBooking, error) {
+	grace := s.policy.CheckinGrace
+	if grace <= 0 {
+		return nil, nil
+	}
+
+	released, err := s.bookingRepo.ReleaseNoShows(ctx, time.Now().UTC(), grace)
+	if err != nil {
+		s.logger.Error("Failed to release no-show bookings", "error", err)
+		return nil, err
+	}
+	for i := range released {
+		s.logger.Info("Released no-show booking",
+			"bookingID", released[i].ID,
+			"userID", released[i].UserID,
+			"originalEnd", released[i].OriginalEnd)
+		released[i].Booking = s.toLocal(released[i].Booking)
+		released[i].OriginalEnd = released[i].OriginalEnd.In(s.cfg.OfficeTZ)
+	}
+	return released, nil
+}
@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+// Реестр доверителей. Записи журналов ссылаются на клиента, текст
+// доверителя в записи остаётся таким, как его ввели.
+
+// SuggestClients — похожие на введённого доверителя клиенты из реестра.
+func (s *LogService) SuggestClients(ctx context.Context, doveritel string) ([]domain.Client, error) {
+	norm := domain.NormalizeClientName(doveritel)
+	if norm == "" {
+		return nil, nil
+	}
+
+	list, err := s.clientRepo.SuggestClients(ctx, norm, domain.MaxClientSuggestions)
+	if err != nil {
+		s.logger.Error("Failed to suggest clients", "err", err, "doveritel", doveritel)
+		return nil, err
+	}
+	s.logger.Info("Suggested clients", "doveritel", doveritel, "count", len(list))
+	return list, nil
+}
+
+// NeedsClientChoice — стоит ли спрашивать, какой это клиент: среди
+// подсказок нет клиента с тем же ключом, что и у введённого текста, или
+// есть другие похожие.
+func NeedsClientChoice(doveritel string, suggestions []domain.Client) bool {
+	if len(suggestions) == 0 {
+		return false
+	}
+	return len(suggestions) > 1 || suggestions[0].NormName != domain.NormalizeClientName(doveritel)
+}
+
+func (s *LogService) GetClient(ctx context.Context, id domain.ClientID) (domain.Client, error) {
+	if id <= 0 {
+		return domain.Client{}, domain.ErrInvalidInputData
+	}
+	c, err := s.clientRepo.GetClient(ctx, id)
+	if err != nil {
+		s.logger.Warn("Failed to get client", "err", err, "clientID", id)
+		return domain.Client{}, err
+	}
+	return c, nil
+}
+
+// MergeClients переносит все записи клиента from на клиента to и удаляет
+// from. Только для администраторов. Возвращает оставшегося клиента.
+func (s *LogService) MergeClients(ctx context.Context, from, to domain.ClientID, actor domain.Actor) (domain.Client, error) {
+	s.logger.Info("Merging clients", "from", from, "to", to, "actor", actor.UserID)
+	if !actor.IsAdmin() {
+		return domain.Client{}, domain.ErrUnauthorized
+	}
+	if from <= 0 || to <= 0 || from == to {
+		return domain.Client{}, domain.ErrInvalidInputData
+	}
+	if _, err := s.GetClient(ctx, to); err != nil {
+		return domain.Client{}, err
+	}
+
+	moved, err := s.clientRepo.MergeClients(ctx, from, to)
+	if err != nil {
+		s.logger.Error("Failed to merge clients", "err", err, "from", from, "to", to)
+		return domain.Client{}, err
+	}
+	s.logger.Info("Clients merged", "from", from, "to", to, "moved", moved)
+	return s.GetClient(ctx, to)
+}
+
+// resolveClient — клиент для новой записи: выбранный из подсказок или
+// найденный (созданный) по тексту доверителя. Реестр вспомогательный, поэтому
+// при ошибке запись создаётся без ссылки на клиента.
+func (s *LogService) resolveClient(ctx context.Context, chosen domain.ClientID, doveritel string) domain.ClientID {
+	if chosen != 0 {
+		return chosen
+	}
+	norm := domain.NormalizeClientName(doveritel)
+	if norm == "" {
+		return 0
+	}
+	c, err := s.clientRepo.EnsureClient(ctx, doveritel, norm)
+	if err != nil {
+		s.logger.Error("Failed to ensure client", "err", err, "doveritel", doveritel)
+		return 0
+	}
+	return c.ID
+}
+
+// relinkClient перепривязывает запись к клиенту после правки доверителя.
+func (s *LogService) relinkClient(ctx context.Context, kind domain.LogKind, id int64, doveritel string) {
+	clientID := s.resolveClient(ctx, 0, doveritel)
+	if err := s.clientRepo.LinkLog(ctx, kind, id, clientID); err != nil {
+		s.logger.Error("Failed to relink log client", "err", err, "kind", kind, "id", id)
+	}
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/pkg/config"
+)
+
+// Фейки репозиториев для тестов. Встроенный интерфейс закрывает методы,
+// которые тест не использует: их вызов — паника, то есть ошибка теста.
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func testTelegramConfig() config.Telegram {
+	return config.Telegram{OfficeTZ: time.UTC}
+}
+
+type fakeLogRepo struct {
+	domain.LogRepository
+
+	soglasheniya []domain.Soglashenie
+	zaprosy      []domain.Zapros
+	reportQuery  []domain.ReportQuery // с какими фильтрами запрашивали выгрузку
+	revisions    []domain.LogRevision
+}
+
+// Правка и история ведутся только для соглашений — тестам этого хватает.
+
+func (r *fakeLogRepo) soglIndex(id int64) int {
+	for i, s := range r.soglasheniya {
+		if int64(s.ID) == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func (r *fakeLogRepo) GetSoglashenieByID(_ context.Context, id int64) (domain.Soglashenie, error) {
+	i := r.soglIndex(id)
+	if i < 0 {
+		return domain.Soglashenie{}, domain.ErrRecordNotFound
+	}
+	return r.soglasheniya[i], nil
+}
+
+func (r *fakeLogRepo) UpdateLogField(_ context.Context, rev domain.LogRevision) error {
+	i := r.soglIndex(rev.RecordID)
+	if i < 0 {
+		return domain.ErrRecordNotFound
+	}
+	if rev.Field == domain.LogFieldDoveritel {
+		r.soglasheniya[i].Doveritel = rev.NewValue
+	} else {
+		r.soglasheniya[i].Comment = rev.NewValue
+	}
+	r.soglasheniya[i].UpdatedAt = time.Now()
+	r.revisions = append(r.revisions, rev)
+	return nil
+}
+
+func (r *fakeLogRepo) VoidLog(_ context.Context, rev domain.LogRevision) error {
+	i := r.soglIndex(rev.RecordID)
+	if i < 0 {
+		return domain.ErrRecordNotFound
+	}
+	r.soglasheniya[i].VoidedAt = time.Now()
+	r.soglasheniya[i].VoidReason = rev.Reason
+	r.revisions = append(r.revisions, rev)
+	return nil
+}
+
+func (r *fakeLogRepo) ListRevisions(_ context.Context, kind domain.LogKind, id int64) ([]domain.LogRevision, error) {
+	var out []domain.LogRevision
+	for _, rev := range r.revisions {
+		if rev.Kind == kind && rev.RecordID == id {
+			out = append(out, rev)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeLogRepo) GetSoglasheniyaForReport(_ context.Context, q domain.ReportQuery) ([]domain.Soglashenie, error) {
+	r.reportQuery = append(r.reportQuery, q)
+	var out []domain.Soglashenie
+	for _, s := range r.soglasheniya {
+		if q.UserID == 0 || s.UserID == q.UserID {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeLogRepo) GetZaprosiForReport(_ context.Context, q domain.ReportQuery) ([]domain.Zapros, error) {
+	r.reportQuery = append(r.reportQuery, q)
+	var out []domain.Zapros
+	for _, z := range r.zaprosy {
+		if q.UserID == 0 || z.UserID == q.UserID {
+			out = append(out, z)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeLogRepo) CountLogAttachments(_ context.Context, _ domain.LogKind, _ []int64) (map[int64]int, error) {
+	return map[int64]int{}, nil
+}
+
+type fakeBookingRepo struct {
+	domain.BookingRepository
+
+	bookings      map[domain.BookingID]domain.Booking
+	series        map[domain.SeriesID]domain.BookingSeries
+	seriesCreated []domain.Booking // повторения, переданные в CreateSeries
+	conflicts     []int            // какие повторения CreateSeries «не вставит»
+	deleted       []domain.BookingID
+	deletedFrom   time.Time // fromUTC последнего DeleteSeries
+}
+
+func (r *fakeBookingRepo) GetByID(_ context.Context, id domain.BookingID) (domain.Booking, error) {
+	b, ok := r.bookings[id]
+	if !ok {
+		return domain.Booking{}, domain.ErrBookingNotFound
+	}
+	return b, nil
+}
+
+func (r *fakeBookingRepo) Delete(_ context.Context, id domain.BookingID) error {
+	if _, ok := r.bookings[id]; !ok {
+		return domain.ErrBookingNotFound
+	}
+	delete(r.bookings, id)
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+// ListByUser — брони пользователя, которые ещё не закончились к now.
+func (r *fakeBookingRepo) ListByUser(_ context.Context, userID domain.UserID, now time.Time) ([]domain.Booking, error) {
+	var out []domain.Booking
+	for _, b := range r.bookings {
+		if b.UserID == userID && b.Range.End.After(now) {
+			out = append(out, b)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeBookingRepo) GetSeries(_ context.Context, id domain.SeriesID) (domain.BookingSeries, error) {
+	s, ok := r.series[id]
+	if !ok {
+		return domain.BookingSeries{}, domain.ErrSeriesNotFound
+	}
+	return s, nil
+}
+
+func (r *fakeBookingRepo) CreateSeries(_ context.Context, s domain.BookingSeries, occurrences []domain.Booking) (domain.SeriesID, []int, error) {
+	r.seriesCreated = occurrences
+	if len(r.conflicts) == len(occurrences) {
+		return 0, nil, domain.ErrOverlapsExisting
+	}
+	return 1, r.conflicts, nil
+}
+
+// DeleteSeries удаляет повторения, начинающиеся после fromUTC, — как запрос в БД.
+func (r *fakeBookingRepo) DeleteSeries(_ context.Context, id domain.SeriesID, fromUTC time.Time) (int64, error) {
+	r.deletedFrom = fromUTC
+	var n int64
+	for bid, b := range r.bookings {
+		if b.SeriesID == id && b.Range.Start.After(fromUTC) {
+			delete(r.bookings, bid)
+			n++
+		}
+	}
+	return n, nil
+}
+
+type fakeRoomRepo struct {
+	domain.RoomRepository
+	rooms map[domain.RoomID]domain.Room
+}
+
+func (r *fakeRoomRepo) GetByID(_ context.Context, id domain.RoomID) (domain.Room, error) {
+	room, ok := r.rooms[id]
+	if !ok {
+		return domain.Room{}, domain.ErrRoomNotFound
+	}
+	return room, nil
+}
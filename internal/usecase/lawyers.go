@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+// Управление адвокатами (таблица users) администратором. ФИО в записях
+// журналов — снимок на момент создания, поэтому правка ФИО и отключение
+// адвоката уже созданные записи не меняют.
+
+func normalizeFIO(fio string) (string, error) {
+	fio = strings.Join(strings.Fields(fio), " ")
+	if fio == "" || len([]rune(fio)) > domain.MaxFIOLen {
+		return "", domain.ErrInvalidInputData
+	}
+	return fio, nil
+}
+
+// AddLawyer заранее регистрирует адвоката по Telegram ID, чтобы при первой
+// записи у него не спрашивали ФИО.
+func (s *LogService) AddLawyer(ctx context.Context, actor domain.Actor, id int64, fio string) (domain.User, error) {
+	s.logger.Info("Adding lawyer", "userID", id, "fio", fio, "actor", actor.UserID)
+	if !actor.IsAdmin() {
+		return domain.User{}, domain.ErrUnauthorized
+	}
+	fio, err := normalizeFIO(fio)
+	if err != nil || id <= 0 {
+		return domain.User{}, domain.ErrInvalidInputData
+	}
+
+	if u, err := s.logRepo.GetUser(ctx, id); err == nil {
+		return u, domain.ErrUserExists
+	}
+	if err := s.logRepo.CreateUser(ctx, id, fio); err != nil {
+		s.logger.Error("Failed to add lawyer", "err", err, "userID", id)
+		return domain.User{}, err
+	}
+	return s.logRepo.GetUser(ctx, id)
+}
+
+// RenameLawyer исправляет ФИО адвоката. Возвращает адвоката до правки.
+func (s *LogService) RenameLawyer(ctx context.Context, actor domain.Actor, id int64, fio string) (domain.User, error) {
+	s.logger.Info("Renaming lawyer", "userID", id, "fio", fio, "actor", actor.UserID)
+	if !actor.IsAdmin() {
+		return domain.User{}, domain.ErrUnauthorized
+	}
+	fio, err := normalizeFIO(fio)
+	if err != nil || id <= 0 {
+		return domain.User{}, domain.ErrInvalidInputData
+	}
+
+	old, err := s.logRepo.GetUser(ctx, id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if err := s.logRepo.UpdateUserFIO(ctx, id, fio); err != nil {
+		s.logger.Error("Failed to rename lawyer", "err", err, "userID", id)
+		return domain.User{}, err
+	}
+	s.logger.Info("Lawyer renamed", "userID", id, "old", old.FIO, "new", fio)
+	return old, nil
+}
+
+// SetLawyerActive отключает ушедшего адвоката или включает его обратно.
+func (s *LogService) SetLawyerActive(ctx context.Context, actor domain.Actor, id int64, active bool) (domain.User, error) {
+	s.logger.Info("Setting lawyer active", "userID", id, "active", active, "actor", actor.UserID)
+	if !actor.IsAdmin() {
+		return domain.User{}, domain.ErrUnauthorized
+	}
+	if id <= 0 {
+		return domain.User{}, domain.ErrInvalidInputData
+	}
+
+	if err := s.logRepo.SetUserActive(ctx, id, active); err != nil {
+		if !errors.Is(err, domain.ErrUserNotFound) {
+			s.logger.Error("Failed to set lawyer active", "err", err, "userID", id)
+		}
+		return domain.User{}, err
+	}
+	return s.logRepo.GetUser(ctx, id)
+}
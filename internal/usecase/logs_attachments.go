@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+// Сканы документов к записям журналов. Файлы хранит Telegram, в БД —
+// только file_id и сведения о файле.
+
+// CanAttachLog — может ли actor прикладывать файлы к записи.
+func (s *LogService) CanAttachLog(rec domain.LogRecord, actor domain.Actor) error {
+	return rec.CanAttach(actor)
+}
+
+// AttachLog прикладывает файл к записи a.Kind / a.RecordID. Возвращает
+// сохранённое вложение и число вложений у записи.
+func (s *LogService) AttachLog(ctx context.Context, a domain.LogAttachment, actor domain.Actor) (domain.LogAttachment, int, error) {
+	s.logger.Info("Attaching file to log record", "kind", a.Kind, "id", a.RecordID, "type", a.Type, "userID", actor.UserID)
+	if a.FileID == "" || a.FileUniqueID == "" ||
+		(a.Type != domain.AttachmentDocument && a.Type != domain.AttachmentPhoto) {
+		return domain.LogAttachment{}, 0, domain.ErrInvalidInputData
+	}
+
+	rec, err := s.getRecord(ctx, a.Kind, a.RecordID)
+	if err != nil {
+		return domain.LogAttachment{}, 0, err
+	}
+	if err := s.CanAttachLog(rec, actor); err != nil {
+		s.logger.Warn("User cannot attach to log record", "userID", actor.UserID, "number", rec.Number(), "error", err)
+		return domain.LogAttachment{}, 0, err
+	}
+
+	existing, err := s.logRepo.ListLogAttachments(ctx, a.Kind, a.RecordID)
+	if err != nil {
+		s.logger.Error("Failed to list log attachments", "number", rec.Number(), "err", err)
+		return domain.LogAttachment{}, 0, err
+	}
+	if len(existing) >= domain.MaxLogAttachments {
+		return domain.LogAttachment{}, len(existing), domain.ErrAttachmentLimit
+	}
+
+	a.UploadedBy = actor.UserID
+	saved, err := s.logRepo.AddLogAttachment(ctx, a)
+	if err != nil {
+		if !errors.Is(err, domain.ErrAttachmentExists) {
+			s.logger.Error("Failed to add log attachment", "number", rec.Number(), "err", err)
+		}
+		return domain.LogAttachment{}, len(existing), err
+	}
+	s.logger.Info("File attached to log record", "number", rec.Number(), "attachmentID", saved.ID)
+	return saved, len(existing) + 1, nil
+}
+
+// LogAttachments — запись и её вложения. Права — как у карточки записи.
+func (s *LogService) LogAttachments(ctx context.Context, kind domain.LogKind, id int64, actor domain.Actor) (domain.LogRecord, []domain.LogAttachment, error) {
+	rec, err := s.GetLog(ctx, kind, id, actor)
+	if err != nil {
+		return domain.LogRecord{}, nil, err
+	}
+	list, err := s.logRepo.ListLogAttachments(ctx, kind, id)
+	if err != nil {
+		s.logger.Error("Failed to list log attachments", "number", rec.Number(), "err", err)
+		return domain.LogRecord{}, nil, err
+	}
+	return rec, list, nil
+}
+
+// LogAttachmentCount — число вложений у записи, для кнопки на карточке.
+func (s *LogService) LogAttachmentCount(ctx context.Context, rec domain.LogRecord) (int, error) {
+	counts, err := s.attachmentCounts(ctx, rec.Kind, []domain.LogRecord{rec})
+	if err != nil {
+		return 0, err
+	}
+	return counts[rec.ID], nil
+}
+
+// attachmentCounts — число вложений у записей выгрузки.
+func (s *LogService) attachmentCounts(ctx context.Context, kind domain.LogKind, recs []domain.LogRecord) (map[int64]int, error) {
+	ids := make([]int64, 0, len(recs))
+	for _, rec := range recs {
+		ids = append(ids, rec.ID)
+	}
+	counts, err := s.logRepo.CountLogAttachments(ctx, kind, ids)
+	if err != nil {
+		s.logger.Error("Failed to count log attachments", "kind", kind, "err", err)
+		return nil, err
+	}
+	return counts, nil
+}
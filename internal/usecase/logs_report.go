@@ -0,0 +1,426 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	excelize "github.com/xuri/excelize/v2"
+)
+
+// Выгрузка журналов: одна книга Excel с листами «Запросы», «Соглашения»
+// и «Сводка». Книга собирается в памяти и отдаётся байтами.
+
+const (
+	sheetZaprosy      = "Запросы"
+	sheetSoglasheniya = "Соглашения"
+	sheetSummary      = "Сводка"
+)
+
+// ExcelReport — готовый файл выгрузки.
+type ExcelReport struct {
+	Name string
+	Data []byte
+}
+
+// CreateExcelReport формирует книгу Excel по параметрам выгрузки. Лист типа,
+// исключённого фильтром, не создаётся. Если записей нет, возвращает ErrRecordNotFound.
+// Выгрузка по всем авторам доступна только администратору.
+func (s *LogService) CreateExcelReport(ctx context.Context, q domain.ReportQuery, actor domain.Actor) (ExcelReport, error) {
+	s.logger.Info("Generating Excel report", "from", q.From, "to", q.To, "kind", q.Kind, "userID", q.UserID, "actor", actor.UserID)
+
+	if !q.From.IsZero() && !q.To.IsZero() && q.To.Before(q.From) {
+		return ExcelReport{}, domain.ErrInvalidInputData
+	}
+	if !actor.CanManage(q.UserID) {
+		s.logger.Warn("User cannot export these logs", "userID", actor.UserID, "filterUserID", q.UserID)
+		return ExcelReport{}, domain.ErrNotOwner
+	}
+
+	var zaprosy, soglasheniya []domain.LogRecord
+	if q.Includes(domain.LogKindZapros) {
+		list, err := s.logRepo.GetZaprosiForReport(ctx, q)
+		if err != nil {
+			s.logger.Error("Failed to get zaprosy", "err", err)
+			return ExcelReport{}, err
+		}
+		for _, z := range list {
+			zaprosy = append(zaprosy, domain.ZaprosRecord(z))
+		}
+	}
+	if q.Includes(domain.LogKindSogl) {
+		list, err := s.logRepo.GetSoglasheniyaForReport(ctx, q)
+		if err != nil {
+			s.logger.Error("Failed to get soglasheniya", "err", err)
+			return ExcelReport{}, err
+		}
+		for _, sgl := range list {
+			soglasheniya = append(soglasheniya, domain.SoglashenieRecord(sgl))
+		}
+	}
+	if len(zaprosy) == 0 && len(soglasheniya) == 0 {
+		return ExcelReport{}, domain.ErrRecordNotFound
+	}
+
+	zaprosyFiles, err := s.attachmentCounts(ctx, domain.LogKindZapros, zaprosy)
+	if err != nil {
+		return ExcelReport{}, err
+	}
+	soglasheniyaFiles, err := s.attachmentCounts(ctx, domain.LogKindSogl, soglasheniya)
+	if err != nil {
+		return ExcelReport{}, err
+	}
+
+	data, err := s.buildWorkbook(q, zaprosy, soglasheniya, zaprosyFiles, soglasheniyaFiles)
+	if err != nil {
+		s.logger.Error("Failed to build Excel report", "err", err)
+		return ExcelReport{}, err
+	}
+
+	report := ExcelReport{Name: reportFileName(q), Data: data}
+	s.logger.Info("Excel report generated successfully",
+		"file", report.Name, "size", len(data), "zaprosy", len(zaprosy), "soglasheniya", len(soglasheniya))
+	return report, nil
+}
+
+// Стили книги.
+type reportStyles struct {
+	header, date, dateTime, voided, title, total int
+}
+
+func newReportStyles(f *excelize.File) (reportStyles, error) {
+	var (
+		st  reportStyles
+		err error
+	)
+	border := []excelize.Border{{Type: "bottom", Color: "8EA9DB", Style: 1}}
+	if st.header, err = f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Color: []string{"D9E1F2"}, Pattern: 1},
+		Border:    border,
+		Alignment: &excelize.Alignment{Horizontal: "center", Vertical: "center", WrapText: true},
+	}); err != nil {
+		return st, err
+	}
+	dateFmt, dateTimeFmt := "dd.mm.yyyy", "dd.mm.yyyy hh:mm"
+	if st.date, err = f.NewStyle(&excelize.Style{CustomNumFmt: &dateFmt}); err != nil {
+		return st, err
+	}
+	if st.dateTime, err = f.NewStyle(&excelize.Style{CustomNumFmt: &dateTimeFmt}); err != nil {
+		return st, err
+	}
+	if st.voided, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Color: "808080", Italic: true}}); err != nil {
+		return st, err
+	}
+	if st.title, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true, Size: 13}}); err != nil {
+		return st, err
+	}
+	if st.total, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}, Border: []excelize.Border{{Type: "top", Color: "000000", Style: 1}}}); err != nil {
+		return st, err
+	}
+	return st, nil
+}
+
+// zaprosyFiles и soglasheniyaFiles — число вложений по ID записи.
+func (s *LogService) buildWorkbook(q domain.ReportQuery, zaprosy, soglasheniya []domain.LogRecord, zaprosyFiles, soglasheniyaFiles map[int64]int) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	st, err := newReportStyles(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// Лист по умолчанию переименовываем в первый нужный
+	first := f.GetSheetName(0)
+	var sheets []string
+	if q.Includes(domain.LogKindZapros) {
+		sheets = append(sheets, sheetZaprosy)
+	}
+	if q.Includes(domain.LogKindSogl) {
+		sheets = append(sheets, sheetSoglasheniya)
+	}
+	sheets = append(sheets, sheetSummary)
+	for i, name := range sheets {
+		if i == 0 {
+			if err := f.SetSheetName(first, name); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if _, err := f.NewSheet(name); err != nil {
+			return nil, err
+		}
+	}
+
+	if q.Includes(domain.LogKindZapros) {
+		if err := s.writeLogSheet(f, st, sheetZaprosy, zaprosy, zaprosyFiles); err != nil {
+			return nil, err
+		}
+	}
+	if q.Includes(domain.LogKindSogl) {
+		if err := s.writeLogSheet(f, st, sheetSoglasheniya, soglasheniya, soglasheniyaFiles); err != nil {
+			return nil, err
+		}
+	}
+	if err := writeSummarySheet(f, st, q, zaprosy, soglasheniya); err != nil {
+		return nil, err
+	}
+	f.SetActiveSheet(0)
+
+	buf, err := f.WriteToBuffer()
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+var logSheetColumns = []struct {
+	title string
+	width float64
+}{
+	{"Номер №", 16},
+	{"Дата", 12},
+	{"Ф.И.О. Адвоката", 30},
+	{"Доверитель", 35},
+	{"Комментарий", 45},
+	{"Дата создания", 17},
+	{"UserID", 14},
+	{"Статус", 30},
+	{"Изменена", 17},
+	{"Вложения", 11},
+}
+
+// writeLogSheet заполняет лист записей: шапка закреплена и с автофильтром,
+// даты — настоящие ячейки дат, аннулированные записи выделены серым.
+func (s *LogService) writeLogSheet(f *excelize.File, st reportStyles, sheet string, recs []domain.LogRecord, files map[int64]int) error {
+	for i, c := range logSheetColumns {
+		col, _ := excelize.ColumnNumberToName(i + 1)
+		cell := col + "1"
+		if err := f.SetCellValue(sheet, cell, c.title); err != nil {
+			return err
+		}
+		if err := f.SetColWidth(sheet, col, col, c.width); err != nil {
+			return err
+		}
+	}
+	lastCol, _ := excelize.ColumnNumberToName(len(logSheetColumns))
+	if err := f.SetCellStyle(sheet, "A1", lastCol+"1", st.header); err != nil {
+		return err
+	}
+
+	for i, rec := range recs {
+		row := i + 2
+		values := []any{
+			rec.Number(),
+			rec.Date,
+			rec.UserName,
+			rec.Doveritel,
+			rec.Comment,
+			rec.CreatedAt.In(s.cfg.OfficeTZ),
+			int64(rec.UserID),
+			voidStatus(rec),
+			nil,
+			files[rec.ID],
+		}
+		if !rec.UpdatedAt.IsZero() {
+			values[8] = rec.UpdatedAt.In(s.cfg.OfficeTZ)
+		}
+		cell, _ := excelize.CoordinatesToCellName(1, row)
+		if err := f.SetSheetRow(sheet, cell, &values); err != nil {
+			return err
+		}
+		if rec.Voided() {
+			if err := f.SetCellStyle(sheet, cell, fmt.Sprintf("%s%d", lastCol, row), st.voided); err != nil {
+				return err
+			}
+		}
+	}
+
+	if n := len(recs); n > 0 {
+		last := n + 1
+		if err := f.SetCellStyle(sheet, "B2", fmt.Sprintf("B%d", last), st.date); err != nil {
+			return err
+		}
+		if err := f.SetCellStyle(sheet, "F2", fmt.Sprintf("F%d", last), st.dateTime); err != nil {
+			return err
+		}
+		if err := f.SetCellStyle(sheet, "I2", fmt.Sprintf("I%d", last), st.dateTime); err != nil {
+			return err
+		}
+	}
+
+	if err := f.AutoFilter(sheet, fmt.Sprintf("A1:%s%d", lastCol, len(recs)+1), nil); err != nil {
+		return err
+	}
+	return f.SetPanes(sheet, &excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	})
+}
+
+// Счётчики сводки. Аннулированные записи считаются отдельно.
+type summaryCounts struct {
+	zaprosy, soglasheniya, voided int
+}
+
+func (c *summaryCounts) add(rec domain.LogRecord) {
+	switch {
+	case rec.Voided():
+		c.voided++
+	case rec.Kind == domain.LogKindSogl:
+		c.soglasheniya++
+	default:
+		c.zaprosy++
+	}
+}
+
+func (c summaryCounts) row(label string) []any {
+	return []any{label, c.zaprosy, c.soglasheniya, c.zaprosy + c.soglasheniya, c.voided}
+}
+
+var monthNames = [...]string{"Январь", "Февраль", "Март", "Апрель", "Май", "Июнь",
+	"Июль", "Август", "Сентябрь", "Октябрь", "Ноябрь", "Декабрь"}
+
+// writeSummarySheet — количество записей по адвокатам и по месяцам.
+func writeSummarySheet(f *excelize.File, st reportStyles, q domain.ReportQuery, zaprosy, soglasheniya []domain.LogRecord) error {
+	type lawyer struct {
+		name   string
+		counts summaryCounts
+	}
+	var (
+		total    summaryCounts
+		lawyers  = map[domain.UserID]*lawyer{}
+		months   = map[string]*summaryCounts{}
+		monthKey []string
+	)
+	for _, rec := range append(append([]domain.LogRecord{}, zaprosy...), soglasheniya...) {
+		total.add(rec)
+
+		l, ok := lawyers[rec.UserID]
+		if !ok {
+			l = &lawyer{name: rec.UserName}
+			lawyers[rec.UserID] = l
+		}
+		l.counts.add(rec)
+
+		key := rec.Date.Format("2006-01")
+		m, ok := months[key]
+		if !ok {
+			m = &summaryCounts{}
+			months[key] = m
+			monthKey = append(monthKey, key)
+		}
+		m.add(rec)
+	}
+
+	byName := make([]*lawyer, 0, len(lawyers))
+	for _, l := range lawyers {
+		byName = append(byName, l)
+	}
+	sort.Slice(byName, func(i, j int) bool { return byName[i].name < byName[j].name })
+	sort.Strings(monthKey)
+
+	header := []any{"", "Запросы", "Соглашения", "Всего", "Аннулировано"}
+	row := 1
+	put := func(values []any, style int) error {
+		cell, _ := excelize.CoordinatesToCellName(1, row)
+		if err := f.SetSheetRow(sheetSummary, cell, &values); err != nil {
+			return err
+		}
+		if style != 0 {
+			if err := f.SetCellStyle(sheetSummary, cell, fmt.Sprintf("E%d", row), style); err != nil {
+				return err
+			}
+		}
+		row++
+		return nil
+	}
+
+	title := "Сводка " + ReportPeriodLabel(q)
+	if err := put([]any{title}, st.title); err != nil {
+		return err
+	}
+	if err := put([]any{"Всего и по типам — без аннулированных записей"}, 0); err != nil {
+		return err
+	}
+	row++
+
+	header[0] = "Адвокат"
+	if err := put(header, st.header); err != nil {
+		return err
+	}
+	for _, l := range byName {
+		if err := put(l.counts.row(l.name), 0); err != nil {
+			return err
+		}
+	}
+	if err := put(total.row("Итого"), st.total); err != nil {
+		return err
+	}
+	row++
+
+	header[0] = "Месяц"
+	if err := put(header, st.header); err != nil {
+		return err
+	}
+	for _, key := range monthKey {
+		t, _ := time.Parse("2006-01", key)
+		label := fmt.Sprintf("%s %d", monthNames[t.Month()-1], t.Year())
+		if err := put(months[key].row(label), 0); err != nil {
+			return err
+		}
+	}
+	if err := put(total.row("Итого"), st.total); err != nil {
+		return err
+	}
+
+	if err := f.SetColWidth(sheetSummary, "A", "A", 32); err != nil {
+		return err
+	}
+	return f.SetColWidth(sheetSummary, "B", "E", 14)
+}
+
+// ReportPeriodLabel — период выгрузки словами: «за 01.01.2026 — 31.03.2026», «за всё время».
+func ReportPeriodLabel(q domain.ReportQuery) string {
+	switch {
+	case q.From.IsZero() && q.To.IsZero():
+		return "за всё время"
+	case q.From.IsZero():
+		return "по " + q.To.Format("02.01.2006")
+	case q.To.IsZero():
+		return "с " + q.From.Format("02.01.2006")
+	default:
+		return fmt.Sprintf("за %s — %s", q.From.Format("02.01.2006"), q.To.Format("02.01.2006"))
+	}
+}
+
+func reportFileName(q domain.ReportQuery) string {
+	name := "journals"
+	if q.UserID != 0 {
+		name = fmt.Sprintf("journals_%d", q.UserID)
+	}
+	if q.From.IsZero() && q.To.IsZero() {
+		return name + "_all.xlsx"
+	}
+	from, to := "start", time.Now().Format(time.DateOnly)
+	if !q.From.IsZero() {
+		from = q.From.Format(time.DateOnly)
+	}
+	if !q.To.IsZero() {
+		to = q.To.Format(time.DateOnly)
+	}
+	return fmt.Sprintf("%s_%s_%s.xlsx", name, from, to)
+}
+
+// voidStatus — отметка об аннулировании для отчёта.
+func voidStatus(rec domain.LogRecord) string {
+	if !rec.Voided() {
+		return ""
+	}
+	return "АННУЛИРОВАНА: " + rec.VoidReason
+}
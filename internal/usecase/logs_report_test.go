@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	repository "github.com/leegeev/KomaevBookingBot/internal/repository/postgres"
+	"github.com/leegeev/KomaevBookingBot/internal/repository/postgres/pgtest"
+)
+
+// Telegram ID новых аккаунтов не помещаются в int4.
+const bigUserID domain.UserID = 7_123_456_789
+
+func TestCreateExcelReportPersonal(t *testing.T) {
+	date := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	repo := &fakeLogRepo{
+		soglasheniya: []domain.Soglashenie{
+			{ID: 1, UserID: bigUserID, UserName: "Иванов Иван", Date: date, RegNumber: "ЭС-2026/0001"},
+			{ID: 2, UserID: 42, UserName: "Петров Пётр", Date: date, RegNumber: "ЭС-2026/0002"},
+		},
+		zaprosy: []domain.Zapros{
+			{ID: 1, UserID: bigUserID, UserName: "Иванов Иван", Date: date, RegNumber: "ЭЗ-2026/0001"},
+		},
+	}
+	s := NewLogService(repo, nil, testLogger(), testTelegramConfig(), domain.LogNumberFormat{})
+
+	tests := []struct {
+		name    string
+		actor   domain.Actor
+		userID  domain.UserID
+		wantErr error
+	}{
+		{"own records with 64-bit ID", domain.Actor{UserID: bigUserID, Role: domain.RoleMember}, bigUserID, nil},
+		{"admin exports lawyer with 64-bit ID", domain.Actor{UserID: 1, Role: domain.RoleAdmin}, bigUserID, nil},
+		{"member exports someone else", domain.Actor{UserID: 42, Role: domain.RoleMember}, bigUserID, domain.ErrNotOwner},
+		{"member exports everyone", domain.Actor{UserID: bigUserID, Role: domain.RoleMember}, 0, domain.ErrNotOwner},
+		{"no records", domain.Actor{UserID: 77, Role: domain.RoleMember}, 77, domain.ErrRecordNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo.reportQuery = nil
+			report, err := s.CreateExcelReport(context.Background(), domain.ReportQuery{UserID: tt.userID}, tt.actor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if len(report.Data) == 0 {
+				t.Fatal("empty report")
+			}
+			if want := "journals_7123456789_all.xlsx"; report.Name != want {
+				t.Errorf("report name = %q, want %q", report.Name, want)
+			}
+			for _, q := range repo.reportQuery {
+				if q.UserID != tt.userID {
+					t.Errorf("repository got UserID %d, want %d", q.UserID, tt.userID)
+				}
+			}
+		})
+	}
+}
+
+// Та же выгрузка «моих записей» через настоящий репозиторий.
+func TestCreateExcelReportPersonalPG(t *testing.T) {
+	ctx := context.Background()
+	conn := pgtest.Open(t)
+	repo := repository.NewLogRepositoryPG(conn, pgtest.Logger())
+	numFmt := domain.LogNumberFormat{Pattern: domain.DefaultLogNumberPattern, Width: domain.DefaultLogNumberWidth}
+	s := NewLogService(repo, repository.NewClientRepositoryPG(conn, pgtest.Logger()), testLogger(), testTelegramConfig(), numFmt)
+
+	if err := repo.CreateUser(ctx, int64(bigUserID), "Иванов Иван"); err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if _, err := repo.CreateSoglashenie(ctx, domain.Soglashenie{
+		UserID: bigUserID, UserName: "Иванов Иван", Date: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), Doveritel: "Ромашка", CreatedAt: time.Now(),
+	}, numFmt); err != nil {
+		t.Fatalf("CreateSoglashenie: %v", err)
+	}
+
+	report, err := s.CreateExcelReport(ctx, domain.ReportQuery{UserID: bigUserID}, domain.Actor{UserID: bigUserID, Role: domain.RoleMember})
+	if err != nil {
+		t.Fatalf("CreateExcelReport: %v", err)
+	}
+	if len(report.Data) == 0 {
+		t.Fatal("empty report")
+	}
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+// Правка и аннулирование записей журналов. Записи не удаляются,
+// каждое изменение сохраняется строкой истории (log_revisions).
+
+// Окно правки для автора, если в конфиге не задано telegram.log_edit_window.
+const defaultLogEditWindow = 24 * time.Hour
+
+func (s *LogService) editWindow() time.Duration {
+	if s.cfg.LogEditWindow > 0 {
+		return s.cfg.LogEditWindow
+	}
+	return defaultLogEditWindow
+}
+
+// EditWindow — сколько после создания автор может править свою запись.
+func (s *LogService) EditWindow() time.Duration {
+	return s.editWindow()
+}
+
+// CanEditLog — можно ли actor сейчас править или аннулировать запись.
+func (s *LogService) CanEditLog(rec domain.LogRecord, actor domain.Actor) error {
+	return rec.CanEdit(actor, time.Now(), s.editWindow())
+}
+
+type EditLogCmd struct {
+	Kind      domain.LogKind
+	ID        int64
+	Field     domain.LogField
+	Value     string
+	Actor     domain.Actor
+	ActorName string
+}
+
+// EditLog меняет доверителя или комментарий записи и возвращает её новое состояние.
+func (s *LogService) EditLog(ctx context.Context, cmd EditLogCmd) (domain.LogRecord, error) {
+	s.logger.Info("Editing log record", "kind", cmd.Kind, "id", cmd.ID, "field", cmd.Field, "userID", cmd.Actor.UserID)
+
+	value := strings.TrimSpace(cmd.Value)
+	if !cmd.Field.Valid() || value == "" || len([]rune(value)) > domain.MaxLogTextLen {
+		return domain.LogRecord{}, domain.ErrInvalidInputData
+	}
+
+	rec, err := s.getRecord(ctx, cmd.Kind, cmd.ID)
+	if err != nil {
+		return domain.LogRecord{}, err
+	}
+	if err := s.CanEditLog(rec, cmd.Actor); err != nil {
+		s.logger.Warn("User cannot edit log record", "userID", cmd.Actor.UserID, "number", rec.Number(), "error", err)
+		return domain.LogRecord{}, err
+	}
+	if rec.Value(cmd.Field) == value {
+		return rec, nil
+	}
+
+	err = s.logRepo.UpdateLogField(ctx, domain.LogRevision{
+		Kind:      cmd.Kind,
+		RecordID:  cmd.ID,
+		ActorID:   cmd.Actor.UserID,
+		ActorName: cmd.ActorName,
+		Action:    domain.LogRevisionEdit,
+		Field:     cmd.Field,
+		OldValue:  rec.Value(cmd.Field),
+		NewValue:  value,
+	})
+	if err != nil {
+		s.logger.Error("Failed to edit log record", "number", rec.Number(), "err", err)
+		return domain.LogRecord{}, err
+	}
+	s.logger.Info("Log record edited", "number", rec.Number(), "field", cmd.Field)
+
+	if cmd.Field == domain.LogFieldDoveritel {
+		s.relinkClient(ctx, cmd.Kind, cmd.ID, value)
+	}
+	return s.getRecord(ctx, cmd.Kind, cmd.ID)
+}
+
+type VoidLogCmd struct {
+	Kind      domain.LogKind
+	ID        int64
+	Reason    string
+	Actor     domain.Actor
+	ActorName string
+}
+
+// VoidLog аннулирует запись с обязательной причиной. Номер остаётся занятым.
+func (s *LogService) VoidLog(ctx context.Context, cmd VoidLogCmd) (domain.LogRecord, error) {
+	s.logger.Info("Voiding log record", "kind", cmd.Kind, "id", cmd.ID, "userID", cmd.Actor.UserID)
+
+	reason := strings.TrimSpace(cmd.Reason)
+	if n := len([]rune(reason)); n < domain.MinVoidReasonLen || n > domain.MaxVoidReasonLen {
+		return domain.LogRecord{}, domain.ErrInvalidInputData
+	}
+
+	rec, err := s.getRecord(ctx, cmd.Kind, cmd.ID)
+	if err != nil {
+		return domain.LogRecord{}, err
+	}
+	if err := s.CanEditLog(rec, cmd.Actor); err != nil {
+		s.logger.Warn("User cannot void log record", "userID", cmd.Actor.UserID, "number", rec.Number(), "error", err)
+		return domain.LogRecord{}, err
+	}
+
+	err = s.logRepo.VoidLog(ctx, domain.LogRevision{
+		Kind:      cmd.Kind,
+		RecordID:  cmd.ID,
+		ActorID:   cmd.Actor.UserID,
+		ActorName: cmd.ActorName,
+		Action:    domain.LogRevisionVoid,
+		Reason:    reason,
+	})
+	if err != nil {
+		s.logger.Error("Failed to void log record", "number", rec.Number(), "err", err)
+		return domain.LogRecord{}, err
+	}
+	s.logger.Info("Log record voided", "number", rec.Number())
+	return s.getRecord(ctx, cmd.Kind, cmd.ID)
+}
+
+// LogHistory возвращает историю изменений записи. Права — как у карточки записи.
+func (s *LogService) LogHistory(ctx context.Context, kind domain.LogKind, id int64, actor domain.Actor) (domain.LogRecord, []domain.LogRevision, error) {
+	rec, err := s.getRecord(ctx, kind, id)
+	if err != nil {
+		return domain.LogRecord{}, nil, err
+	}
+	if !actor.CanManage(rec.UserID) {
+		return domain.LogRecord{}, nil, domain.ErrNotOwner
+	}
+
+	revs, err := s.logRepo.ListRevisions(ctx, kind, id)
+	if err != nil {
+		s.logger.Error("Failed to list log revisions", "number", rec.Number(), "err", err)
+		return domain.LogRecord{}, nil, err
+	}
+	return rec, revs, nil
+}
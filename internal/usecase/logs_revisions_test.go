@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+const (
+	testAuthor domain.UserID = 100
+	testLawyer domain.UserID = 200
+)
+
+func newTestLogService(created time.Time) (*LogService, *fakeLogRepo) {
+	repo := &fakeLogRepo{soglasheniya: []domain.Soglashenie{{
+		ID: 1, UserID: testAuthor, UserName: "Иванов Иван", Doveritel: "Ромашка", Comment: "старый",
+		Date: created, CreatedAt: created, RegNumber: "ЭС-2026/0001",
+	}}}
+	return NewLogService(repo, nil, testLogger(), testTelegramConfig(), domain.LogNumberFormat{}), repo
+}
+
+func TestEditLog(t *testing.T) {
+	author := domain.Actor{UserID: testAuthor, Role: domain.RoleMember}
+
+	tests := []struct {
+		name    string
+		created time.Time
+		actor   domain.Actor
+		value   string
+		wantErr error
+	}{
+		{"author within window", time.Now().Add(-time.Hour), author, "новый", nil},
+		{"author after window", time.Now().Add(-48 * time.Hour), author, "новый", domain.ErrEditWindowOver},
+		{"admin after window", time.Now().Add(-48 * time.Hour), domain.Actor{UserID: testLawyer, Role: domain.RoleAdmin}, "новый", nil},
+		{"other lawyer", time.Now().Add(-time.Hour), domain.Actor{UserID: testLawyer, Role: domain.RoleMember}, "новый", domain.ErrNotOwner},
+		{"empty value", time.Now().Add(-time.Hour), author, "   ", domain.ErrInvalidInputData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, repo := newTestLogService(tt.created)
+
+			rec, err := s.EditLog(context.Background(), EditLogCmd{
+				Kind: domain.LogKindSogl, ID: 1, Field: domain.LogFieldComment, Value: tt.value,
+				Actor: tt.actor, ActorName: "actor",
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(repo.revisions) != 0 {
+					t.Fatalf("revisions written after a rejected edit: %v", repo.revisions)
+				}
+				return
+			}
+			if rec.Comment != tt.value || rec.UpdatedAt.IsZero() {
+				t.Errorf("record = %+v, want edited comment", rec)
+			}
+			if len(repo.revisions) != 1 {
+				t.Fatalf("got %d revisions, want 1", len(repo.revisions))
+			}
+			rev := repo.revisions[0]
+			if rev.Action != domain.LogRevisionEdit || rev.OldValue != "старый" || rev.NewValue != tt.value || rev.ActorID != tt.actor.UserID {
+				t.Errorf("revision = %+v", rev)
+			}
+		})
+	}
+}
+
+// Повторная правка тем же значением не пишет историю.
+func TestEditLogSameValue(t *testing.T) {
+	s, repo := newTestLogService(time.Now())
+
+	_, err := s.EditLog(context.Background(), EditLogCmd{
+		Kind: domain.LogKindSogl, ID: 1, Field: domain.LogFieldComment, Value: " старый ",
+		Actor: domain.Actor{UserID: testAuthor, Role: domain.RoleMember},
+	})
+	if err != nil {
+		t.Fatalf("EditLog: %v", err)
+	}
+	if len(repo.revisions) != 0 {
+		t.Fatalf("got %d revisions, want 0", len(repo.revisions))
+	}
+}
+
+func TestVoidLogAndHistory(t *testing.T) {
+	ctx := context.Background()
+	s, repo := newTestLogService(time.Now())
+	author := domain.Actor{UserID: testAuthor, Role: domain.RoleMember}
+
+	if _, err := s.VoidLog(ctx, VoidLogCmd{Kind: domain.LogKindSogl, ID: 1, Reason: "ой", Actor: author}); !errors.Is(err, domain.ErrInvalidInputData) {
+		t.Fatalf("short reason: err = %v, want ErrInvalidInputData", err)
+	}
+	if _, err := s.EditLog(ctx, EditLogCmd{Kind: domain.LogKindSogl, ID: 1, Field: domain.LogFieldComment, Value: "новый", Actor: author}); err != nil {
+		t.Fatalf("EditLog: %v", err)
+	}
+	rec, err := s.VoidLog(ctx, VoidLogCmd{Kind: domain.LogKindSogl, ID: 1, Reason: "ошибочная запись", Actor: author})
+	if err != nil {
+		t.Fatalf("VoidLog: %v", err)
+	}
+	if !rec.Voided() || rec.VoidReason != "ошибочная запись" {
+		t.Errorf("record = %+v, want voided", rec)
+	}
+
+	// Аннулированную запись не правит и не аннулирует никто, даже администратор
+	admin := domain.Actor{UserID: testLawyer, Role: domain.RoleAdmin}
+	if _, err := s.EditLog(ctx, EditLogCmd{Kind: domain.LogKindSogl, ID: 1, Field: domain.LogFieldComment, Value: "ещё", Actor: admin}); !errors.Is(err, domain.ErrRecordVoided) {
+		t.Errorf("edit voided: err = %v, want ErrRecordVoided", err)
+	}
+	if _, err := s.VoidLog(ctx, VoidLogCmd{Kind: domain.LogKindSogl, ID: 1, Reason: "повторно", Actor: admin}); !errors.Is(err, domain.ErrRecordVoided) {
+		t.Errorf("void voided: err = %v, want ErrRecordVoided", err)
+	}
+
+	_, revs, err := s.LogHistory(ctx, domain.LogKindSogl, 1, author)
+	if err != nil {
+		t.Fatalf("LogHistory: %v", err)
+	}
+	if len(revs) != 2 || revs[0].Action != domain.LogRevisionEdit || revs[1].Action != domain.LogRevisionVoid {
+		t.Fatalf("history = %+v, want edit then void", revs)
+	}
+	if len(repo.revisions) != 2 {
+		t.Fatalf("got %d revisions, want 2", len(repo.revisions))
+	}
+
+	if _, _, err := s.LogHistory(ctx, domain.LogKindSogl, 1, domain.Actor{UserID: testLawyer, Role: domain.RoleMember}); !errors.Is(err, domain.ErrNotOwner) {
+		t.Errorf("history of other lawyer: err = %v, want ErrNotOwner", err)
+	}
+}
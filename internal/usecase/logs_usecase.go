@@ -2,13 +2,11 @@ package usecase
 
 import (
 	"context"
-	"fmt"
 	"time"
 
 	"github.com/leegeev/KomaevBookingBot/internal/domain"
 	"github.com/leegeev/KomaevBookingBot/pkg/config"
 	"github.com/leegeev/KomaevBookingBot/pkg/logger"
-	excelize "github.com/xuri/excelize/v2"
 )
 
 type CreateLogCmd struct {
@@ -18,19 +16,24 @@ type CreateLogCmd struct {
 	Date      time.Time
 	Doveritel string
 	Comment   string
+	ClientID  domain.ClientID // выбран из подсказок; 0 — найти или создать по Doveritel
 }
 
 type LogService struct {
-	logRepo domain.LogRepository
-	logger  logger.Logger
-	cfg     config.Telegram
+	logRepo    domain.LogRepository
+	clientRepo domain.ClientRepository
+	logger     logger.Logger
+	cfg        config.Telegram
+	numFmt     domain.LogNumberFormat
 }
 
-func NewLogService(logRepo domain.LogRepository, logger logger.Logger, cfg config.Telegram) *LogService {
+func NewLogService(logRepo domain.LogRepository, clientRepo domain.ClientRepository, logger logger.Logger, cfg config.Telegram, numFmt domain.LogNumberFormat) *LogService {
 	return &LogService{
-		logRepo: logRepo,
-		logger:  logger,
-		cfg:     cfg,
+		logRepo:    logRepo,
+		clientRepo: clientRepo,
+		logger:     logger,
+		cfg:        cfg,
+		numFmt:     numFmt,
 	}
 }
 
@@ -57,7 +60,8 @@ func (s *LogService) GetUser(ctx context.Context, id int64) (domain.User, error)
 
 func (s *LogService) CreateUser(ctx context.Context, id int64, FIO string) error {
 	s.logger.Info("Creating user", "userID", id, "fio", FIO)
-	if id <= 0 || FIO == "" {
+	FIO, err := normalizeFIO(FIO)
+	if id <= 0 || err != nil {
 		s.logger.Error("Invalid input data", "userID", id, "fio", FIO)
 		return domain.ErrInvalidInputData
 	}
@@ -71,32 +75,65 @@ func (s *LogService) CreateUser(ctx context.Context, id int64, FIO string) error
 	return nil
 }
 
-// Получить соглашения пользователя
-func (s *LogService) GetSoglasheniyaByUserID(ctx context.Context, userID int64) ([]domain.Soglashenie, error) {
-	s.logger.Info("Getting soglasheniya by userID", "userID", userID)
-	list, err := s.logRepo.GetSoglasheniyaByUserID(ctx, domain.UserID(userID))
+// BackfillNumbers проставляет номера записям, пронумерованным миграцией 011:
+// номер собирается по настроенному формату, а не по формату по умолчанию.
+func (s *LogService) BackfillNumbers(ctx context.Context) error {
+	n, err := s.logRepo.BackfillRegNumbers(ctx, s.numFmt)
 	if err != nil {
-		s.logger.Error("Failed to get soglasheniya", "err", err)
-		return nil, err
+		s.logger.Error("Failed to backfill log numbers", "err", err)
+		return err
+	}
+	if n > 0 {
+		s.logger.Info("Log numbers backfilled", "count", n, "pattern", s.numFmt.Pattern)
 	}
-	s.logger.Info("Found soglasheniya", "count", len(list))
-	return list, nil
+	return nil
 }
 
-// Получить запросы пользователя
-func (s *LogService) GetZaprosiByUserID(ctx context.Context, userID int64) ([]domain.Zapros, error) {
-	s.logger.Info("Getting zaprosy by userID", "userID", userID)
-	if userID <= 0 {
-		return nil, domain.ErrInvalidInputData
+// Размер страницы при листании записей пользователя.
+const LogListPageSize = 5
+
+// ListUserLogs — страница записей пользователя userID одного типа и общее их
+// число. Свои записи видит каждый, чужие — только администратор.
+func (s *LogService) ListUserLogs(ctx context.Context, kind domain.LogKind, userID domain.UserID, offset int, actor domain.Actor) ([]domain.LogRecord, int, error) {
+	s.logger.Info("Listing user logs", "kind", kind, "userID", userID, "offset", offset, "actor", actor.UserID)
+	if userID <= 0 || offset < 0 {
+		return nil, 0, domain.ErrInvalidInputData
+	}
+	if !actor.CanManage(userID) {
+		s.logger.Warn("User cannot view these logs", "userID", actor.UserID, "ownerID", userID)
+		return nil, 0, domain.ErrNotOwner
 	}
 
-	list, err := s.logRepo.GetZaprosiByUserID(ctx, domain.UserID(userID))
-	if err != nil {
-		s.logger.Error("Failed to get zaprosy", "err", err)
-		return nil, err
+	var (
+		out   []domain.LogRecord
+		total int
+	)
+	switch kind {
+	case domain.LogKindSogl:
+		list, n, err := s.logRepo.GetSoglasheniyaByUserID(ctx, userID, LogListPageSize, offset)
+		if err != nil {
+			s.logger.Error("Failed to get soglasheniya", "err", err)
+			return nil, 0, err
+		}
+		for _, sgl := range list {
+			out = append(out, domain.SoglashenieRecord(sgl))
+		}
+		total = n
+	case domain.LogKindZapros:
+		list, n, err := s.logRepo.GetZaprosiByUserID(ctx, userID, LogListPageSize, offset)
+		if err != nil {
+			s.logger.Error("Failed to get zaprosy", "err", err)
+			return nil, 0, err
+		}
+		for _, z := range list {
+			out = append(out, domain.ZaprosRecord(z))
+		}
+		total = n
+	default:
+		return nil, 0, domain.ErrInvalidInputData
 	}
-	s.logger.Info("Found zaprosy", "count", len(list))
-	return list, nil
+	s.logger.Info("Found user logs", "count", len(out), "total", total)
+	return out, total, nil
 }
 
 // Получить одно соглашение по ID
@@ -129,10 +166,114 @@ func (s *LogService) GetZaprosById(ctx context.Context, id int64) (domain.Zapros
 	return record, nil
 }
 
-// Создание записи (соглашения или запроса)
-func (s *LogService) CreateLog(ctx context.Context, cmd CreateLogCmd) (int64, error) {
+// FindLog ищет запись по номеру (ЭС-2026/0001 или старому ЭС<id>). Администратор
+// видит любую запись, участник — только свои (для чужих возвращается ErrNotOwner).
+func (s *LogService) FindLog(ctx context.Context, number string, actor domain.Actor) (domain.LogRecord, error) {
+	s.logger.Info("Finding log record", "number", number, "userID", actor.UserID)
+	rec, err := s.findByNumber(ctx, number)
+	if err != nil {
+		return domain.LogRecord{}, err
+	}
+	return s.checkView(rec, actor)
+}
+
+// GetLog — запись по типу и id, права те же, что у FindLog.
+func (s *LogService) GetLog(ctx context.Context, kind domain.LogKind, id int64, actor domain.Actor) (domain.LogRecord, error) {
+	rec, err := s.getRecord(ctx, kind, id)
+	if err != nil {
+		return domain.LogRecord{}, err
+	}
+	return s.checkView(rec, actor)
+}
+
+func (s *LogService) checkView(rec domain.LogRecord, actor domain.Actor) (domain.LogRecord, error) {
+	if !actor.CanManage(rec.UserID) {
+		s.logger.Warn("User cannot view this log record", "userID", actor.UserID, "number", rec.Number())
+		return domain.LogRecord{}, domain.ErrNotOwner
+	}
+	return rec, nil
+}
+
+// findByNumber ищет запись по регистрационному номеру, а если он не
+// разобран — по старому номеру ЭС<id>, выданному до нумерации по годам.
+func (s *LogService) findByNumber(ctx context.Context, number string) (domain.LogRecord, error) {
+	if kind, year, seq, err := s.numFmt.Parse(number); err == nil {
+		return s.logRepo.GetLogByRegNumber(ctx, kind, year, seq)
+	}
+
+	kind, id, err := domain.ParseLogNumber(number)
+	if err != nil {
+		return domain.LogRecord{}, err
+	}
+	return s.getRecord(ctx, kind, id)
+}
+
+// getRecord загружает запись журнала любого типа.
+func (s *LogService) getRecord(ctx context.Context, kind domain.LogKind, id int64) (domain.LogRecord, error) {
+	switch kind {
+	case domain.LogKindSogl:
+		sogl, err := s.GetSoglasheniyaById(ctx, id)
+		if err != nil {
+			return domain.LogRecord{}, err
+		}
+		return domain.SoglashenieRecord(sogl), nil
+	case domain.LogKindZapros:
+		z, err := s.GetZaprosById(ctx, id)
+		if err != nil {
+			return domain.LogRecord{}, err
+		}
+		return domain.ZaprosRecord(z), nil
+	default:
+		return domain.LogRecord{}, domain.ErrInvalidInputData
+	}
+}
+
+// Размер страницы результатов поиска.
+const LogSearchPageSize = 5
+
+// Search ищет записи журналов по доверителю и комментарию среди записей
+// всех адвокатов — чтобы проверить, не было ли уже соглашения с доверителем.
+// Чужие записи (см. Actor.CanManage) возвращаются без содержимого: только
+// номер, дата и автор. Сами карточки записей открываются по правилам FindLog.
+func (s *LogService) Search(ctx context.Context, f domain.LogSearchFilter, actor domain.Actor) ([]domain.LogRecord, int, error) {
+	s.logger.Info("Searching logs", "query", f.Query, "kind", f.Kind, "userID", actor.UserID, "offset", f.Offset)
+	if len(f.Words()) == 0 {
+		return nil, 0, domain.ErrInvalidInputData
+	}
+	if !f.From.IsZero() && !f.To.IsZero() && f.To.Before(f.From) {
+		return nil, 0, domain.ErrInvalidInputData
+	}
+	f.Limit = LogSearchPageSize
+
+	list, total, err := s.logRepo.Search(ctx, f)
+	if err != nil {
+		s.logger.Error("Failed to search logs", "err", err)
+		return nil, 0, err
+	}
+	for i, rec := range list {
+		if !actor.CanManage(rec.UserID) {
+			list[i] = rec.Masked()
+		}
+	}
+	s.logger.Info("Found logs", "count", len(list), "total", total)
+	return list, total, nil
+}
+
+// Создание записи (соглашения или запроса). Возвращает созданную запись с регистрационным номером.
+func (s *LogService) CreateLog(ctx context.Context, cmd CreateLogCmd) (domain.LogRecord, error) {
 	s.logger.Info("Creating log entry", "user", cmd.UserName, "type", cmd.Type, "TZ:", s.cfg.OfficeTZ, "time", time.Now().In(s.cfg.OfficeTZ))
 
+	// В запись попадает текущее ФИО адвоката: правки ФИО после создания
+	// записи её не меняют. Отключённый адвокат записи не создаёт.
+	if user, err := s.logRepo.GetUser(ctx, int64(cmd.UserID)); err == nil {
+		if !user.Active {
+			s.logger.Warn("Inactive user tried to create log", "userID", cmd.UserID)
+			return domain.LogRecord{}, domain.ErrUserInactive
+		}
+		cmd.UserName = user.FIO
+	}
+	clientID := s.resolveClient(ctx, cmd.ClientID, cmd.Doveritel)
+
 	switch cmd.Type {
 	case "sogl":
 		sogl := domain.Soglashenie{
@@ -141,17 +282,18 @@ func (s *LogService) CreateLog(ctx context.Context, cmd CreateLogCmd) (int64, er
 			Date:      cmd.Date,
 			Doveritel: cmd.Doveritel,
 			Comment:   cmd.Comment,
+			ClientID:  clientID,
 			CreatedAt: time.Now().In(s.cfg.OfficeTZ),
 			// CreatedAt: time.Now(),
 		}
 
-		id, err := s.logRepo.CreateSoglashenie(ctx, sogl)
+		sogl, err := s.logRepo.CreateSoglashenie(ctx, sogl, s.numFmt)
 		if err != nil {
 			s.logger.Error("Failed to create soglashenie", "err", err)
-			return 0, err
+			return domain.LogRecord{}, err
 		}
-		s.logger.Info("Soglashenie created successfully", "id", id)
-		return id, nil
+		s.logger.Info("Soglashenie created successfully", "id", sogl.ID, "number", sogl.RegNumber)
+		return domain.SoglashenieRecord(sogl), nil
 
 	case "zapros":
 		z := domain.Zapros{
@@ -160,21 +302,22 @@ func (s *LogService) CreateLog(ctx context.Context, cmd CreateLogCmd) (int64, er
 			Date:      cmd.Date,
 			Doveritel: cmd.Doveritel,
 			Comment:   cmd.Comment,
+			ClientID:  clientID,
 			CreatedAt: time.Now().In(s.cfg.OfficeTZ),
 			// CreatedAt: time.Now(),
 		}
 
-		id, err := s.logRepo.CreateZapros(ctx, z)
+		z, err := s.logRepo.CreateZapros(ctx, z, s.numFmt)
 		if err != nil {
 			s.logger.Error("Failed to create zapros", "err", err)
-			return 0, err
+			return domain.LogRecord{}, err
 		}
-		s.logger.Info("Zapros created successfully", "id", id)
-		return id, nil
+		s.logger.Info("Zapros created successfully", "id", z.ID, "number", z.RegNumber)
+		return domain.ZaprosRecord(z), nil
 
 	default:
 		s.logger.Warn("Unknown log type", "type", cmd.Type)
-		return 0, domain.ErrInvalidInputData
+		return domain.LogRecord{}, domain.ErrInvalidInputData
 	}
 }
 
@@ -196,100 +339,12 @@ type LogEntry struct {
 	CreatedAt time.Time
 }
 
-// CreateExcelReport формирует два Excel-файла (запросы и соглашения) за последний год.
-func (s *LogService) CreateExcelReport(ctx context.Context) (string, string, error) {
-	s.logger.Info("Generating Excel reports for last year")
-
-	// 1️⃣ Берём период за последний год
-	oneYearAgo := time.Now().AddDate(-1, 0, 0)
-
-	// 2️⃣ Получаем данные из репозитория
-	zaprosy, err := s.logRepo.GetZaprosiAfterDate(ctx, oneYearAgo)
-	if err != nil {
-		s.logger.Error("Failed to get zaprosy", "err", err)
-		return "", "", err
-	}
-
-	soglasheniya, err := s.logRepo.GetSoglasheniyaAfterDate(ctx, oneYearAgo)
+// ListLawyers — адвокаты, сохранившие ФИО, для фильтра выгрузки.
+func (s *LogService) ListLawyers(ctx context.Context) ([]domain.User, error) {
+	users, err := s.logRepo.ListUsers(ctx)
 	if err != nil {
-		s.logger.Error("Failed to get soglasheniya", "err", err)
-		return "", "", err
-	}
-
-	// 3️⃣ Формируем отчёт для запросов
-	zaprosFilePath, err := s.createZaprosExcel(zaprosy)
-	if err != nil {
-		s.logger.Error("Failed to create zapros Excel", "err", err)
-		return "", "", err
-	}
-
-	// 4️⃣ Формируем отчёт для соглашений
-	soglFilePath, err := s.createSoglasheniyaExcel(soglasheniya)
-	if err != nil {
-		s.logger.Error("Failed to create soglasheniya Excel", "err", err)
-		return "", "", err
-	}
-
-	s.logger.Info("Excel reports generated successfully",
-		"zaprosFile", zaprosFilePath,
-		"soglFile", soglFilePath)
-
-	return zaprosFilePath, soglFilePath, nil
-}
-
-func (s *LogService) createZaprosExcel(zaprosy []domain.Zapros) (string, error) {
-	f := excelize.NewFile()
-	sheet := f.GetSheetName(0)
-
-	headers := []string{"Номер №", "Дата", "Ф.И.О. Адвоката", "Доверитель", "Комментарий", "Дата создания", "UserID"}
-	for i, h := range headers {
-		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
-		f.SetCellValue(sheet, cell, h)
-	}
-
-	for i, z := range zaprosy {
-		row := i + 2
-		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("ЭЗ%d", z.ID))
-		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), z.Date.Format("2006-01-02"))
-		f.SetCellValue(sheet, fmt.Sprintf("C%d", row), z.UserName)
-		f.SetCellValue(sheet, fmt.Sprintf("D%d", row), z.Doveritel)
-		f.SetCellValue(sheet, fmt.Sprintf("E%d", row), z.Comment)
-		f.SetCellValue(sheet, fmt.Sprintf("F%d", row), z.CreatedAt.Format("2006-01-02 15:04:05"))
-		f.SetCellValue(sheet, fmt.Sprintf("G%d", row), z.UserID)
-	}
-
-	filePath := fmt.Sprintf("/tmp/zaprosy_report_%d.xlsx", time.Now().Unix())
-	if err := f.SaveAs(filePath); err != nil {
-		return "", err
-	}
-	return filePath, nil
-}
-
-func (s *LogService) createSoglasheniyaExcel(soglasheniya []domain.Soglashenie) (string, error) {
-	f := excelize.NewFile()
-	sheet := f.GetSheetName(0)
-
-	headers := []string{"Номер №", "Дата", "Ф.И.О. Адвоката", "Доверитель", "Комментарий", "Дата создания", "UserID"}
-	for i, h := range headers {
-		cell, _ := excelize.CoordinatesToCellName(i+1, 1)
-		f.SetCellValue(sheet, cell, h)
-	}
-
-	for i, sgl := range soglasheniya {
-		row := i + 2
-		f.SetCellValue(sheet, fmt.Sprintf("A%d", row), fmt.Sprintf("ЭС%d", sgl.ID))
-		f.SetCellValue(sheet, fmt.Sprintf("B%d", row), sgl.Date.Format("2006-01-02"))
-		f.SetCellValue(sheet, fmt.Sprintf("C%d", row), sgl.UserName)
-		f.SetCellValue(sheet, fmt.Sprintf("D%d", row), sgl.Doveritel)
-		f.SetCellValue(sheet, fmt.Sprintf("E%d", row), sgl.Comment)
-		f.SetCellValue(sheet, fmt.Sprintf("F%d", row), sgl.CreatedAt.Format("2006-01-02 15:04:05"))
-		f.SetCellValue(sheet, fmt.Sprintf("G%d", row), sgl.UserID)
-	}
-
-	// filePath := fmt.Sprintf("soglasheniya_report_%d.xlsx", time.Now().Unix())
-	filePath := fmt.Sprintf("/tmp/soglasheniya_report_%d.xlsx", time.Now().Unix())
-	if err := f.SaveAs(filePath); err != nil {
-		return "", err
+		s.logger.Error("Failed to list users", "err", err)
+		return nil, err
 	}
-	return filePath, nil
+	return users, nil
 }
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/pkg/config"
+)
+
+const defaultSlotStep = 30 * time.Minute
+
+var weekdays = map[string]time.Weekday{
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+	"sun": time.Sunday,
+}
+
+// NewBookingPolicy собирает доменную политику бронирования из секции booking конфига.
+func NewBookingPolicy(cfg config.Booking) (domain.BookingPolicy, error) {
+	policy := domain.BookingPolicy{
+		WeekdayHours:     make(map[time.Weekday]domain.WorkingHours),
+		ClosedDays:       make(map[time.Weekday]bool),
+		Step:             cfg.SlotStep,
+		MinDuration:      cfg.MinDuration,
+		MaxDuration:      cfg.MaxDuration,
+		MaxDaysAhead:     cfg.MaxDaysAhead,
+		MaxActivePerUser: cfg.MaxActivePerUser,
+		CheckinGrace:     cfg.CheckinGrace,
+	}
+
+	if cfg.WorkHours != "" {
+		wh, err := domain.ParseWorkingHours(cfg.WorkHours)
+		if err != nil {
+			return domain.BookingPolicy{}, err
+		}
+		policy.WorkingHours = wh
+	}
+
+	for key, val := range cfg.WeekdayHours {
+		wd, ok := weekdays[strings.ToLower(key)]
+		if !ok {
+			return domain.BookingPolicy{}, fmt.Errorf("%w: unknown weekday %q", domain.ErrInvalidWorkingHours, key)
+		}
+		val = strings.TrimSpace(val)
+		if val == "" || strings.EqualFold(val, "closed") {
+			policy.ClosedDays[wd] = true
+			continue
+		}
+		wh, err := domain.ParseWorkingHours(val)
+		if err != nil {
+			return domain.BookingPolicy{}, err
+		}
+		policy.WeekdayHours[wd] = wh
+	}
+
+	if policy.Step <= 0 {
+		policy.Step = defaultSlotStep
+	}
+	if policy.MaxDuration > 0 && policy.MinDuration > policy.MaxDuration {
+		return domain.BookingPolicy{}, fmt.Errorf("%w: min_duration > max_duration", domain.ErrInvalidInputData)
+	}
+	if policy.CheckinGrace < 0 {
+		return domain.BookingPolicy{}, fmt.Errorf("%w: negative checkin_grace", domain.ErrInvalidInputData)
+	}
+	return policy, nil
+}
@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+// Напоминания о начале брони.
+
+// Значение по умолчанию, если в конфиге не задано telegram.reminder_before.
+const defaultRemindBefore = 15 * time.Minute
+
+// Максимум, за сколько можно попросить напоминать.
+const maxRemindBefore = 24 * time.Hour
+
+func (s *BookingService) defaultRemindBefore() time.Duration {
+	if s.cfg.ReminderBefore > 0 {
+		return s.cfg.ReminderBefore
+	}
+	return defaultRemindBefore
+}
+
+// DueReminders забирает брони, о которых пора напомнить владельцу.
+// Повторно одна и та же бронь не вернётся (кроме случая ReleaseReminder).
+func (s *BookingService) DueReminders(ctx context.Context) ([]domain.Booking, error) {
+	bks, err := s.bookingRepo.ClaimDueReminders(ctx, time.Now().UTC(), s.defaultRemindBefore())
+	if err != nil {
+		s.logger.Error("Failed to claim due reminders", "error", err)
+		return nil, err
+	}
+	if len(bks) > 0 {
+		s.logger.Info("Claimed due reminders", "count", len(bks))
+	}
+	return s.toLocalSlice(bks), nil
+}
+
+// ReleaseReminder возвращает напоминание в очередь, если отправить его не удалось
+// по временной причине (сеть, лимиты Telegram).
+func (s *BookingService) ReleaseReminder(ctx context.Context, id domain.BookingID) error {
+	if err := s.bookingRepo.ReleaseReminder(ctx, id); err != nil {
+		s.logger.Error("Failed to release reminder", "bookingID", id, "error", err)
+		return err
+	}
+	return nil
+}
+
+// RemindBefore возвращает, за сколько до начала брони пользователь получает напоминание.
+func (s *BookingService) RemindBefore(ctx context.Context, userID domain.UserID) (time.Duration, error) {
+	st, err := s.settingsRepo.Get(ctx, userID)
+	if errors.Is(err, domain.ErrUserNotFound) {
+		return s.defaultRemindBefore(), nil
+	} else if err != nil {
+		s.logger.Error("Failed to get user settings", "user", userID, "error", err)
+		return 0, err
+	}
+	return st.RemindBefore, nil
+}
+
+// SetRemindBefore сохраняет персональную настройку напоминаний. 0 — выключить.
+func (s *BookingService) SetRemindBefore(ctx context.Context, userID domain.UserID, d time.Duration) error {
+	if d < 0 || d > maxRemindBefore || d%time.Minute != 0 {
+		return domain.ErrInvalidInputData
+	}
+	if err := s.settingsRepo.Save(ctx, domain.UserSettings{UserID: userID, RemindBefore: d}); err != nil {
+		s.logger.Error("Failed to save user settings", "user", userID, "error", err)
+		return err
+	}
+	s.logger.Info("Reminder setting updated", "user", userID, "remind_before", d)
+	return nil
+}
@@ -0,0 +1,87 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+	"github.com/leegeev/KomaevBookingBot/pkg/config"
+	"github.com/leegeev/KomaevBookingBot/pkg/logger"
+)
+
+const (
+	// Сколько живёт незавершённый диалог без действий пользователя,
+	// если telegram.session_ttl не задан.
+	defaultSessionTTL = time.Hour
+	// Сколько хранить истёкшую сессию, чтобы отвечать «сессия истекла».
+	expiredSessionKeep = 24 * time.Hour
+)
+
+// SessionService хранит незавершённые диалоги пользователей, чтобы
+// они переживали рестарт бота. Состояние диалога — непрозрачный JSON.
+type SessionService struct {
+	repo   domain.SessionRepository
+	logger logger.Logger
+	ttl    time.Duration
+}
+
+func NewSessionService(repo domain.SessionRepository, logger logger.Logger, cfg config.Telegram) *SessionService {
+	ttl := cfg.SessionTTL
+	if ttl <= 0 {
+		ttl = defaultSessionTTL
+	}
+	return &SessionService{
+		repo:   repo,
+		logger: logger,
+		ttl:    ttl,
+	}
+}
+
+// LoadSession возвращает сессию, в том числе истёкшую (см. Session.Expired).
+// ErrSessionNotFound — сессии нет.
+func (s *SessionService) LoadSession(ctx context.Context, kind domain.SessionKind, userID int64) (domain.Session, error) {
+	sess, err := s.repo.GetSession(ctx, kind, userID)
+	if err != nil && !errors.Is(err, domain.ErrSessionNotFound) {
+		s.logger.Error("Failed to load session", "kind", kind, "userID", userID, "err", err)
+	}
+	return sess, err
+}
+
+// SaveSession сохраняет состояние диалога и продлевает его срок.
+// Возвращает новый срок истечения.
+func (s *SessionService) SaveSession(ctx context.Context, kind domain.SessionKind, userID int64, data []byte) (time.Time, error) {
+	now := time.Now()
+	sess := domain.Session{
+		Kind:      kind,
+		UserID:    userID,
+		Data:      data,
+		UpdatedAt: now,
+		ExpiresAt: now.Add(s.ttl),
+	}
+	if err := s.repo.SaveSession(ctx, sess); err != nil {
+		s.logger.Error("Failed to save session", "kind", kind, "userID", userID, "err", err)
+		return time.Time{}, err
+	}
+	return sess.ExpiresAt, nil
+}
+
+func (s *SessionService) DeleteSession(ctx context.Context, kind domain.SessionKind, userID int64) error {
+	if err := s.repo.DeleteSession(ctx, kind, userID); err != nil {
+		s.logger.Error("Failed to delete session", "kind", kind, "userID", userID, "err", err)
+		return err
+	}
+	return nil
+}
+
+// SweepSessions удаляет сессии, истёкшие больше суток назад.
+func (s *SessionService) SweepSessions(ctx context.Context) {
+	n, err := s.repo.DeleteExpiredSessions(ctx, time.Now().Add(-expiredSessionKeep))
+	if err != nil {
+		s.logger.Error("Failed to sweep sessions", "err", err)
+		return
+	}
+	if n > 0 {
+		s.logger.Info("Expired sessions swept", "count", n)
+	}
+}
@@ -2,6 +2,10 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/leegeev/KomaevBookingBot/internal/domain"
@@ -9,20 +13,30 @@ import (
 	"github.com/leegeev/KomaevBookingBot/pkg/logger"
 )
 
-func NewBookingService(roomRepo domain.RoomRepository, bookingRepo domain.BookingRepository, logger logger.Logger, cfg config.Telegram) *BookingService {
+func NewBookingService(roomRepo domain.RoomRepository, bookingRepo domain.BookingRepository, auditRepo domain.AuditRepository, settingsRepo domain.UserSettingsRepository, usernameRepo domain.UsernameRepository, logger logger.Logger, cfg config.Telegram, policy domain.BookingPolicy) *BookingService {
 	return &BookingService{
-		roomRepo:    roomRepo,
-		bookingRepo: bookingRepo,
-		logger:      logger,
-		cfg:         cfg,
+		roomRepo:     roomRepo,
+		bookingRepo:  bookingRepo,
+		auditRepo:    auditRepo,
+		settingsRepo: settingsRepo,
+		usernameRepo: usernameRepo,
+		logger:       logger,
+		cfg:          cfg,
+		policy:       policy,
 	}
 }
 
 type BookingService struct {
-	roomRepo    domain.RoomRepository
-	bookingRepo domain.BookingRepository
-	logger      logger.Logger
-	cfg         config.Telegram
+	roomRepo     domain.RoomRepository
+	bookingRepo  domain.BookingRepository
+	auditRepo    domain.AuditRepository
+	settingsRepo domain.UserSettingsRepository
+	usernameRepo domain.UsernameRepository
+	logger       logger.Logger
+	cfg          config.Telegram
+	policy       domain.BookingPolicy
+
+	usernames sync.Map // UserID -> последний сохранённый ник
 }
 
 type CreateBookingCmd struct {
@@ -32,6 +46,9 @@ type CreateBookingCmd struct {
 	UserName string
 	Start    time.Time // UTC
 	End      time.Time // UTC
+
+	Note      string            // цель встречи, необязательно
+	Attendees []domain.Attendee // участники, необязательно
 }
 
 func (s *BookingService) CreateBooking(ctx context.Context, cmd CreateBookingCmd) error {
@@ -45,6 +62,15 @@ func (s *BookingService) CreateBooking(ctx context.Context, cmd CreateBookingCmd
 		return err
 	}
 
+	// Check booking policy
+	if err := s.policy.Check(tr, time.Now(), s.cfg.OfficeTZ); err != nil {
+		s.logger.Warn("Booking violates policy", "error", err, "start", tr.Start, "end", tr.End)
+		return err
+	}
+	if err := s.checkActiveLimit(ctx, cmd.UserID, 1); err != nil {
+		return err
+	}
+
 	// Check if room exists
 	room, err := s.roomRepo.GetByID(ctx, cmd.RoomID)
 	if err == domain.ErrRoomNotFound {
@@ -64,6 +90,12 @@ func (s *BookingService) CreateBooking(ctx context.Context, cmd CreateBookingCmd
 		s.logger.Error("Failed to create booking entity", "error", err)
 		return err
 	}
+	booking.Note = cmd.Note
+	booking.Attendees = cmd.Attendees
+	if err := booking.ValidateDetails(); err != nil {
+		s.logger.Warn("Invalid booking details", "error", err)
+		return err
+	}
 
 	// Save booking to repository
 	err = s.bookingRepo.Create(ctx, booking)
@@ -76,42 +108,265 @@ func (s *BookingService) CreateBooking(ctx context.Context, cmd CreateBookingCmd
 	return nil
 }
 
-func (s *BookingService) CancelBooking(ctx context.Context, bookingID int64) error {
-	s.logger.Info("Canceling booking", "bookingID", bookingID)
-	if bookingID <= 0 {
-		s.logger.Error("Invalid booking ID", "bookingID", bookingID)
-		return domain.ErrInvalidInputData
+type CreateSeriesCmd struct {
+	CreateBookingCmd                       // первое повторение
+	Rule             domain.RecurrenceRule // правило повторения
+}
+
+// Результат создания серии. Пересекающиеся повторения не валят всю серию,
+// а возвращаются в Conflicts, чтобы показать пользователю по датам.
+// Повторения, нарушающие политику (выходной, дальше горизонта), — в Rejected.
+type SeriesResult struct {
+	SeriesID  domain.SeriesID
+	Created   []domain.TimeRange // в TZ офиса
+	Conflicts []domain.TimeRange // в TZ офиса
+	Rejected  []domain.TimeRange // в TZ офиса
+}
+
+func (s *BookingService) CreateRecurringBooking(ctx context.Context, cmd CreateSeriesCmd) (SeriesResult, error) {
+	s.logger.Info("Creating recurring booking", "user", cmd.UserID, "roomID", cmd.RoomID, "start", cmd.Start, "rule", cmd.Rule)
+
+	first, err := domain.NewTimeRange(cmd.Start, cmd.End)
+	if err != nil {
+		s.logger.Error("Invalid time range", "error", err)
+		return SeriesResult{}, err
 	}
-	if err := s.bookingRepo.Delete(ctx, domain.BookingID(bookingID)); err != nil {
-		s.logger.Error("Failed to cancel booking", "error", err)
+
+	occurrences, err := cmd.Rule.Occurrences(first, s.cfg.OfficeTZ)
+	if err != nil {
+		s.logger.Error("Invalid recurrence rule", "error", err, "rule", cmd.Rule)
+		return SeriesResult{}, err
+	}
+
+	// Первое повторение выбрано пользователем явно — его нарушение политики
+	// возвращаем как ошибку. Остальные просто пропускаем и показываем по датам.
+	now := time.Now()
+	if err := s.policy.Check(first, now, s.cfg.OfficeTZ); err != nil {
+		s.logger.Warn("Booking violates policy", "error", err, "start", first.Start, "end", first.End)
+		return SeriesResult{}, err
+	}
+	var res SeriesResult
+	allowed := make([]domain.TimeRange, 0, len(occurrences))
+	for _, tr := range occurrences {
+		if err := s.policy.Check(tr, now, s.cfg.OfficeTZ); err != nil {
+			res.Rejected = append(res.Rejected, s.rangeToLocal(tr))
+			continue
+		}
+		allowed = append(allowed, tr)
+	}
+	if err := s.checkActiveLimit(ctx, cmd.UserID, len(allowed)); err != nil {
+		return SeriesResult{}, err
+	}
+	details := domain.Booking{Note: cmd.Note, Attendees: cmd.Attendees}
+	if err := details.ValidateDetails(); err != nil {
+		s.logger.Warn("Invalid booking details", "error", err)
+		return SeriesResult{}, err
+	}
+
+	room, err := s.roomRepo.GetByID(ctx, cmd.RoomID)
+	if err != nil {
+		s.logger.Error("Failed to get room by ID", "error", err)
+		return SeriesResult{}, err
+	}
+	if !room.IsActive {
+		s.logger.Error("Room is not active", "roomID", cmd.RoomID)
+		return SeriesResult{}, domain.ErrRoomNotFound
+	}
+
+	bookings := make([]domain.Booking, 0, len(allowed))
+	for _, tr := range allowed {
+		booking, err := domain.NewBooking(cmd.RoomID, cmd.RoomName, cmd.UserID, cmd.UserName, tr)
+		if err != nil {
+			return res, err
+		}
+		booking.Note = cmd.Note
+		booking.Attendees = cmd.Attendees
+		bookings = append(bookings, booking)
+	}
+
+	// Серия и повторения — одна транзакция: если не создано ни одного
+	// повторения, серии в БД не остаётся
+	seriesID, conflicts, err := s.bookingRepo.CreateSeries(ctx, domain.BookingSeries{
+		RoomID:   cmd.RoomID,
+		RoomName: cmd.RoomName,
+		UserID:   cmd.UserID,
+		UserName: cmd.UserName,
+		Rule:     cmd.Rule,
+	}, bookings)
+	if errors.Is(err, domain.ErrOverlapsExisting) {
+		s.logger.Warn("All occurrences overlap existing bookings", "userID", cmd.UserID)
+		for _, tr := range allowed {
+			res.Conflicts = append(res.Conflicts, s.rangeToLocal(tr))
+		}
+		return res, err
+	} else if err != nil {
+		s.logger.Error("Failed to create series", "error", err)
+		return SeriesResult{}, err
+	}
+
+	res.SeriesID = seriesID
+	conflict := make(map[int]bool, len(conflicts))
+	for _, i := range conflicts {
+		conflict[i] = true
+	}
+	for i, tr := range allowed {
+		if conflict[i] {
+			res.Conflicts = append(res.Conflicts, s.rangeToLocal(tr))
+		} else {
+			res.Created = append(res.Created, s.rangeToLocal(tr))
+		}
+	}
+	s.logger.Info("Series created", "seriesID", seriesID, "created", len(res.Created), "conflicts", len(res.Conflicts), "rejected", len(res.Rejected))
+	return res, nil
+}
+
+func (s *BookingService) GetSeries(ctx context.Context, seriesID int64) (domain.BookingSeries, error) {
+	s.logger.Info("Getting series by ID", "seriesID", seriesID)
+	if seriesID <= 0 {
+		return domain.BookingSeries{}, domain.ErrInvalidInputData
+	}
+	series, err := s.bookingRepo.GetSeries(ctx, domain.SeriesID(seriesID))
+	if err != nil {
+		s.logger.Error("Failed to get series", "error", err)
+		return domain.BookingSeries{}, err
+	}
+	return series, nil
+}
+
+// CancelSeries отменяет все будущие повторения серии и возвращает их (в TZ офиса),
+// чтобы вызывающий мог уведомить участников. Уже начавшееся повторение не
+// отменяется. Отменить серию может её владелец или администратор чата.
+func (s *BookingService) CancelSeries(ctx context.Context, seriesID int64, actor domain.Actor) ([]domain.Booking, error) {
+	s.logger.Info("Canceling series", "seriesID", seriesID, "userID", actor.UserID, "role", actor.Role)
+	if seriesID <= 0 {
+		s.logger.Error("Invalid series ID", "seriesID", seriesID)
+		return nil, domain.ErrInvalidInputData
+	}
+
+	series, err := s.bookingRepo.GetSeries(ctx, domain.SeriesID(seriesID))
+	if err != nil {
+		s.logger.Error("Failed to get series", "error", err)
+		return nil, err
+	}
+	if !actor.CanManage(series.UserID) {
+		s.logger.Warn("User cannot cancel this series", "userID", actor.UserID, "seriesID", seriesID)
+		return nil, domain.ErrForbiddenCancellation
+	}
+
+	now := time.Now().UTC()
+	upcoming, err := s.bookingRepo.ListByUser(ctx, series.UserID, now)
+	if err != nil {
+		s.logger.Error("Failed to list series bookings", "error", err)
+		return nil, err
+	}
+	canceled := make([]domain.Booking, 0, len(upcoming))
+	for _, b := range upcoming {
+		// То же условие, что у DeleteSeries
+		if b.SeriesID == series.ID && b.Range.Start.After(now) {
+			canceled = append(canceled, s.toLocal(b))
+		}
+	}
+
+	n, err := s.bookingRepo.DeleteSeries(ctx, series.ID, now)
+	if err != nil {
+		s.logger.Error("Failed to cancel series", "error", err)
+		return nil, err
+	}
+	s.logger.Info("Series canceled", "seriesID", seriesID, "count", n)
+	return canceled, nil
+}
+
+type RescheduleBookingCmd struct {
+	BookingID domain.BookingID
+	Actor     domain.Actor  // владелец брони или администратор чата
+	RoomID    domain.RoomID // 0 — оставить текущую переговорку
+	Start     time.Time     // UTC
+	End       time.Time     // UTC
+}
+
+// RescheduleBooking переносит бронь на новое время (и, опционально, в другую переговорку).
+// Если новое время занято, старая бронь остаётся без изменений.
+func (s *BookingService) RescheduleBooking(ctx context.Context, cmd RescheduleBookingCmd) error {
+	s.logger.Info("Rescheduling booking", "bookingID", cmd.BookingID, "user", cmd.Actor.UserID, "roomID", cmd.RoomID, "start", cmd.Start, "end", cmd.End)
+
+	tr, err := domain.NewTimeRange(cmd.Start, cmd.End)
+	if err != nil {
+		s.logger.Error("Invalid time range", "error", err)
+		return err
+	}
+	if err := s.policy.Check(tr, time.Now(), s.cfg.OfficeTZ); err != nil {
+		s.logger.Warn("Booking violates policy", "error", err, "start", tr.Start, "end", tr.End)
+		return err
+	}
+
+	booking, err := s.bookingRepo.GetByID(ctx, cmd.BookingID)
+	if err != nil {
+		s.logger.Error("Failed to get booking", "error", err)
+		return err
+	}
+	if !cmd.Actor.CanManage(booking.UserID) {
+		s.logger.Warn("User cannot reschedule this booking", "userID", cmd.Actor.UserID, "bookingID", cmd.BookingID)
+		return domain.ErrForbiddenCancellation
+	}
+
+	roomID := cmd.RoomID
+	if roomID == 0 {
+		roomID = booking.RoomID
+	}
+	room, err := s.roomRepo.GetByID(ctx, roomID)
+	if err != nil {
+		s.logger.Error("Failed to get room by ID", "error", err)
+		return err
+	}
+	if !room.IsActive {
+		s.logger.Error("Room is not active", "roomID", roomID)
+		return domain.ErrRoomNotFound
+	}
+
+	if err := s.bookingRepo.UpdateRange(ctx, booking.ID, room.ID, room.Name, tr); err != nil {
+		if err != domain.ErrOverlapsExisting {
+			s.logger.Error("Failed to reschedule booking", "error", err)
+		}
 		return err
 	}
+	s.logger.Info("Booking rescheduled", "bookingID", cmd.BookingID)
 	return nil
 }
 
-func (s *BookingService) GetById(ctx context.Context, bookingID int64) (domain.Booking, error) {
-	s.logger.Info("Getting booking by ID", "bookingID", bookingID)
+// CancelBooking отменяет бронь и возвращает её (в TZ офиса), чтобы вызывающий мог
+// уведомить участников. Отменить может её владелец или администратор чата.
+func (s *BookingService) CancelBooking(ctx context.Context, bookingID int64, actor domain.Actor) (domain.Booking, error) {
+	s.logger.Info("Canceling booking", "bookingID", bookingID, "userID", actor.UserID, "role", actor.Role)
+	if bookingID <= 0 {
+		s.logger.Error("Invalid booking ID", "bookingID", bookingID)
+		return domain.Booking{}, domain.ErrInvalidInputData
+	}
+
 	booking, err := s.bookingRepo.GetByID(ctx, domain.BookingID(bookingID))
 	if err != nil {
 		s.logger.Error("Failed to get booking", "error", err)
 		return domain.Booking{}, err
 	}
+	if !actor.CanManage(booking.UserID) {
+		s.logger.Warn("User cannot cancel this booking", "userID", actor.UserID, "bookingID", bookingID)
+		return domain.Booking{}, domain.ErrForbiddenCancellation
+	}
+
+	if err := s.bookingRepo.Delete(ctx, domain.BookingID(bookingID)); err != nil {
+		s.logger.Error("Failed to cancel booking", "error", err)
+		return domain.Booking{}, err
+	}
 	return s.toLocal(booking), nil
 }
 
-func (s *BookingService) CheckBookingAndUserID(ctx context.Context, bookingID, userID int64) (bool, error) {
-	s.logger.Info("Checking if it is his booking")
+func (s *BookingService) GetById(ctx context.Context, bookingID int64) (domain.Booking, error) {
+	s.logger.Info("Getting booking by ID", "bookingID", bookingID)
 	booking, err := s.bookingRepo.GetByID(ctx, domain.BookingID(bookingID))
 	if err != nil {
 		s.logger.Error("Failed to get booking", "error", err)
-		return false, err
-	}
-	if booking.UserID != domain.UserID(userID) {
-		s.logger.Warn("User does not own this booking", "userID", userID, "bookingID", bookingID)
-		return false, domain.ErrNotOwner
+		return domain.Booking{}, err
 	}
-	s.logger.Info("User owns the booking", "userID", userID, "bookingID", bookingID)
-	return true, nil
+	return s.toLocal(booking), nil
 }
 
 func (s *BookingService) ListUserBookings(ctx context.Context, userID int64) ([]domain.Booking, error) {
@@ -175,9 +430,67 @@ func (s *BookingService) GetRoom(ctx context.Context, roomID int64) (domain.Room
 	return room, nil
 }
 
-// func (s *BookingService) AdminCancelBooking(ctx context.Context, bookingID int64) error {
-// 	return nil
-// }
+type AdminCancelCmd struct {
+	BookingID domain.BookingID
+	Actor     domain.Actor
+	ActorName string
+	Reason    string
+}
+
+// AdminCancelBooking отменяет любую бронь от имени администратора чата
+// и записывает действие в журнал аудита. Возвращает отменённую бронь (в TZ офиса),
+// чтобы вызывающий мог уведомить владельца.
+func (s *BookingService) AdminCancelBooking(ctx context.Context, cmd AdminCancelCmd) (domain.Booking, error) {
+	s.logger.Info("Admin canceling booking", "bookingID", cmd.BookingID, "admin", cmd.Actor.UserID)
+	if !cmd.Actor.IsAdmin() {
+		s.logger.Warn("Non-admin tried to cancel booking as admin", "userID", cmd.Actor.UserID, "bookingID", cmd.BookingID)
+		return domain.Booking{}, domain.ErrForbiddenCancellation
+	}
+	reason := strings.TrimSpace(cmd.Reason)
+	if reason == "" {
+		return domain.Booking{}, domain.ErrInvalidInputData
+	}
+
+	booking, err := s.bookingRepo.GetByID(ctx, cmd.BookingID)
+	if err != nil {
+		s.logger.Error("Failed to get booking", "error", err)
+		return domain.Booking{}, err
+	}
+	local := s.toLocal(booking)
+	entry := domain.AuditEntry{
+		ActorID:   cmd.Actor.UserID,
+		ActorName: cmd.ActorName,
+		Action:    domain.AuditAdminCancelBooking,
+		BookingID: booking.ID,
+		OwnerID:   booking.UserID,
+		Reason:    reason,
+		Details: fmt.Sprintf("%s, %s %s-%s, %s",
+			local.RoomName,
+			local.Range.Start.Format("02.01.2006"),
+			local.Range.Start.Format("15:04"),
+			local.Range.End.Format("15:04"),
+			local.UserName,
+		),
+	}
+	// Удаление и аудит — одна транзакция: чужая бронь не исчезает без следа
+	if err := s.bookingRepo.DeleteWithAudit(ctx, booking.ID, entry); err != nil {
+		s.logger.Error("Failed to cancel booking as admin", "error", err, "entry", entry)
+		return domain.Booking{}, err
+	}
+	s.logger.Info("Booking canceled by admin", "bookingID", booking.ID, "owner", booking.UserID)
+	return local, nil
+}
+
+// ListRoomUpcoming возвращает ещё не закончившиеся брони переговорки в пределах
+// горизонта бронирования (или 30 дней, если горизонт не ограничен).
+func (s *BookingService) ListRoomUpcoming(ctx context.Context, roomID int64) ([]domain.Booking, error) {
+	days := s.policy.MaxDaysAhead
+	if days <= 0 {
+		days = 30
+	}
+	now := time.Now().UTC()
+	return s.ListRoomBookings(ctx, roomID, now, now.AddDate(0, 0, days+1))
+}
 
 func (s *BookingService) AdminCreateRoom(ctx context.Context, name string) error {
 	room, err := s.roomRepo.GetByName(ctx, name)
@@ -214,16 +527,108 @@ func (s *BookingService) AdminDeleteRoom(ctx context.Context, roomID int64) erro
 	return nil
 }
 
-// func (s *BookingService) FreeSlots(ctx context.Context, roomID domain.RoomID, day time.Time, step time.Duration) ([]domain.TimeRange, error)
+func (s *BookingService) Policy() domain.BookingPolicy { return s.policy }
+
+// FreeSlots возвращает свободные промежутки переговорки на день day в рабочие часы,
+// выровненные по сетке step (0 — шаг из политики). Прошедшее время считается занятым.
+func (s *BookingService) FreeSlots(ctx context.Context, roomID domain.RoomID, day time.Time, step time.Duration) ([]domain.TimeRange, error) {
+	s.logger.Info("Calculating free slots", "roomID", roomID, "day", day, "step", step)
+	if roomID <= 0 {
+		return nil, domain.ErrInvalidInputData
+	}
+	if step <= 0 {
+		step = s.policy.Step
+	}
+
+	window, open := s.policy.HoursOn(day, s.cfg.OfficeTZ)
+	if !open {
+		return nil, nil
+	}
+	bookings, err := s.bookingRepo.ListByRoomAndInterval(ctx, roomID, window.Start, window.End)
+	if err != nil {
+		s.logger.Error("Failed to list room bookings", "error", err)
+		return nil, err
+	}
+
+	busy := make([]domain.TimeRange, 0, len(bookings)+1)
+	if now := time.Now().UTC(); now.After(window.Start) {
+		busy = append(busy, domain.TimeRange{Start: window.Start, End: now})
+	}
+	for _, b := range bookings {
+		busy = append(busy, b.Range)
+	}
+
+	free := domain.FreeRanges(window, busy, step)
+	for i := range free {
+		free[i] = s.rangeToLocal(free[i])
+	}
+	s.logger.Info("Free slots calculated", "roomID", roomID, "count", len(free))
+	return free, nil
+}
+
+// FreeRooms возвращает активные переговорки, свободные в [start, start+dur).
+func (s *BookingService) FreeRooms(ctx context.Context, start time.Time, dur time.Duration) ([]domain.Room, error) {
+	s.logger.Info("Searching free rooms", "start", start, "duration", dur)
+	tr, err := domain.NewTimeRange(start, start.Add(dur))
+	if err != nil {
+		return nil, err
+	}
+	// Свободной считаем только переговорку, которую в это время можно забронировать
+	if err := s.policy.Check(tr, time.Now(), s.cfg.OfficeTZ); err != nil {
+		s.logger.Warn("Free rooms range violates policy", "error", err, "start", tr.Start, "end", tr.End)
+		return nil, err
+	}
+
+	rooms, err := s.ListRooms(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	free := make([]domain.Room, 0, len(rooms))
+	for _, room := range rooms {
+		busy, err := s.bookingRepo.AnyOverlap(ctx, room.ID, tr)
+		if err != nil {
+			s.logger.Error("Failed to check overlap", "roomID", room.ID, "error", err)
+			return nil, err
+		}
+		if !busy {
+			free = append(free, room)
+		}
+	}
+	s.logger.Info("Found free rooms", "count", len(free))
+	return free, nil
+}
 
 // getChatMember). Если status ∈ {creator, administrator, member} — добавляем/обновляем в users_whitelist
 
+// checkActiveLimit проверяет, что после добавления add броней пользователь
+// не превысит лимит незакончившихся броней из политики.
+func (s *BookingService) checkActiveLimit(ctx context.Context, userID domain.UserID, add int) error {
+	if s.policy.MaxActivePerUser <= 0 {
+		return nil
+	}
+	active, err := s.bookingRepo.ListByUser(ctx, userID, time.Now().UTC())
+	if err != nil {
+		s.logger.Error("Failed to list user bookings", "error", err)
+		return err
+	}
+	if len(active)+add > s.policy.MaxActivePerUser {
+		s.logger.Warn("Active bookings limit exceeded", "userID", userID, "active", len(active), "add", add)
+		return domain.ErrTooManyActiveBookings
+	}
+	return nil
+}
+
 func (s *BookingService) toLocal(b domain.Booking) domain.Booking {
 	b.Range.Start = b.Range.Start.In(s.cfg.OfficeTZ)
 	b.Range.End = b.Range.End.In(s.cfg.OfficeTZ)
 	return b
 }
 
+func (s *BookingService) rangeToLocal(tr domain.TimeRange) domain.TimeRange {
+	return domain.TimeRange{Start: tr.Start.In(s.cfg.OfficeTZ), End: tr.End.In(s.cfg.OfficeTZ)}
+}
+
 func (s *BookingService) toLocalSlice(list []domain.Booking) []domain.Booking {
 	for i := range list {
 		list[i] = s.toLocal(list[i])
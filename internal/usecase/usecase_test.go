@@ -0,0 +1,216 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+const (
+	testOwner domain.UserID = 100
+	testOther domain.UserID = 200
+)
+
+func newTestBookingService(bookings *fakeBookingRepo) *BookingService {
+	rooms := &fakeRoomRepo{rooms: map[domain.RoomID]domain.Room{
+		1: {ID: 1, Name: "Переговорка 1", IsActive: true},
+	}}
+	return NewBookingService(rooms, bookings, nil, nil, nil, testLogger(), testTelegramConfig(), domain.BookingPolicy{})
+}
+
+func testBooking(id domain.BookingID, owner domain.UserID, series domain.SeriesID, start time.Time) domain.Booking {
+	return domain.Booking{
+		ID:       id,
+		RoomID:   1,
+		RoomName: "Переговорка 1",
+		UserID:   owner,
+		SeriesID: series,
+		Range:    domain.TimeRange{Start: start.UTC(), End: start.Add(time.Hour).UTC()},
+	}
+}
+
+// Идущее сейчас повторение не удаляется и не попадает в список отменённых.
+func TestCancelSeriesSkipsOccurrenceInProgress(t *testing.T) {
+	now := time.Now()
+	repo := &fakeBookingRepo{
+		series: map[domain.SeriesID]domain.BookingSeries{7: {ID: 7, UserID: testOwner}},
+		bookings: map[domain.BookingID]domain.Booking{
+			1: testBooking(1, testOwner, 7, now.Add(-2*time.Hour)),    // прошло
+			2: testBooking(2, testOwner, 7, now.Add(-30*time.Minute)), // идёт
+			3: testBooking(3, testOwner, 7, now.Add(24*time.Hour)),
+			4: testBooking(4, testOwner, 7, now.Add(48*time.Hour)),
+			5: testBooking(5, testOwner, 0, now.Add(24*time.Hour)), // вне серии
+		},
+	}
+	s := newTestBookingService(repo)
+
+	canceled, err := s.CancelSeries(context.Background(), 7, domain.Actor{UserID: testOwner, Role: domain.RoleMember})
+	if err != nil {
+		t.Fatalf("CancelSeries: %v", err)
+	}
+
+	var got []domain.BookingID
+	for _, b := range canceled {
+		got = append(got, b.ID)
+	}
+	sort.Slice(got, func(i, j int) bool { return got[i] < got[j] })
+	if len(got) != 2 || got[0] != 3 || got[1] != 4 {
+		t.Fatalf("canceled = %v, want [3 4]", got)
+	}
+	for _, id := range []domain.BookingID{1, 2, 5} {
+		if _, ok := repo.bookings[id]; !ok {
+			t.Errorf("booking %d was deleted", id)
+		}
+	}
+	for _, id := range got {
+		if _, ok := repo.bookings[id]; ok {
+			t.Errorf("canceled booking %d is still there", id)
+		}
+	}
+}
+
+func TestCancelSeriesForbidden(t *testing.T) {
+	repo := &fakeBookingRepo{
+		series: map[domain.SeriesID]domain.BookingSeries{7: {ID: 7, UserID: testOwner}},
+	}
+	s := newTestBookingService(repo)
+
+	_, err := s.CancelSeries(context.Background(), 7, domain.Actor{UserID: testOther, Role: domain.RoleMember})
+	if !errors.Is(err, domain.ErrForbiddenCancellation) {
+		t.Fatalf("err = %v, want ErrForbiddenCancellation", err)
+	}
+	if !repo.deletedFrom.IsZero() {
+		t.Fatal("series was deleted")
+	}
+}
+
+func TestCreateRecurringBookingConflicts(t *testing.T) {
+	n := time.Now().UTC()
+	tomorrow := time.Date(n.Year(), n.Month(), n.Day()+1, 10, 0, 0, 0, time.UTC)
+	day := func(i int) time.Time { return tomorrow.AddDate(0, 0, i) }
+	cmd := CreateSeriesCmd{
+		CreateBookingCmd: CreateBookingCmd{
+			RoomID: 1, RoomName: "Переговорка 1", UserID: testOwner, UserName: "owner",
+			Start: tomorrow, End: tomorrow.Add(time.Hour),
+		},
+		Rule: domain.RecurrenceRule{Freq: domain.RecurDaily, Count: 4},
+	}
+
+	tests := []struct {
+		name          string
+		maxDaysAhead  int
+		conflicts     []int // индексы среди повторений, прошедших политику
+		wantErr       error
+		wantCreated   []time.Time
+		wantConflicts []time.Time
+		wantRejected  []time.Time
+	}{
+		{
+			name:        "no conflicts",
+			wantCreated: []time.Time{day(0), day(1), day(2), day(3)},
+		},
+		{
+			name:          "some occurrences overlap",
+			conflicts:     []int{1, 3},
+			wantCreated:   []time.Time{day(0), day(2)},
+			wantConflicts: []time.Time{day(1), day(3)},
+		},
+		{
+			name:          "all occurrences overlap",
+			conflicts:     []int{0, 1, 2, 3},
+			wantErr:       domain.ErrOverlapsExisting,
+			wantConflicts: []time.Time{day(0), day(1), day(2), day(3)},
+		},
+		{
+			// Горизонт — послезавтра: последние два повторения отклоняет политика,
+			// индексы конфликтов считаются только среди оставшихся
+			name:          "beyond horizon and overlap",
+			maxDaysAhead:  2,
+			conflicts:     []int{1},
+			wantCreated:   []time.Time{day(0)},
+			wantConflicts: []time.Time{day(1)},
+			wantRejected:  []time.Time{day(2), day(3)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeBookingRepo{conflicts: tt.conflicts}
+			s := newTestBookingService(repo)
+			s.policy.MaxDaysAhead = tt.maxDaysAhead
+
+			res, err := s.CreateRecurringBooking(context.Background(), cmd)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && res.SeriesID == 0 {
+				t.Error("SeriesID is not set")
+			}
+			if want := len(tt.wantCreated) + len(tt.wantConflicts); len(repo.seriesCreated) != want {
+				t.Errorf("repository got %d occurrences, want %d", len(repo.seriesCreated), want)
+			}
+			assertStarts(t, "created", res.Created, tt.wantCreated)
+			assertStarts(t, "conflicts", res.Conflicts, tt.wantConflicts)
+			assertStarts(t, "rejected", res.Rejected, tt.wantRejected)
+		})
+	}
+}
+
+func assertStarts(t *testing.T, what string, got []domain.TimeRange, want []time.Time) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: got %d ranges, want %d: %v", what, len(got), len(want), got)
+		return
+	}
+	for i := range got {
+		if !got[i].Start.Equal(want[i]) {
+			t.Errorf("%s[%d] starts at %v, want %v", what, i, got[i].Start, want[i])
+		}
+	}
+}
+
+func TestCancelBookingOwnership(t *testing.T) {
+	start := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		bookingID int64
+		actor     domain.Actor
+		wantErr   error
+	}{
+		{"owner", 1, domain.Actor{UserID: testOwner, Role: domain.RoleMember}, nil},
+		{"admin", 1, domain.Actor{UserID: testOther, Role: domain.RoleAdmin}, nil},
+		{"other member", 1, domain.Actor{UserID: testOther, Role: domain.RoleMember}, domain.ErrForbiddenCancellation},
+		{"unknown booking", 2, domain.Actor{UserID: testOwner, Role: domain.RoleMember}, domain.ErrBookingNotFound},
+		{"invalid id", 0, domain.Actor{UserID: testOwner, Role: domain.RoleMember}, domain.ErrInvalidInputData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeBookingRepo{bookings: map[domain.BookingID]domain.Booking{
+				1: testBooking(1, testOwner, 0, start),
+			}}
+			s := newTestBookingService(repo)
+
+			canceled, err := s.CancelBooking(context.Background(), tt.bookingID, tt.actor)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if len(repo.deleted) != 0 {
+					t.Fatalf("deleted %v after a rejected cancellation", repo.deleted)
+				}
+				return
+			}
+			if canceled.ID != 1 {
+				t.Errorf("canceled booking %d, want 1", canceled.ID)
+			}
+			if len(repo.deleted) != 1 || repo.deleted[0] != 1 {
+				t.Errorf("deleted = %v, want [1]", repo.deleted)
+			}
+		})
+	}
+}
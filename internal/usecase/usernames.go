@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/leegeev/KomaevBookingBot/internal/domain"
+)
+
+// Ники пользователей для упоминаний участников. Имя из броней для этого
+// не годится: там может быть и имя без ника, и ник, который с тех пор
+// перешёл к другому человеку.
+
+func normUsername(username string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@"))
+}
+
+// RememberUsername запоминает текущий ник пользователя. Вызывается на
+// каждом апдейте, поэтому в БД пишет, только когда ник изменился.
+func (s *BookingService) RememberUsername(ctx context.Context, userID domain.UserID, username string) {
+	username = normUsername(username)
+	if prev, ok := s.usernames.Load(userID); ok && prev.(string) == username {
+		return
+	}
+	if err := s.usernameRepo.SaveUsername(ctx, userID, username); err != nil {
+		s.logger.Error("Failed to save username", "userID", userID, "username", username, "error", err)
+		return
+	}
+	s.usernames.Store(userID, username)
+}
+
+// LookupUserID — владелец ника (с @ или без). ErrUserNotFound, если бот
+// такого ника не видел.
+func (s *BookingService) LookupUserID(ctx context.Context, username string) (domain.UserID, error) {
+	norm := normUsername(username)
+	if norm == "" {
+		return 0, domain.ErrUserNotFound
+	}
+	id, err := s.usernameRepo.FindUserIDByUsername(ctx, norm)
+	if err != nil && !errors.Is(err, domain.ErrUserNotFound) {
+		s.logger.Error("Failed to find user by username", "username", username, "error", err)
+	}
+	return id, err
+}
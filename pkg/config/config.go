@@ -21,21 +21,75 @@ type DB struct {
 	SSLMode    string        `mapstructure:"sslmode"`
 	RetryCount int           `mapstructure:"retry_count"`
 	RetryDelay time.Duration `mapstructure:"retry_delay"`
+	// Применять миграции из scripts/ при старте (по умолчанию — да, в том числе
+	// для старых config.yaml без этого ключа). Если выключено, бот не стартует,
+	// пока схема не обновлена командой migrate up.
+	AutoMigrate bool `mapstructure:"auto_migrate"`
 }
 
 type Telegram struct {
-	Token          string `mapstructure:"token"`
-	OfficeTZ       *time.Location
-	OfficeTZString string        `mapstructure:"office_tz"`
-	GroupChatID    int64         `mapstructure:"group_chat_id"` // ID группы для проверки админства
-	AdminID        int64         `mapstructure:"admin_id"`      // ID админа для уведомлений
-	NotifierConfig string        `mapstructure:"notifier_config"`
-	RoleCacheTTL   time.Duration `mapstructure:"role_cache_ttl"`
+	Token           string `mapstructure:"token"`
+	OfficeTZ        *time.Location
+	OfficeTZString  string        `mapstructure:"office_tz"`
+	GroupChatID     int64         `mapstructure:"group_chat_id"` // ID группы для проверки админства
+	AdminID         int64         `mapstructure:"admin_id"`      // ID админа для уведомлений
+	NotifierConfig  string        `mapstructure:"notifier_config"`
+	RoleCacheTTL    time.Duration `mapstructure:"role_cache_ttl"`
+	ReminderTick    string        `mapstructure:"reminder_tick"`     // как часто искать брони для напоминания
+	ReminderBefore  time.Duration `mapstructure:"reminder_before"`   // за сколько напоминать по умолчанию
+	CheckinTick     string        `mapstructure:"checkin_tick"`      // как часто освобождать брони без check-in
+	LogEditWindow   time.Duration `mapstructure:"log_edit_window"`   // сколько автор может править свою запись журнала
+	LogNumberFormat string        `mapstructure:"log_number_format"` // шаблон номера записи журнала, см. domain.LogNumberFormat
+	LogNumberWidth  int           `mapstructure:"log_number_width"`  // до скольких цифр дополнять порядковый номер нулями
+	SessionTTL      time.Duration `mapstructure:"session_ttl"`       // сколько живёт незавершённый диалог без действий пользователя
+	SessionSweep    string        `mapstructure:"session_sweep"`     // как часто удалять истёкшие сессии (cron)
+
+	// Обработка апдейтов
+	Workers         int           `mapstructure:"workers"`          // сколько пользователей обслуживается параллельно
+	WorkerQueue     int           `mapstructure:"worker_queue"`     // размер очереди апдейтов одного воркера
+	UpdateTimeout   time.Duration `mapstructure:"update_timeout"`   // таймаут обработки одного апдейта
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"` // сколько при остановке ждать обработки принятых апдейтов
+
+	Mode    string  `mapstructure:"mode"` // ModePolling или ModeWebhook
+	Webhook Webhook `mapstructure:"webhook"`
+}
+
+// Способ получения апдейтов
+const (
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
+)
+
+// Режим webhook. Без cert_file/key_file сервер слушает plain HTTP и
+// рассчитан на работу за reverse proxy, который терминирует TLS.
+type Webhook struct {
+	URL            string `mapstructure:"url"`          // публичный адрес эндпоинта; пусто — не регистрировать вебхук (локальная отладка)
+	Listen         string `mapstructure:"listen"`       // адрес HTTP-сервера, например ":8080"
+	Path           string `mapstructure:"path"`         // путь эндпоинта; по умолчанию — путь из url
+	SecretToken    string `mapstructure:"secret_token"` // проверяется в заголовке X-Telegram-Bot-Api-Secret-Token
+	CertFile       string `mapstructure:"cert_file"`    // TLS на самом боте
+	KeyFile        string `mapstructure:"key_file"`
+	UploadCert     bool   `mapstructure:"upload_cert"`          // отправить cert_file в Telegram (самоподписанный сертификат)
+	MaxConnections int    `mapstructure:"max_connections"`      // 0 — по умолчанию Telegram (40)
+	DropPending    bool   `mapstructure:"drop_pending_updates"` // сбросить накопившиеся апдейты при установке вебхука
+}
+
+// Политика бронирования. Нулевые ограничения — «без ограничения».
+type Booking struct {
+	WorkHours        string            `mapstructure:"work_hours"`    // "09:00-21:00" в TZ офиса
+	WeekdayHours     map[string]string `mapstructure:"weekday_hours"` // mon..sun: "10:00-16:00" или "closed"
+	SlotStep         time.Duration     `mapstructure:"slot_step"`     // шаг сетки начала и длительности
+	MinDuration      time.Duration     `mapstructure:"min_duration"`
+	MaxDuration      time.Duration     `mapstructure:"max_duration"`
+	MaxDaysAhead     int               `mapstructure:"max_days_ahead"`
+	MaxActivePerUser int               `mapstructure:"max_active_per_user"`
+	CheckinGrace     time.Duration     `mapstructure:"checkin_grace"` // 0 — check-in не требуется
 }
 
 type Config struct {
 	DB       DB       `mapstructure:"database"`
 	Telegram Telegram `mapstructure:"telegram"`
+	Booking  Booking  `mapstructure:"booking"`
 }
 
 // pkg/config/config.go
@@ -59,6 +113,8 @@ func LoadConfig(logger logger.Logger) (*Config, error) {
 		viper.SetConfigName("config")
 	}
 
+	viper.SetDefault("database.auto_migrate", true)
+
 	viper.AutomaticEnv()
 	viper.AllowEmptyEnv(true)
 	bindEnv()
@@ -81,6 +137,14 @@ func LoadConfig(logger logger.Logger) (*Config, error) {
 		}
 		cfg.Telegram.OfficeTZ = loc
 	}
+	switch cfg.Telegram.Mode {
+	case "":
+		cfg.Telegram.Mode = ModePolling
+	case ModePolling, ModeWebhook:
+	default:
+		logger.Error("Unknown telegram mode", "mode", cfg.Telegram.Mode)
+		return nil, fmt.Errorf("неизвестный telegram.mode %q: ожидается %s или %s", cfg.Telegram.Mode, ModePolling, ModeWebhook)
+	}
 	return &cfg, nil
 }
 
@@ -97,7 +161,26 @@ func bindEnv() {
 	_ = viper.BindEnv("telegram.token", "TELEGRAM_TOKEN")
 	_ = viper.BindEnv("telegram.group_chat_id", "TELEGRAM_GROUP_CHAT_ID")
 	_ = viper.BindEnv("telegram.admin_id", "TELEGRAM_ADMIN_ID")
+	_ = viper.BindEnv("telegram.mode", "TELEGRAM_MODE")
+	_ = viper.BindEnv("telegram.webhook.url", "TELEGRAM_WEBHOOK_URL")
+	_ = viper.BindEnv("telegram.webhook.secret_token", "TELEGRAM_WEBHOOK_SECRET")
+
+}
 
+// Redacted — копия конфига для логов: токен бота, секрет вебхука и пароль БД
+// заменены заглушкой.
+func (c Config) Redacted() Config {
+	c.DB.Password = redact(c.DB.Password)
+	c.Telegram.Token = redact(c.Telegram.Token)
+	c.Telegram.Webhook.SecretToken = redact(c.Telegram.Webhook.SecretToken)
+	return c
+}
+
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "***"
 }
 
 func (c *DB) DSN() string {
@@ -0,0 +1,26 @@
+package config
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// config.yaml, написанный до появления auto_migrate, не должен выключать миграции.
+func TestLoadConfigAutoMigrateDefault(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("database:\n  host: localhost\ntelegram:\n  office_tz: UTC\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_PATH", path)
+
+	cfg, err := LoadConfig(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if !cfg.DB.AutoMigrate {
+		t.Fatal("auto_migrate = false, want true when the key is missing")
+	}
+}
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS bookings;
+DROP TABLE IF EXISTS rooms;
+-- btree_gist не удаляем: расширение могло понадобиться не только нам
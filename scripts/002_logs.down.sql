@@ -0,0 +1,7 @@
+-- ===============================================
+-- 002_logs.down.sql
+-- ===============================================
+
+DROP TABLE IF EXISTS soglasheniya;
+DROP TABLE IF EXISTS zaprosy;
+DROP TABLE IF EXISTS users;
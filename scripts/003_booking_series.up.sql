@@ -0,0 +1,24 @@
+-- ===============================================
+-- 003_booking_series.up.sql
+-- Повторяющиеся брони (ежедневно / по будням / еженедельно / раз в 2 недели)
+-- ===============================================
+
+CREATE TABLE IF NOT EXISTS booking_series (
+    id          SERIAL PRIMARY KEY,
+    room_id     INT NOT NULL,
+    room_name   TEXT NOT NULL,
+    user_id     BIGINT NOT NULL,
+    user_name   TEXT NOT NULL,
+    freq        TEXT NOT NULL,                 -- daily | weekdays | weekly | biweekly
+    count       INT NOT NULL DEFAULT 0,        -- число повторений (0, если задан until)
+    until       DATE,                          -- последняя дата серии включительно
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Каждое повторение — обычная бронь со ссылкой на серию,
+-- поэтому bookings_no_overlap продолжает работать для каждого повторения.
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS series_id INT REFERENCES booking_series(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_series_id
+    ON bookings (series_id);
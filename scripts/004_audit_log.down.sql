@@ -0,0 +1,5 @@
+-- ===============================================
+-- 004_audit_log.down.sql
+-- ===============================================
+
+DROP TABLE IF EXISTS audit_log;
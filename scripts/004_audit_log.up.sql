@@ -0,0 +1,19 @@
+-- ===============================================
+-- 004_audit_log.up.sql
+-- Журнал аудита административных действий (отмена чужих броней и т.п.)
+-- ===============================================
+
+CREATE TABLE IF NOT EXISTS audit_log (
+    id          BIGSERIAL PRIMARY KEY,
+    actor_id    BIGINT NOT NULL,               -- кто выполнил действие
+    actor_name  TEXT NOT NULL,
+    action      TEXT NOT NULL,                 -- admin_cancel_booking | ...
+    booking_id  BIGINT,                        -- бронь уже может быть удалена, поэтому без FK
+    owner_id    BIGINT,                        -- владелец брони
+    reason      TEXT NOT NULL DEFAULT '',
+    details     TEXT NOT NULL DEFAULT '',      -- снимок брони: переговорка и время
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_created_at
+    ON audit_log (created_at);
@@ -0,0 +1,10 @@
+-- ===============================================
+-- 005_booking_details.down.sql
+-- ===============================================
+
+DROP INDEX IF EXISTS idx_bookings_user_name_lower;
+
+DROP TABLE IF EXISTS booking_attendees;
+
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS note;
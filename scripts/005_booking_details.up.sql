@@ -0,0 +1,21 @@
+-- ===============================================
+-- 005_booking_details.up.sql
+-- Цель встречи и участники брони
+-- ===============================================
+
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS note TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS booking_attendees (
+    id          SERIAL PRIMARY KEY,
+    booking_id  INT NOT NULL REFERENCES bookings(id) ON DELETE CASCADE,
+    user_id     BIGINT,                        -- NULL, если участник указан просто именем
+    name        TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_booking_attendees_booking_id
+    ON booking_attendees (booking_id);
+
+-- Поиск пользователя по нику (@username) среди тех, кто уже бронировал
+CREATE INDEX IF NOT EXISTS idx_bookings_user_name_lower
+    ON bookings (lower(user_name));
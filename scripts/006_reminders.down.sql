@@ -0,0 +1,10 @@
+-- ===============================================
+-- 006_reminders.down.sql
+-- ===============================================
+
+DROP TABLE IF EXISTS user_settings;
+
+DROP INDEX IF EXISTS idx_bookings_pending_reminders;
+
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS reminded_at;
@@ -0,0 +1,19 @@
+-- ===============================================
+-- 006_reminders.up.sql
+-- Напоминания о начале брони и пользовательские настройки
+-- ===============================================
+
+-- Момент, когда напоминание было «забрано» на отправку. Отметка ставится
+-- атомарно до отправки, поэтому после рестарта напоминание не повторится.
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS reminded_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_pending_reminders
+    ON bookings (lower(time_range))
+    WHERE reminded_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS user_settings (
+    user_id                BIGINT PRIMARY KEY,
+    remind_before_minutes  INT NOT NULL,        -- 0 — напоминания выключены
+    updated_at             TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,8 @@
+-- ===============================================
+-- 007_checkin.down.sql
+-- ===============================================
+
+DROP INDEX IF EXISTS idx_bookings_pending_checkin;
+
+ALTER TABLE bookings
+    DROP COLUMN IF EXISTS checked_in_at;
@@ -0,0 +1,13 @@
+-- ===============================================
+-- 007_checkin.up.sql
+-- Отметка о приходе на встречу (check-in)
+-- ===============================================
+
+-- Если после начала брони никто не отметился в течение booking.checkin_grace,
+-- остаток брони освобождается: time_range обрезается до момента освобождения.
+ALTER TABLE bookings
+    ADD COLUMN IF NOT EXISTS checked_in_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_bookings_pending_checkin
+    ON bookings (lower(time_range))
+    WHERE checked_in_at IS NULL;
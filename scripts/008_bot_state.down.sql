@@ -0,0 +1,6 @@
+-- ===============================================
+-- 008_bot_state.down.sql
+-- ===============================================
+
+DROP TABLE IF EXISTS bot_schedule_messages;
+DROP TABLE IF EXISTS bot_group_chats;
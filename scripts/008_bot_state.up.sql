@@ -0,0 +1,20 @@
+-- ===============================================
+-- 008_bot_state.up.sql
+-- Состояние бота, которое должно переживать рестарт
+-- ===============================================
+
+-- Групповые чаты, зарегистрированные командой /register.
+CREATE TABLE IF NOT EXISTS bot_group_chats (
+    chat_id        BIGINT PRIMARY KEY,
+    registered_by  BIGINT NOT NULL,
+    registered_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Сообщение с расписанием на день, которое бот редактирует при изменениях.
+CREATE TABLE IF NOT EXISTS bot_schedule_messages (
+    chat_id     BIGINT NOT NULL,
+    day         DATE   NOT NULL,   -- дата в TZ офиса
+    message_id  BIGINT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    PRIMARY KEY (chat_id, day)
+);
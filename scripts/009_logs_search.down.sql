@@ -0,0 +1,9 @@
+-- ===============================================
+-- 009_logs_search.down.sql
+-- ===============================================
+
+DROP INDEX IF EXISTS idx_soglasheniya_date;
+DROP INDEX IF EXISTS idx_zaprosy_date;
+DROP INDEX IF EXISTS idx_soglasheniya_search_trgm;
+DROP INDEX IF EXISTS idx_zaprosy_search_trgm;
+-- pg_trgm не удаляем: расширение могло понадобиться не только нам
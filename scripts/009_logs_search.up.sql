@@ -0,0 +1,17 @@
+-- ===============================================
+-- 009_logs_search.up.sql
+-- Поиск по доверителю и комментарию в журналах
+-- ===============================================
+
+-- Триграммы: подстрочный поиск без учёта регистра (ILIKE '%иванов%')
+-- работает по индексу и не требует словарей морфологии.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS idx_soglasheniya_search_trgm
+    ON soglasheniya USING gin ((coalesce(doveritel, '') || ' ' || coalesce(comment, '')) gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_zaprosy_search_trgm
+    ON zaprosy USING gin ((coalesce(doveritel, '') || ' ' || coalesce(comment, '')) gin_trgm_ops);
+
+CREATE INDEX IF NOT EXISTS idx_soglasheniya_date ON soglasheniya (date DESC);
+CREATE INDEX IF NOT EXISTS idx_zaprosy_date ON zaprosy (date DESC);
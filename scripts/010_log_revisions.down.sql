@@ -0,0 +1,17 @@
+-- ===============================================
+-- 010_log_revisions.down.sql
+-- Внимание: удаляет историю изменений и отметки об аннулировании
+-- ===============================================
+
+DROP TABLE IF EXISTS log_revisions;
+DROP FUNCTION IF EXISTS log_revisions_immutable();
+
+ALTER TABLE soglasheniya
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS voided_at,
+    DROP COLUMN IF EXISTS void_reason;
+
+ALTER TABLE zaprosy
+    DROP COLUMN IF EXISTS updated_at,
+    DROP COLUMN IF EXISTS voided_at,
+    DROP COLUMN IF EXISTS void_reason;
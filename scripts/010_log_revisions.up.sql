@@ -0,0 +1,46 @@
+-- ===============================================
+-- 010_log_revisions.up.sql
+-- Правка и аннулирование записей журналов с историей изменений
+-- ===============================================
+
+-- Записи не удаляются: нумерация должна оставаться сплошной.
+-- Аннулированная запись остаётся в журнале с отметкой и причиной.
+ALTER TABLE soglasheniya
+    ADD COLUMN IF NOT EXISTS updated_at  TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS voided_at   TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS void_reason TEXT;
+
+ALTER TABLE zaprosy
+    ADD COLUMN IF NOT EXISTS updated_at  TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS voided_at   TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS void_reason TEXT;
+
+-- История изменений. Одна строка — одно изменение поля или аннулирование.
+CREATE TABLE IF NOT EXISTS log_revisions (
+    id          BIGSERIAL PRIMARY KEY,
+    kind        TEXT   NOT NULL CHECK (kind IN ('sogl', 'zapros')),
+    record_id   BIGINT NOT NULL,
+    actor_id    BIGINT NOT NULL,
+    actor_name  TEXT   NOT NULL,
+    action      TEXT   NOT NULL CHECK (action IN ('edit', 'void')),
+    field       TEXT   NOT NULL DEFAULT '',   -- doveritel | comment, пусто для void
+    old_value   TEXT   NOT NULL DEFAULT '',
+    new_value   TEXT   NOT NULL DEFAULT '',
+    reason      TEXT   NOT NULL DEFAULT '',
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_log_revisions_record
+    ON log_revisions (kind, record_id, id);
+
+-- История неизменяема: запрещаем UPDATE и DELETE на уровне БД.
+CREATE OR REPLACE FUNCTION log_revisions_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'log_revisions is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_log_revisions_immutable ON log_revisions;
+CREATE TRIGGER trg_log_revisions_immutable
+    BEFORE UPDATE OR DELETE ON log_revisions
+    FOR EACH ROW EXECUTE FUNCTION log_revisions_immutable();
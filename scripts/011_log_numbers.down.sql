@@ -0,0 +1,20 @@
+-- ===============================================
+-- 011_log_numbers.down.sql
+-- Внимание: номера записей пропадут; повторный up пронумерует
+-- записи заново по дате и может выдать другие номера
+-- ===============================================
+
+DROP INDEX IF EXISTS soglasheniya_reg_seq_uq;
+DROP INDEX IF EXISTS zaprosy_reg_seq_uq;
+
+ALTER TABLE soglasheniya
+    DROP COLUMN IF EXISTS reg_year,
+    DROP COLUMN IF EXISTS reg_seq,
+    DROP COLUMN IF EXISTS reg_number;
+
+ALTER TABLE zaprosy
+    DROP COLUMN IF EXISTS reg_year,
+    DROP COLUMN IF EXISTS reg_seq,
+    DROP COLUMN IF EXISTS reg_number;
+
+DROP TABLE IF EXISTS log_counters;
@@ -0,0 +1,72 @@
+-- ===============================================
+-- 011_log_numbers.up.sql
+-- Сквозная нумерация записей журналов по годам: ЭС-2026/0001
+-- ===============================================
+
+-- Счётчик номеров на тип записи и год. Номер выделяется в той же
+-- транзакции, что и вставка записи, поэтому неудачная вставка
+-- откатывает и счётчик — пропусков в нумерации нет.
+CREATE TABLE IF NOT EXISTS log_counters (
+    kind     TEXT NOT NULL CHECK (kind IN ('sogl', 'zapros')),
+    year     INT  NOT NULL,
+    last_seq INT  NOT NULL,
+    PRIMARY KEY (kind, year)
+);
+
+ALTER TABLE soglasheniya
+    ADD COLUMN IF NOT EXISTS reg_year   INT,
+    ADD COLUMN IF NOT EXISTS reg_seq    INT,
+    ADD COLUMN IF NOT EXISTS reg_number TEXT;
+
+ALTER TABLE zaprosy
+    ADD COLUMN IF NOT EXISTS reg_year   INT,
+    ADD COLUMN IF NOT EXISTS reg_seq    INT,
+    ADD COLUMN IF NOT EXISTS reg_number TEXT;
+
+-- Существующие записи нумеруются по году даты записи в порядке создания.
+-- Сам номер (reg_number) здесь не собирается: его по настроенному
+-- telegram.log_number_format проставляет бот при старте.
+WITH n AS (
+    SELECT id,
+           extract(year FROM date)::int AS y,
+           row_number() OVER (PARTITION BY extract(year FROM date) ORDER BY id) AS s
+    FROM soglasheniya
+    WHERE reg_seq IS NULL
+)
+UPDATE soglasheniya t
+SET reg_year = n.y, reg_seq = n.s
+FROM n
+WHERE t.id = n.id;
+
+WITH n AS (
+    SELECT id,
+           extract(year FROM date)::int AS y,
+           row_number() OVER (PARTITION BY extract(year FROM date) ORDER BY id) AS s
+    FROM zaprosy
+    WHERE reg_seq IS NULL
+)
+UPDATE zaprosy t
+SET reg_year = n.y, reg_seq = n.s
+FROM n
+WHERE t.id = n.id;
+
+INSERT INTO log_counters (kind, year, last_seq)
+SELECT 'sogl', reg_year, max(reg_seq) FROM soglasheniya GROUP BY reg_year
+ON CONFLICT (kind, year) DO UPDATE SET last_seq = GREATEST(log_counters.last_seq, EXCLUDED.last_seq);
+
+INSERT INTO log_counters (kind, year, last_seq)
+SELECT 'zapros', reg_year, max(reg_seq) FROM zaprosy GROUP BY reg_year
+ON CONFLICT (kind, year) DO UPDATE SET last_seq = GREATEST(log_counters.last_seq, EXCLUDED.last_seq);
+
+-- reg_number остаётся NULL до старта бота, поэтому NOT NULL на нём нет
+ALTER TABLE soglasheniya
+    ALTER COLUMN reg_year SET NOT NULL,
+    ALTER COLUMN reg_seq  SET NOT NULL;
+
+ALTER TABLE zaprosy
+    ALTER COLUMN reg_year SET NOT NULL,
+    ALTER COLUMN reg_seq  SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS soglasheniya_reg_seq_uq ON soglasheniya (reg_year, reg_seq);
+CREATE UNIQUE INDEX IF NOT EXISTS zaprosy_reg_seq_uq      ON zaprosy (reg_year, reg_seq);
+
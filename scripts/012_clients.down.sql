@@ -0,0 +1,13 @@
+-- ===============================================
+-- 012_clients.down.sql
+-- Текст доверителя остаётся в записях, теряются только ссылки
+-- и объединения, сделанные через /merge_clients
+-- ===============================================
+
+ALTER TABLE soglasheniya
+    DROP COLUMN IF EXISTS client_id;
+
+ALTER TABLE zaprosy
+    DROP COLUMN IF EXISTS client_id;
+
+DROP TABLE IF EXISTS clients;
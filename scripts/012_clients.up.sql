@@ -0,0 +1,58 @@
+-- ===============================================
+-- 012_clients.up.sql
+-- Реестр доверителей: записи журналов ссылаются на клиента,
+-- сохраняя текст доверителя в том виде, как его ввели
+-- ===============================================
+
+-- norm_name — ключ поиска дублей (см. domain.NormalizeClientName):
+-- нижний регистр, ё → е, без знаков препинания и ОПФ, слова по алфавиту.
+CREATE TABLE IF NOT EXISTS clients (
+    id          BIGSERIAL PRIMARY KEY,
+    name        TEXT NOT NULL,                 -- как ввели впервые
+    norm_name   TEXT NOT NULL UNIQUE,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Похожие названия для подсказок (pg_trgm подключён в 009)
+CREATE INDEX IF NOT EXISTS idx_clients_norm_name_trgm
+    ON clients USING gin (norm_name gin_trgm_ops);
+
+ALTER TABLE soglasheniya
+    ADD COLUMN IF NOT EXISTS client_id BIGINT REFERENCES clients(id) ON DELETE SET NULL;
+
+ALTER TABLE zaprosy
+    ADD COLUMN IF NOT EXISTS client_id BIGINT REFERENCES clients(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_soglasheniya_client ON soglasheniya (client_id);
+CREATE INDEX IF NOT EXISTS idx_zaprosy_client ON zaprosy (client_id);
+
+-- Перенос существующих доверителей. Та же нормализация, что и в коде;
+-- оставшиеся дубли администратор объединяет командой /merge_clients.
+CREATE OR REPLACE FUNCTION pg_temp.client_norm(s TEXT) RETURNS TEXT
+LANGUAGE sql IMMUTABLE AS $$
+    SELECT coalesce(string_agg(w, ' ' ORDER BY w COLLATE "C"), '')
+    FROM regexp_split_to_table(
+             regexp_replace(replace(lower(coalesce(s, '')), 'ё', 'е'), '[^0-9a-zа-я]+', ' ', 'g'),
+             ' ') AS w
+    WHERE w <> ''
+      AND w NOT IN ('ооо', 'оао', 'зао', 'пао', 'ао', 'ип', 'нко', 'ано', 'кфх', 'llc', 'ltd', 'inc')
+$$;
+
+INSERT INTO clients (name, norm_name)
+SELECT DISTINCT ON (n) doveritel, n
+FROM (
+    SELECT doveritel, created_at, pg_temp.client_norm(doveritel) AS n FROM soglasheniya
+    UNION ALL
+    SELECT doveritel, created_at, pg_temp.client_norm(doveritel) AS n FROM zaprosy
+) d
+WHERE n <> ''
+ORDER BY n, created_at
+ON CONFLICT (norm_name) DO NOTHING;
+
+UPDATE soglasheniya t SET client_id = c.id
+FROM clients c
+WHERE t.client_id IS NULL AND c.norm_name = pg_temp.client_norm(t.doveritel);
+
+UPDATE zaprosy t SET client_id = c.id
+FROM clients c
+WHERE t.client_id IS NULL AND c.norm_name = pg_temp.client_norm(t.doveritel);
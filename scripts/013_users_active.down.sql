@@ -0,0 +1,8 @@
+-- ===============================================
+-- 013_users_active.down.sql
+-- Отключённые адвокаты снова смогут создавать записи
+-- ===============================================
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS active,
+    DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,11 @@
+-- ===============================================
+-- 013_users_active.up.sql
+-- Управление адвокатами: отключение ушедших с сохранением их записей
+-- ===============================================
+
+-- Отключённый адвокат не может создавать записи, но его записи
+-- остаются в журналах и выгрузках. ФИО в записях — снимок на момент
+-- создания (user_name), правка users.fio их не меняет.
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS active     BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ;
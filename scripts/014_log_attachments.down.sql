@@ -0,0 +1,6 @@
+-- ===============================================
+-- 014_log_attachments.down.sql
+-- Файлы остаются в Telegram, теряются только ссылки на них
+-- ===============================================
+
+DROP TABLE IF EXISTS log_attachments;
@@ -0,0 +1,25 @@
+-- ===============================================
+-- 014_log_attachments.up.sql
+-- Сканы документов, приложенные к записям журналов
+-- ===============================================
+
+-- Сами файлы хранит Telegram: бот сохраняет только file_id, по которому
+-- файл можно отправить повторно, и сведения о файле для карточки.
+-- record_id указывает на soglasheniya или zaprosy в зависимости от kind.
+CREATE TABLE IF NOT EXISTS log_attachments (
+    id             BIGSERIAL PRIMARY KEY,
+    kind           TEXT   NOT NULL CHECK (kind IN ('sogl', 'zapros')),
+    record_id      BIGINT NOT NULL,
+    file_type      TEXT   NOT NULL CHECK (file_type IN ('document', 'photo')),
+    file_id        TEXT   NOT NULL,
+    file_unique_id TEXT   NOT NULL,             -- один и тот же файл не прикладывается дважды
+    file_name      TEXT   NOT NULL DEFAULT '',
+    mime_type      TEXT   NOT NULL DEFAULT '',
+    file_size      BIGINT NOT NULL DEFAULT 0,
+    uploaded_by    BIGINT NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (kind, record_id, file_unique_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_log_attachments_record
+    ON log_attachments (kind, record_id, id);
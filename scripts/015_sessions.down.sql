@@ -0,0 +1,6 @@
+-- ===============================================
+-- 015_sessions.down.sql
+-- Незавершённые диалоги пользователей будут потеряны
+-- ===============================================
+
+DROP TABLE IF EXISTS bot_sessions;
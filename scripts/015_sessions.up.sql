@@ -0,0 +1,19 @@
+-- ===============================================
+-- 015_sessions.up.sql
+-- Незавершённые диалоги пользователей переживают рестарт бота
+-- ===============================================
+
+-- data — состояние диалога (черновик брони или записи журнала) в JSON.
+-- Истёкшие сессии хранятся ещё сутки, чтобы бот мог ответить
+-- «сессия истекла», а не «сессия не найдена».
+CREATE TABLE IF NOT EXISTS bot_sessions (
+    kind        TEXT   NOT NULL CHECK (kind IN ('booking', 'logs')),
+    user_id     BIGINT NOT NULL,
+    data        JSONB  NOT NULL,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now(),
+    expires_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (kind, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bot_sessions_expires_at
+    ON bot_sessions (expires_at);
@@ -0,0 +1,11 @@
+-- ===============================================
+-- 016_flow_sessions.down.sql
+-- ===============================================
+
+DELETE FROM bot_sessions WHERE kind = 'flow';
+
+ALTER TABLE bot_sessions
+    DROP CONSTRAINT IF EXISTS bot_sessions_kind_check;
+
+ALTER TABLE bot_sessions
+    ADD CONSTRAINT bot_sessions_kind_check CHECK (kind IN ('booking', 'logs'));
@@ -0,0 +1,14 @@
+-- ===============================================
+-- 016_flow_sessions.up.sql
+-- Текущий диалог пользователя хранится отдельной сессией вида 'flow'
+-- ===============================================
+
+ALTER TABLE bot_sessions
+    DROP CONSTRAINT IF EXISTS bot_sessions_kind_check;
+
+ALTER TABLE bot_sessions
+    ADD CONSTRAINT bot_sessions_kind_check CHECK (kind IN ('booking', 'logs', 'flow'));
+
+-- Шаги диалогов теперь хранятся в сессии 'flow', старые черновики
+-- без неё продолжить нельзя.
+DELETE FROM bot_sessions;
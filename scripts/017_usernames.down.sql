@@ -0,0 +1,5 @@
+-- ===============================================
+-- 017_usernames.down.sql
+-- ===============================================
+
+DROP TABLE IF EXISTS tg_usernames;
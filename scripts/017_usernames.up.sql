@@ -0,0 +1,13 @@
+-- ===============================================
+-- 017_usernames.up.sql
+-- Актуальные @ники пользователей Telegram для упоминаний участников
+-- ===============================================
+
+-- Ник обновляется на каждом апдейте от пользователя. В Telegram ник
+-- в каждый момент принадлежит одному человеку, поэтому он уникален,
+-- а у пользователя — не больше одного ника.
+CREATE TABLE IF NOT EXISTS tg_usernames (
+    username    TEXT   PRIMARY KEY, -- в нижнем регистре, без @
+    user_id     BIGINT NOT NULL UNIQUE,
+    updated_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
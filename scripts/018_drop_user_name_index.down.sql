@@ -0,0 +1,6 @@
+-- ===============================================
+-- 018_drop_user_name_index.down.sql
+-- ===============================================
+
+CREATE INDEX IF NOT EXISTS idx_bookings_user_name_lower
+    ON bookings (lower(user_name));
@@ -0,0 +1,6 @@
+-- ===============================================
+-- 018_drop_user_name_index.up.sql
+-- Индекс для поиска по нику больше не нужен: ники хранятся в tg_usernames (017)
+-- ===============================================
+
+DROP INDEX IF EXISTS idx_bookings_user_name_lower;
@@ -0,0 +1,10 @@
+// Package scripts встраивает SQL-миграции в бинарь бота.
+//
+// Файлы называются NNN_name.up.sql и NNN_name.down.sql, где NNN — номер
+// версии схемы. Применяет их db.Migrator при старте и команда migrate.
+package scripts
+
+import "embed"
+
+//go:embed *.sql
+var Migrations embed.FS